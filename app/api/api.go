@@ -1,4 +1,162 @@
+// Package api provides a management interface for controlling V2Ray at runtime.
+//
+// The API server listens on a local TCP port and serves HTTP requests under a versioned path, such as
+// "/v1/inbound/add". Both request and response bodies are serialized protobuf messages defined in this package.
 package api
 
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/observatory"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	v2net "v2ray.com/core/common/net"
+)
+
+const (
+	// APIVersion is the path prefix of all requests served by the API server.
+	APIVersion = "/v1"
+
+	contentType = "application/x-protobuf"
+)
+
 type ApiServer struct {
+	ctx      context.Context
+	config   *Config
+	ihm      proxyman.InboundHandlerManager
+	ohm      proxyman.OutboundHandlerManager
+	stats    *stats.Manager
+	observer *observatory.Observatory
+	router   *router.Router
+	listener net.Listener
+	server   *http.Server
+}
+
+func NewApiServer(ctx context.Context, config *Config) (*ApiServer, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, errors.New("API: No space in context.")
+	}
+	s := &ApiServer{
+		ctx:    ctx,
+		config: config,
+	}
+	space.OnInitialize(func() error {
+		s.ihm = proxyman.InboundHandlerManagerFromSpace(space)
+		if s.ihm == nil {
+			return errors.New("API: InboundHandlerManager is not found in the space.")
+		}
+		s.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
+		if s.ohm == nil {
+			return errors.New("API: OutboundHandlerManager is not found in the space.")
+		}
+		s.stats = stats.FromSpace(space)
+		s.observer = observatory.FromSpace(space)
+		s.router = router.FromSpace(space)
+		return nil
+	})
+	return s, nil
+}
+
+func (*ApiServer) Interface() interface{} {
+	return (*ApiServer)(nil)
+}
+
+// Start starts listening on the configured port.
+func (s *ApiServer) Start() error {
+	address := s.config.Listen.AsAddress()
+	if address == nil {
+		address = v2net.LocalHostIP
+	}
+	dest := v2net.TCPDestination(address, v2net.Port(s.config.DirectPort))
+	listener, err := net.Listen("tcp", dest.NetAddr())
+	if err != nil {
+		return errors.Base(err).Message("API: Failed to listen on ", dest)
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	s.registerHandlers(mux)
+	s.server = &http.Server{
+		Handler: mux,
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Warning("API: Server stopped: ", err)
+		}
+	}()
+	log.Info("API: Listening on ", dest)
+	return nil
+}
+
+// Close stops the API server. Requests in progress are interrupted.
+func (s *ApiServer) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// Addr returns the address that the API server is listening on, or nil if the server is not started.
+func (s *ApiServer) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+type requestHandler func(request proto.Message) (proto.Message, error)
+
+func serve(newRequest func() proto.Message, handler requestHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "API: Method not allowed.", http.StatusMethodNotAllowed)
+			return
+		}
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		request := newRequest()
+		if err := proto.Unmarshal(payload, request); err != nil {
+			http.Error(w, "API: Invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		response, err := handler(request)
+		if err != nil {
+			log.Info("API: Failed to process request ", r.URL.Path, ": ", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := proto.Marshal(response)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+	})
+}
+
+func FromSpace(space app.Space) *ApiServer {
+	app := space.GetApplication((*ApiServer)(nil))
+	if app == nil {
+		return nil
+	}
+	return app.(*ApiServer)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewApiServer(ctx, config.(*Config))
+	}))
 }
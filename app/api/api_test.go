@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app"
+	. "v2ray.com/core/app/api"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func call(server *ApiServer, path string, request proto.Message, response proto.Message) error {
+	data, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post("http://"+server.Addr().String()+APIVersion+path, "application/x-protobuf", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	payload, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(string(payload))
+	}
+	return proto.Unmarshal(payload, response)
+}
+
+func TestOutboundManagement(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.InboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(Config))).IsNil()
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(space)
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "default",
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+	})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	server := FromSpace(space)
+	assert.Error(server.Start()).IsNil()
+	defer server.Close()
+
+	assert.Error(call(server, "/outbound/add", &AddOutboundRequest{
+		Outbound: &proxyman.OutboundHandlerConfig{
+			Tag:           "test",
+			ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+		},
+	}, new(AddOutboundResponse))).IsNil()
+	assert.Bool(ohm.GetHandler("test") != nil).IsTrue()
+
+	listResp := new(ListOutboundResponse)
+	assert.Error(call(server, "/outbound/list", new(ListOutboundRequest), listResp)).IsNil()
+	assert.Int(len(listResp.Outbound)).Equals(2)
+	assert.String(listResp.Outbound[1].Tag).Equals("test")
+
+	assert.Error(call(server, "/outbound/remove", &RemoveOutboundRequest{Tag: "test"}, new(RemoveOutboundResponse))).IsNil()
+	assert.Bool(ohm.GetHandler("test") == nil).IsTrue()
+
+	assert.Error(call(server, "/outbound/remove", &RemoveOutboundRequest{Tag: "test"}, new(RemoveOutboundResponse))).IsNotNil()
+}
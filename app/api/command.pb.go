@@ -0,0 +1,662 @@
+package api
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_app_observatory "v2ray.com/core/app/observatory"
+import v2ray_core_app_proxyman "v2ray.com/core/app/proxyman"
+import v2ray_core_app_router "v2ray.com/core/app/router"
+import v2ray_core_common_protocol "v2ray.com/core/common/protocol"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListInboundRequest struct {
+}
+
+func (m *ListInboundRequest) Reset()                    { *m = ListInboundRequest{} }
+func (m *ListInboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListInboundRequest) ProtoMessage()               {}
+func (*ListInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type ListInboundResponse struct {
+	Inbound []*v2ray_core_app_proxyman.InboundHandlerConfig `protobuf:"bytes,1,rep,name=inbound" json:"inbound,omitempty"`
+}
+
+func (m *ListInboundResponse) Reset()                    { *m = ListInboundResponse{} }
+func (m *ListInboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListInboundResponse) ProtoMessage()               {}
+func (*ListInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ListInboundResponse) GetInbound() []*v2ray_core_app_proxyman.InboundHandlerConfig {
+	if m != nil {
+		return m.Inbound
+	}
+	return nil
+}
+
+type AddInboundRequest struct {
+	// Tag of the inbound handler must not be empty.
+	Inbound *v2ray_core_app_proxyman.InboundHandlerConfig `protobuf:"bytes,1,opt,name=inbound" json:"inbound,omitempty"`
+}
+
+func (m *AddInboundRequest) Reset()                    { *m = AddInboundRequest{} }
+func (m *AddInboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddInboundRequest) ProtoMessage()               {}
+func (*AddInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *AddInboundRequest) GetInbound() *v2ray_core_app_proxyman.InboundHandlerConfig {
+	if m != nil {
+		return m.Inbound
+	}
+	return nil
+}
+
+type AddInboundResponse struct {
+}
+
+func (m *AddInboundResponse) Reset()                    { *m = AddInboundResponse{} }
+func (m *AddInboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddInboundResponse) ProtoMessage()               {}
+func (*AddInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type RemoveInboundRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *RemoveInboundRequest) Reset()                    { *m = RemoveInboundRequest{} }
+func (m *RemoveInboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveInboundRequest) ProtoMessage()               {}
+func (*RemoveInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *RemoveInboundRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+type RemoveInboundResponse struct {
+}
+
+func (m *RemoveInboundResponse) Reset()                    { *m = RemoveInboundResponse{} }
+func (m *RemoveInboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveInboundResponse) ProtoMessage()               {}
+func (*RemoveInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type AddUserRequest struct {
+	// Tag of the inbound handler.
+	Tag  string                           `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+	User *v2ray_core_common_protocol.User `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *AddUserRequest) Reset()                    { *m = AddUserRequest{} }
+func (m *AddUserRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddUserRequest) ProtoMessage()               {}
+func (*AddUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *AddUserRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *AddUserRequest) GetUser() *v2ray_core_common_protocol.User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+type AddUserResponse struct {
+}
+
+func (m *AddUserResponse) Reset()                    { *m = AddUserResponse{} }
+func (m *AddUserResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddUserResponse) ProtoMessage()               {}
+func (*AddUserResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+type RemoveUserRequest struct {
+	// Tag of the inbound handler.
+	Tag   string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+	Email string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	// Deprecated: existing connections of the user are always closed.
+	CloseSessions bool `protobuf:"varint,3,opt,name=close_sessions,json=closeSessions" json:"close_sessions,omitempty"`
+}
+
+func (m *RemoveUserRequest) Reset()                    { *m = RemoveUserRequest{} }
+func (m *RemoveUserRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveUserRequest) ProtoMessage()               {}
+func (*RemoveUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *RemoveUserRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *RemoveUserRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *RemoveUserRequest) GetCloseSessions() bool {
+	if m != nil {
+		return m.CloseSessions
+	}
+	return false
+}
+
+type RemoveUserResponse struct {
+}
+
+func (m *RemoveUserResponse) Reset()                    { *m = RemoveUserResponse{} }
+func (m *RemoveUserResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveUserResponse) ProtoMessage()               {}
+func (*RemoveUserResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+type ListOutboundRequest struct {
+}
+
+func (m *ListOutboundRequest) Reset()                    { *m = ListOutboundRequest{} }
+func (m *ListOutboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListOutboundRequest) ProtoMessage()               {}
+func (*ListOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+type ListOutboundResponse struct {
+	Outbound []*v2ray_core_app_proxyman.OutboundHandlerConfig `protobuf:"bytes,1,rep,name=outbound" json:"outbound,omitempty"`
+}
+
+func (m *ListOutboundResponse) Reset()                    { *m = ListOutboundResponse{} }
+func (m *ListOutboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListOutboundResponse) ProtoMessage()               {}
+func (*ListOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *ListOutboundResponse) GetOutbound() []*v2ray_core_app_proxyman.OutboundHandlerConfig {
+	if m != nil {
+		return m.Outbound
+	}
+	return nil
+}
+
+type AddOutboundRequest struct {
+	// Tag of the outbound handler must not be empty.
+	Outbound *v2ray_core_app_proxyman.OutboundHandlerConfig `protobuf:"bytes,1,opt,name=outbound" json:"outbound,omitempty"`
+}
+
+func (m *AddOutboundRequest) Reset()                    { *m = AddOutboundRequest{} }
+func (m *AddOutboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddOutboundRequest) ProtoMessage()               {}
+func (*AddOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *AddOutboundRequest) GetOutbound() *v2ray_core_app_proxyman.OutboundHandlerConfig {
+	if m != nil {
+		return m.Outbound
+	}
+	return nil
+}
+
+type AddOutboundResponse struct {
+}
+
+func (m *AddOutboundResponse) Reset()                    { *m = AddOutboundResponse{} }
+func (m *AddOutboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddOutboundResponse) ProtoMessage()               {}
+func (*AddOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+type RemoveOutboundRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *RemoveOutboundRequest) Reset()                    { *m = RemoveOutboundRequest{} }
+func (m *RemoveOutboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveOutboundRequest) ProtoMessage()               {}
+func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *RemoveOutboundRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+type RemoveOutboundResponse struct {
+}
+
+func (m *RemoveOutboundResponse) Reset()                    { *m = RemoveOutboundResponse{} }
+func (m *RemoveOutboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveOutboundResponse) ProtoMessage()               {}
+func (*RemoveOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+type Stat struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value int64  `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Stat) Reset()                    { *m = Stat{} }
+func (m *Stat) String() string            { return proto.CompactTextString(m) }
+func (*Stat) ProtoMessage()               {}
+func (*Stat) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *Stat) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Stat) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type GetStatsRequest struct {
+	// Name of the counter.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Whether or not to reset the counter after reading its value.
+	Reset_ bool `protobuf:"varint,2,opt,name=reset" json:"reset,omitempty"`
+}
+
+func (m *GetStatsRequest) Reset()                    { *m = GetStatsRequest{} }
+func (m *GetStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetStatsRequest) ProtoMessage()               {}
+func (*GetStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *GetStatsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetStatsRequest) GetReset_() bool {
+	if m != nil {
+		return m.Reset_
+	}
+	return false
+}
+
+type GetStatsResponse struct {
+	Stat *Stat `protobuf:"bytes,1,opt,name=stat" json:"stat,omitempty"`
+}
+
+func (m *GetStatsResponse) Reset()                    { *m = GetStatsResponse{} }
+func (m *GetStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetStatsResponse) ProtoMessage()               {}
+func (*GetStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *GetStatsResponse) GetStat() *Stat {
+	if m != nil {
+		return m.Stat
+	}
+	return nil
+}
+
+type QueryStatsRequest struct {
+	// Counters whose name contains the pattern are returned. An empty pattern matches all counters.
+	Pattern string `protobuf:"bytes,1,opt,name=pattern" json:"pattern,omitempty"`
+	Reset_  bool   `protobuf:"varint,2,opt,name=reset" json:"reset,omitempty"`
+}
+
+func (m *QueryStatsRequest) Reset()                    { *m = QueryStatsRequest{} }
+func (m *QueryStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryStatsRequest) ProtoMessage()               {}
+func (*QueryStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *QueryStatsRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *QueryStatsRequest) GetReset_() bool {
+	if m != nil {
+		return m.Reset_
+	}
+	return false
+}
+
+type QueryStatsResponse struct {
+	Stat []*Stat `protobuf:"bytes,1,rep,name=stat" json:"stat,omitempty"`
+}
+
+func (m *QueryStatsResponse) Reset()                    { *m = QueryStatsResponse{} }
+func (m *QueryStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryStatsResponse) ProtoMessage()               {}
+func (*QueryStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *QueryStatsResponse) GetStat() []*Stat {
+	if m != nil {
+		return m.Stat
+	}
+	return nil
+}
+
+type GetOutboundStatusRequest struct {
+	// Tag of the outbound handler. Results of all probed handlers are returned if empty.
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *GetOutboundStatusRequest) Reset()                    { *m = GetOutboundStatusRequest{} }
+func (m *GetOutboundStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetOutboundStatusRequest) ProtoMessage()               {}
+func (*GetOutboundStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *GetOutboundStatusRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+type GetOutboundStatusResponse struct {
+	Status []*v2ray_core_app_observatory.OutboundStatus `protobuf:"bytes,1,rep,name=status" json:"status,omitempty"`
+}
+
+func (m *GetOutboundStatusResponse) Reset()                    { *m = GetOutboundStatusResponse{} }
+func (m *GetOutboundStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetOutboundStatusResponse) ProtoMessage()               {}
+func (*GetOutboundStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *GetOutboundStatusResponse) GetStatus() []*v2ray_core_app_observatory.OutboundStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type ReloadGeoDataRequest struct {
+}
+
+func (m *ReloadGeoDataRequest) Reset()                    { *m = ReloadGeoDataRequest{} }
+func (m *ReloadGeoDataRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReloadGeoDataRequest) ProtoMessage()               {}
+func (*ReloadGeoDataRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+type ReloadGeoDataResponse struct {
+	// Whether any GeoIP or GeoSite file changed and was reloaded.
+	Reloaded bool `protobuf:"varint,1,opt,name=reloaded" json:"reloaded,omitempty"`
+}
+
+func (m *ReloadGeoDataResponse) Reset()                    { *m = ReloadGeoDataResponse{} }
+func (m *ReloadGeoDataResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReloadGeoDataResponse) ProtoMessage()               {}
+func (*ReloadGeoDataResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *ReloadGeoDataResponse) GetReloaded() bool {
+	if m != nil {
+		return m.Reloaded
+	}
+	return false
+}
+
+type TestRouteRequest struct {
+	Request *v2ray_core_app_router.RouteRequest `protobuf:"bytes,1,opt,name=request" json:"request,omitempty"`
+}
+
+func (m *TestRouteRequest) Reset()                    { *m = TestRouteRequest{} }
+func (m *TestRouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*TestRouteRequest) ProtoMessage()               {}
+func (*TestRouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *TestRouteRequest) GetRequest() *v2ray_core_app_router.RouteRequest {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+type TestRouteResponse struct {
+	// If no rule matches the connection, rule_index is -1 and the default outbound handler is used.
+	Explanation *v2ray_core_app_router.RouteExplanation `protobuf:"bytes,1,opt,name=explanation" json:"explanation,omitempty"`
+}
+
+func (m *TestRouteResponse) Reset()                    { *m = TestRouteResponse{} }
+func (m *TestRouteResponse) String() string            { return proto.CompactTextString(m) }
+func (*TestRouteResponse) ProtoMessage()               {}
+func (*TestRouteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *TestRouteResponse) GetExplanation() *v2ray_core_app_router.RouteExplanation {
+	if m != nil {
+		return m.Explanation
+	}
+	return nil
+}
+
+type ListRulesRequest struct {
+	// Whether or not to reset the hit counters after reading them.
+	Reset_ bool `protobuf:"varint,1,opt,name=reset" json:"reset,omitempty"`
+}
+
+func (m *ListRulesRequest) Reset()                    { *m = ListRulesRequest{} }
+func (m *ListRulesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRulesRequest) ProtoMessage()               {}
+func (*ListRulesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *ListRulesRequest) GetReset_() bool {
+	if m != nil {
+		return m.Reset_
+	}
+	return false
+}
+
+type ListRulesResponse struct {
+	Rule []*v2ray_core_app_router.RuleStats `protobuf:"bytes,1,rep,name=rule" json:"rule,omitempty"`
+	// Number of connections that matched no rule, and went to the default outbound handler.
+	DefaultHits int64 `protobuf:"varint,2,opt,name=default_hits,json=defaultHits" json:"default_hits,omitempty"`
+}
+
+func (m *ListRulesResponse) Reset()                    { *m = ListRulesResponse{} }
+func (m *ListRulesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListRulesResponse) ProtoMessage()               {}
+func (*ListRulesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *ListRulesResponse) GetRule() []*v2ray_core_app_router.RuleStats {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+func (m *ListRulesResponse) GetDefaultHits() int64 {
+	if m != nil {
+		return m.DefaultHits
+	}
+	return 0
+}
+
+// Rules changed by AddRule, RemoveRule and ReplaceRules are discarded when the config is reloaded.
+type AddRuleRequest struct {
+	// Tag of the rule must not be empty.
+	Rule *v2ray_core_app_router.RoutingRule `protobuf:"bytes,1,opt,name=rule" json:"rule,omitempty"`
+	// The rule is inserted before the rule at this index. It is appended if the index is negative or out of range.
+	Index int32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+}
+
+func (m *AddRuleRequest) Reset()                    { *m = AddRuleRequest{} }
+func (m *AddRuleRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddRuleRequest) ProtoMessage()               {}
+func (*AddRuleRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *AddRuleRequest) GetRule() *v2ray_core_app_router.RoutingRule {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+func (m *AddRuleRequest) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type AddRuleResponse struct {
+}
+
+func (m *AddRuleResponse) Reset()                    { *m = AddRuleResponse{} }
+func (m *AddRuleResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddRuleResponse) ProtoMessage()               {}
+func (*AddRuleResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+type RemoveRuleRequest struct {
+	RuleTag string `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag" json:"rule_tag,omitempty"`
+}
+
+func (m *RemoveRuleRequest) Reset()                    { *m = RemoveRuleRequest{} }
+func (m *RemoveRuleRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveRuleRequest) ProtoMessage()               {}
+func (*RemoveRuleRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *RemoveRuleRequest) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+type RemoveRuleResponse struct {
+}
+
+func (m *RemoveRuleResponse) Reset()                    { *m = RemoveRuleResponse{} }
+func (m *RemoveRuleResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveRuleResponse) ProtoMessage()               {}
+func (*RemoveRuleResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+type ReplaceRulesRequest struct {
+	// All rules of the router are replaced by these rules at once.
+	Rule []*v2ray_core_app_router.RoutingRule `protobuf:"bytes,1,rep,name=rule" json:"rule,omitempty"`
+}
+
+func (m *ReplaceRulesRequest) Reset()                    { *m = ReplaceRulesRequest{} }
+func (m *ReplaceRulesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReplaceRulesRequest) ProtoMessage()               {}
+func (*ReplaceRulesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *ReplaceRulesRequest) GetRule() []*v2ray_core_app_router.RoutingRule {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+type ReplaceRulesResponse struct {
+}
+
+func (m *ReplaceRulesResponse) Reset()                    { *m = ReplaceRulesResponse{} }
+func (m *ReplaceRulesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReplaceRulesResponse) ProtoMessage()               {}
+func (*ReplaceRulesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func init() {
+	proto.RegisterType((*ListInboundRequest)(nil), "v2ray.core.app.api.ListInboundRequest")
+	proto.RegisterType((*ListInboundResponse)(nil), "v2ray.core.app.api.ListInboundResponse")
+	proto.RegisterType((*AddInboundRequest)(nil), "v2ray.core.app.api.AddInboundRequest")
+	proto.RegisterType((*AddInboundResponse)(nil), "v2ray.core.app.api.AddInboundResponse")
+	proto.RegisterType((*RemoveInboundRequest)(nil), "v2ray.core.app.api.RemoveInboundRequest")
+	proto.RegisterType((*RemoveInboundResponse)(nil), "v2ray.core.app.api.RemoveInboundResponse")
+	proto.RegisterType((*AddUserRequest)(nil), "v2ray.core.app.api.AddUserRequest")
+	proto.RegisterType((*AddUserResponse)(nil), "v2ray.core.app.api.AddUserResponse")
+	proto.RegisterType((*RemoveUserRequest)(nil), "v2ray.core.app.api.RemoveUserRequest")
+	proto.RegisterType((*RemoveUserResponse)(nil), "v2ray.core.app.api.RemoveUserResponse")
+	proto.RegisterType((*ListOutboundRequest)(nil), "v2ray.core.app.api.ListOutboundRequest")
+	proto.RegisterType((*ListOutboundResponse)(nil), "v2ray.core.app.api.ListOutboundResponse")
+	proto.RegisterType((*AddOutboundRequest)(nil), "v2ray.core.app.api.AddOutboundRequest")
+	proto.RegisterType((*AddOutboundResponse)(nil), "v2ray.core.app.api.AddOutboundResponse")
+	proto.RegisterType((*RemoveOutboundRequest)(nil), "v2ray.core.app.api.RemoveOutboundRequest")
+	proto.RegisterType((*RemoveOutboundResponse)(nil), "v2ray.core.app.api.RemoveOutboundResponse")
+	proto.RegisterType((*Stat)(nil), "v2ray.core.app.api.Stat")
+	proto.RegisterType((*GetStatsRequest)(nil), "v2ray.core.app.api.GetStatsRequest")
+	proto.RegisterType((*GetStatsResponse)(nil), "v2ray.core.app.api.GetStatsResponse")
+	proto.RegisterType((*QueryStatsRequest)(nil), "v2ray.core.app.api.QueryStatsRequest")
+	proto.RegisterType((*QueryStatsResponse)(nil), "v2ray.core.app.api.QueryStatsResponse")
+	proto.RegisterType((*GetOutboundStatusRequest)(nil), "v2ray.core.app.api.GetOutboundStatusRequest")
+	proto.RegisterType((*GetOutboundStatusResponse)(nil), "v2ray.core.app.api.GetOutboundStatusResponse")
+	proto.RegisterType((*ReloadGeoDataRequest)(nil), "v2ray.core.app.api.ReloadGeoDataRequest")
+	proto.RegisterType((*ReloadGeoDataResponse)(nil), "v2ray.core.app.api.ReloadGeoDataResponse")
+	proto.RegisterType((*TestRouteRequest)(nil), "v2ray.core.app.api.TestRouteRequest")
+	proto.RegisterType((*TestRouteResponse)(nil), "v2ray.core.app.api.TestRouteResponse")
+	proto.RegisterType((*ListRulesRequest)(nil), "v2ray.core.app.api.ListRulesRequest")
+	proto.RegisterType((*ListRulesResponse)(nil), "v2ray.core.app.api.ListRulesResponse")
+	proto.RegisterType((*AddRuleRequest)(nil), "v2ray.core.app.api.AddRuleRequest")
+	proto.RegisterType((*AddRuleResponse)(nil), "v2ray.core.app.api.AddRuleResponse")
+	proto.RegisterType((*RemoveRuleRequest)(nil), "v2ray.core.app.api.RemoveRuleRequest")
+	proto.RegisterType((*RemoveRuleResponse)(nil), "v2ray.core.app.api.RemoveRuleResponse")
+	proto.RegisterType((*ReplaceRulesRequest)(nil), "v2ray.core.app.api.ReplaceRulesRequest")
+	proto.RegisterType((*ReplaceRulesResponse)(nil), "v2ray.core.app.api.ReplaceRulesResponse")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/api/command.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 834 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x56, 0x6d, 0x6f, 0xdb, 0x36,
+	0x10, 0x86, 0x6b, 0xa7, 0x71, 0x2f, 0x5d, 0x1b, 0x2b, 0x4e, 0xe6, 0xe6, 0x53, 0xc6, 0xbd, 0xb9,
+	0x5b, 0x47, 0x0f, 0x69, 0xd1, 0x2f, 0xc3, 0x80, 0xc6, 0xde, 0xe0, 0x76, 0xd8, 0xb0, 0x95, 0xed,
+	0x86, 0x61, 0x18, 0xe2, 0xd1, 0xd2, 0xd5, 0x13, 0x20, 0x89, 0x1c, 0x49, 0x19, 0xf1, 0x5f, 0xda,
+	0xaf, 0x2c, 0x44, 0x52, 0xb6, 0xa4, 0xd8, 0x41, 0x81, 0x7c, 0x33, 0xa9, 0x7b, 0x5e, 0xc8, 0xe7,
+	0x78, 0x09, 0x7c, 0xb6, 0x3c, 0x57, 0x7c, 0x45, 0x43, 0x91, 0x8e, 0x42, 0xa1, 0x70, 0xc4, 0xa5,
+	0x1c, 0x71, 0x19, 0x8f, 0x42, 0x91, 0xa6, 0x3c, 0x8b, 0xa8, 0x54, 0xc2, 0x88, 0x20, 0x28, 0xab,
+	0x14, 0x52, 0x2e, 0x25, 0xe5, 0x32, 0x3e, 0xfd, 0x7a, 0x0b, 0x52, 0xcc, 0x35, 0xaa, 0x25, 0x37,
+	0x42, 0xad, 0x46, 0xa1, 0xc8, 0xde, 0xc5, 0x0b, 0x47, 0x70, 0x3a, 0xdc, 0x52, 0x2c, 0x95, 0xb8,
+	0x5a, 0xa5, 0x3c, 0xab, 0x57, 0x7e, 0xb1, 0xa5, 0x52, 0x89, 0xdc, 0xa0, 0xaa, 0xd7, 0x3d, 0x6e,
+	0xd4, 0x15, 0x86, 0x45, 0x36, 0xb2, 0x1f, 0x43, 0x91, 0x8c, 0x72, 0x8d, 0xca, 0x95, 0x92, 0x3e,
+	0x04, 0x3f, 0xc7, 0xda, 0xbc, 0xca, 0xe6, 0x22, 0xcf, 0x22, 0x86, 0xff, 0xe5, 0xa8, 0x0d, 0xb9,
+	0x84, 0xa3, 0xda, 0xae, 0x96, 0x22, 0xd3, 0x18, 0x4c, 0x61, 0x3f, 0x76, 0x5b, 0x83, 0xd6, 0x59,
+	0x7b, 0x78, 0x70, 0xfe, 0x0d, 0x6d, 0x1c, 0xbe, 0xf4, 0x4d, 0x3d, 0xf4, 0x25, 0xcf, 0xa2, 0x04,
+	0xd5, 0xc4, 0xba, 0x63, 0x25, 0x9a, 0xfc, 0x0d, 0xbd, 0x8b, 0x28, 0xaa, 0x8b, 0xd6, 0xd9, 0x5b,
+	0xb7, 0x60, 0xef, 0x43, 0x50, 0x65, 0x77, 0xe6, 0xc9, 0x10, 0xfa, 0x0c, 0x53, 0xb1, 0xc4, 0x86,
+	0xec, 0x21, 0xb4, 0x0d, 0x5f, 0x58, 0xc9, 0x7b, 0xac, 0xf8, 0x49, 0x3e, 0x86, 0xe3, 0x46, 0xa5,
+	0xa7, 0xf8, 0x13, 0x1e, 0x5c, 0x44, 0xd1, 0xef, 0x1a, 0xd5, 0x4e, 0x70, 0xf0, 0x0c, 0x3a, 0xc5,
+	0xf5, 0x0e, 0xee, 0xd8, 0x23, 0x9c, 0x55, 0x8f, 0xe0, 0x62, 0xa0, 0x65, 0x0c, 0xd4, 0x12, 0xd9,
+	0x6a, 0xd2, 0x83, 0x87, 0x6b, 0x66, 0x2f, 0x36, 0x87, 0x9e, 0x73, 0x71, 0xb3, 0x5e, 0x1f, 0xf6,
+	0x30, 0xe5, 0x71, 0x62, 0x05, 0xef, 0x31, 0xb7, 0x08, 0x3e, 0x87, 0x07, 0x61, 0x22, 0x34, 0xce,
+	0x34, 0x6a, 0x1d, 0x8b, 0x4c, 0x0f, 0xda, 0x67, 0xad, 0x61, 0x97, 0x7d, 0x64, 0x77, 0xdf, 0xf8,
+	0xcd, 0xe2, 0xa6, 0xaa, 0x1a, 0x5e, 0xf9, 0xd8, 0xa5, 0xff, 0x6b, 0x6e, 0x6a, 0x4d, 0x31, 0x87,
+	0x7e, 0x7d, 0xdb, 0x77, 0xc5, 0x4f, 0xd0, 0x15, 0xb9, 0xa9, 0xb6, 0x05, 0xdd, 0x19, 0x5c, 0x09,
+	0xae, 0x27, 0xb7, 0xc6, 0x93, 0x7f, 0x6c, 0x74, 0x0d, 0xe5, 0x86, 0x42, 0xeb, 0x56, 0x0a, 0xc7,
+	0x70, 0x54, 0x53, 0xf0, 0x67, 0x7e, 0x5c, 0x66, 0xde, 0xd4, 0xbe, 0xde, 0x1e, 0x03, 0x38, 0x69,
+	0x96, 0x7a, 0x92, 0x6f, 0xa1, 0xf3, 0xc6, 0x70, 0x13, 0x04, 0xd0, 0xc9, 0x78, 0x8a, 0x1e, 0x64,
+	0x7f, 0x17, 0x39, 0x2d, 0x79, 0x92, 0xa3, 0xcd, 0xa9, 0xcd, 0xdc, 0x82, 0x7c, 0x07, 0x0f, 0xa7,
+	0x68, 0x0a, 0x90, 0x2e, 0x05, 0x77, 0x80, 0x15, 0x6a, 0x34, 0x16, 0xdc, 0x65, 0x6e, 0x41, 0x5e,
+	0xc0, 0xe1, 0x06, 0xec, 0xc3, 0x78, 0x02, 0x1d, 0x6d, 0xb8, 0xf1, 0xd7, 0x34, 0xa0, 0xd7, 0x87,
+	0x13, 0x2d, 0x00, 0xcc, 0x56, 0x91, 0x09, 0xf4, 0x5e, 0xe7, 0xa8, 0x56, 0x35, 0x03, 0x03, 0xd8,
+	0x97, 0xdc, 0x18, 0x54, 0x99, 0xf7, 0x50, 0x2e, 0x77, 0xd8, 0x18, 0x43, 0x50, 0x25, 0xb9, 0x66,
+	0xa4, 0xfd, 0x01, 0x46, 0x9e, 0xc0, 0x60, 0x8a, 0xeb, 0xd6, 0x2a, 0x3e, 0xe4, 0x7a, 0x77, 0x02,
+	0x33, 0x78, 0xb4, 0xa5, 0xda, 0x0b, 0x8f, 0xe1, 0xae, 0xb6, 0x3b, 0x5e, 0xfa, 0xab, 0xa6, 0x74,
+	0x65, 0x10, 0xd3, 0x06, 0x87, 0x47, 0x92, 0x93, 0x62, 0x56, 0x24, 0x82, 0x47, 0x53, 0x14, 0x3f,
+	0x70, 0xc3, 0xcb, 0x27, 0xf0, 0x14, 0x8e, 0x1b, 0xfb, 0x5e, 0xf4, 0x14, 0xba, 0xca, 0x7e, 0x40,
+	0xd7, 0xa1, 0x5d, 0xb6, 0x5e, 0x93, 0xd7, 0x70, 0xf8, 0x16, 0xb5, 0x61, 0x22, 0x37, 0x58, 0x9e,
+	0xe9, 0x7b, 0xd8, 0x57, 0xee, 0xa7, 0x4f, 0xea, 0xd3, 0xa6, 0x4b, 0x37, 0xd7, 0x69, 0x15, 0xc5,
+	0x4a, 0x0c, 0xb9, 0x84, 0x5e, 0x85, 0xd2, 0x7b, 0x78, 0x05, 0x07, 0x78, 0x25, 0x13, 0x9e, 0x71,
+	0x13, 0x8b, 0xcc, 0xf3, 0x7e, 0x79, 0x13, 0xef, 0x8f, 0x9b, 0x72, 0x56, 0xc5, 0x92, 0x21, 0x1c,
+	0x16, 0x4f, 0x9d, 0xe5, 0x09, 0xae, 0x63, 0x58, 0x87, 0xdf, 0xaa, 0x86, 0x9f, 0x40, 0xaf, 0x52,
+	0xe9, 0x9d, 0x3c, 0x83, 0x8e, 0xca, 0x13, 0xf4, 0x01, 0x9c, 0xed, 0xb2, 0x90, 0x27, 0xe8, 0x7a,
+	0xc6, 0x56, 0x07, 0x9f, 0xc0, 0xfd, 0x08, 0xdf, 0xf1, 0x3c, 0x31, 0xb3, 0x7f, 0x63, 0xa3, 0xfd,
+	0x43, 0x39, 0xf0, 0x7b, 0x2f, 0x63, 0xa3, 0xc9, 0xa5, 0x1d, 0xc0, 0x05, 0xb0, 0x74, 0xf5, 0x7c,
+	0x2d, 0x55, 0x9c, 0x96, 0xdc, 0x70, 0xda, 0x38, 0x5b, 0x58, 0xa0, 0x13, 0xeb, 0xc3, 0x5e, 0x9c,
+	0x45, 0x78, 0x65, 0x55, 0xf6, 0x98, 0x5b, 0xf8, 0x31, 0xec, 0xf8, 0xfd, 0x9b, 0xa6, 0xe5, 0x18,
+	0xae, 0xaa, 0x3e, 0x82, 0x6e, 0xc1, 0x32, 0xdb, 0xf4, 0xe5, 0x7e, 0xb1, 0x7e, 0xcb, 0x17, 0x9b,
+	0x91, 0x5a, 0x63, 0xf9, 0x05, 0x8e, 0x18, 0xca, 0x84, 0x87, 0x58, 0xbb, 0xd3, 0xe7, 0xb5, 0x8b,
+	0xfa, 0x60, 0xf7, 0xae, 0x3f, 0xab, 0x74, 0x4e, 0x66, 0xfc, 0x02, 0x4e, 0x42, 0x91, 0x6e, 0x79,
+	0x6b, 0xe3, 0xfb, 0x13, 0xf7, 0x4f, 0xcb, 0x6f, 0x4a, 0x18, 0xf1, 0x57, 0x9b, 0xcb, 0xf8, 0xff,
+	0x3b, 0xc1, 0x1f, 0xe7, 0x8c, 0xaf, 0xe8, 0xa4, 0xa8, 0xbb, 0x90, 0x92, 0x5e, 0xc8, 0x78, 0x7e,
+	0xd7, 0xfe, 0x75, 0x7a, 0xfa, 0x3e, 0x00, 0x00, 0xff, 0xff, 0x60, 0xb1, 0xa3, 0x55, 0xfc, 0x08,
+	0x00, 0x00,
+}
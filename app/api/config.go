@@ -1,9 +0,0 @@
-package api
-
-import (
-	v2net "v2ray.com/core/common/net"
-)
-
-type Config struct {
-	DirectPort v2net.Port
-}
@@ -0,0 +1,61 @@
+package api
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+type Config struct {
+	// Port for the API server to listen on.
+	DirectPort uint32 `protobuf:"varint,1,opt,name=direct_port,json=directPort" json:"direct_port,omitempty"`
+	// Address for the API server to listen on. Default to 127.0.0.1 if unset.
+	Listen *v2ray_core_common_net.IPOrDomain `protobuf:"bytes,2,opt,name=listen" json:"listen,omitempty"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+
+func (m *Config) GetDirectPort() uint32 {
+	if m != nil {
+		return m.DirectPort
+	}
+	return 0
+}
+
+func (m *Config) GetListen() *v2ray_core_common_net.IPOrDomain {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.api.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/api/config.proto", fileDescriptor1) }
+
+var fileDescriptor1 = []byte{
+	// 216 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0x8e, 0x31, 0x4b, 0x04, 0x31,
+	0x10, 0x46, 0xd9, 0x13, 0xb6, 0xc8, 0x62, 0x93, 0x42, 0x0e, 0x1b, 0x4f, 0x2d, 0xbc, 0x6a, 0x02,
+	0x6b, 0x65, 0x25, 0x77, 0x67, 0x63, 0xe5, 0xb2, 0x85, 0x85, 0x8d, 0x8c, 0x49, 0x94, 0x01, 0x93,
+	0x19, 0x66, 0x83, 0x70, 0x7f, 0xc9, 0x5f, 0x29, 0xb7, 0x51, 0x10, 0x6d, 0x87, 0xef, 0xcd, 0x7b,
+	0xe6, 0xf2, 0xa3, 0x57, 0xdc, 0x83, 0xe7, 0xe4, 0x3c, 0x6b, 0x74, 0x28, 0xe2, 0x50, 0xc8, 0x79,
+	0xce, 0xaf, 0xf4, 0x06, 0xa2, 0x5c, 0xd8, 0xda, 0x9f, 0x91, 0x46, 0x40, 0x11, 0x40, 0xa1, 0xd3,
+	0xab, 0x3f, 0xa0, 0xe7, 0x94, 0x38, 0xbb, 0x1c, 0x8b, 0xc3, 0x10, 0x34, 0x4e, 0x53, 0x85, 0x2f,
+	0x82, 0x69, 0x77, 0xf3, 0x33, 0x7b, 0x66, 0xba, 0x40, 0x1a, 0x7d, 0x79, 0x16, 0xd6, 0xb2, 0x6c,
+	0x56, 0xcd, 0xfa, 0x78, 0x34, 0xf5, 0x34, 0xb0, 0x16, 0x7b, 0x63, 0xda, 0x77, 0x9a, 0x4a, 0xcc,
+	0xcb, 0xc5, 0xaa, 0x59, 0x77, 0xfd, 0x39, 0xfc, 0x12, 0x57, 0x01, 0xe4, 0x58, 0xe0, 0x7e, 0x78,
+	0xd0, 0x3b, 0x4e, 0x48, 0x79, 0xfc, 0x06, 0xb6, 0xb7, 0xe6, 0xc4, 0x73, 0x82, 0xff, 0xa1, 0xdb,
+	0xae, 0xda, 0x87, 0x43, 0xcc, 0xd3, 0x11, 0x0a, 0x7d, 0x2e, 0xec, 0x63, 0x3f, 0xe2, 0x1e, 0x76,
+	0x87, 0xd9, 0x46, 0x04, 0x36, 0x42, 0x2f, 0xed, 0x5c, 0x7b, 0xfd, 0x15, 0x00, 0x00, 0xff, 0xff,
+	0xc3, 0x9a, 0x85, 0x11, 0x11, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/observatory"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/proxy"
+)
+
+func (s *ApiServer) registerHandlers(mux *http.ServeMux) {
+	mux.Handle(APIVersion+"/inbound/list", serve(func() proto.Message { return new(ListInboundRequest) }, s.listInbound))
+	mux.Handle(APIVersion+"/inbound/add", serve(func() proto.Message { return new(AddInboundRequest) }, s.addInbound))
+	mux.Handle(APIVersion+"/inbound/remove", serve(func() proto.Message { return new(RemoveInboundRequest) }, s.removeInbound))
+	mux.Handle(APIVersion+"/inbound/user/add", serve(func() proto.Message { return new(AddUserRequest) }, s.addUser))
+	mux.Handle(APIVersion+"/inbound/user/remove", serve(func() proto.Message { return new(RemoveUserRequest) }, s.removeUser))
+	mux.Handle(APIVersion+"/outbound/list", serve(func() proto.Message { return new(ListOutboundRequest) }, s.listOutbound))
+	mux.Handle(APIVersion+"/outbound/add", serve(func() proto.Message { return new(AddOutboundRequest) }, s.addOutbound))
+	mux.Handle(APIVersion+"/outbound/remove", serve(func() proto.Message { return new(RemoveOutboundRequest) }, s.removeOutbound))
+	mux.Handle(APIVersion+"/stats/get", serve(func() proto.Message { return new(GetStatsRequest) }, s.getStats))
+	mux.Handle(APIVersion+"/stats/query", serve(func() proto.Message { return new(QueryStatsRequest) }, s.queryStats))
+	mux.Handle(APIVersion+"/observatory/status", serve(func() proto.Message { return new(GetOutboundStatusRequest) }, s.getOutboundStatus))
+	mux.Handle(APIVersion+"/router/geodata/reload", serve(func() proto.Message { return new(ReloadGeoDataRequest) }, s.reloadGeoData))
+	mux.Handle(APIVersion+"/router/test", serve(func() proto.Message { return new(TestRouteRequest) }, s.testRoute))
+	mux.Handle(APIVersion+"/router/rules/list", serve(func() proto.Message { return new(ListRulesRequest) }, s.listRules))
+	mux.Handle(APIVersion+"/router/rules/add", serve(func() proto.Message { return new(AddRuleRequest) }, s.addRule))
+	mux.Handle(APIVersion+"/router/rules/remove", serve(func() proto.Message { return new(RemoveRuleRequest) }, s.removeRule))
+	mux.Handle(APIVersion+"/router/rules/replace", serve(func() proto.Message { return new(ReplaceRulesRequest) }, s.replaceRules))
+}
+
+func (s *ApiServer) listInbound(proto.Message) (proto.Message, error) {
+	return &ListInboundResponse{
+		Inbound: s.ihm.ListHandlers(s.ctx),
+	}, nil
+}
+
+func (s *ApiServer) addInbound(request proto.Message) (proto.Message, error) {
+	config := request.(*AddInboundRequest).Inbound
+	if config == nil || len(config.Tag) == 0 {
+		return nil, errors.New("API: Inbound handler must have a tag.")
+	}
+	if err := s.ihm.AddHandler(s.ctx, config); err != nil {
+		return nil, err
+	}
+	log.Info("API: Added inbound handler ", config.Tag)
+	return new(AddInboundResponse), nil
+}
+
+func (s *ApiServer) removeInbound(request proto.Message) (proto.Message, error) {
+	tag := request.(*RemoveInboundRequest).Tag
+	if err := s.ihm.RemoveHandler(s.ctx, tag); err != nil {
+		return nil, err
+	}
+	log.Info("API: Removed inbound handler ", tag)
+	return new(RemoveInboundResponse), nil
+}
+
+func (s *ApiServer) getUserManager(tag string) (proxy.UserManager, error) {
+	handler, err := s.ihm.GetHandler(s.ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	um, ok := handler.(proxy.UserManager)
+	if !ok {
+		return nil, errors.New("API: Inbound handler doesn't support user management: ", tag)
+	}
+	return um, nil
+}
+
+func (s *ApiServer) addUser(request proto.Message) (proto.Message, error) {
+	req := request.(*AddUserRequest)
+	if req.User == nil {
+		return nil, errors.New("API: User is not specified.")
+	}
+	um, err := s.getUserManager(req.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := um.AddUser(s.ctx, req.User); err != nil {
+		return nil, err
+	}
+	log.Info("API: Added user ", req.User.Email, " to inbound handler ", req.Tag)
+	return new(AddUserResponse), nil
+}
+
+func (s *ApiServer) removeUser(request proto.Message) (proto.Message, error) {
+	req := request.(*RemoveUserRequest)
+	um, err := s.getUserManager(req.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := um.RemoveUser(s.ctx, req.Email); err != nil {
+		return nil, err
+	}
+	log.Info("API: Removed user ", req.Email, " from inbound handler ", req.Tag)
+	return new(RemoveUserResponse), nil
+}
+
+func (s *ApiServer) listOutbound(proto.Message) (proto.Message, error) {
+	return &ListOutboundResponse{
+		Outbound: s.ohm.ListHandlers(s.ctx),
+	}, nil
+}
+
+func (s *ApiServer) addOutbound(request proto.Message) (proto.Message, error) {
+	config := request.(*AddOutboundRequest).Outbound
+	if config == nil || len(config.Tag) == 0 {
+		return nil, errors.New("API: Outbound handler must have a tag.")
+	}
+	if err := s.ohm.AddHandler(s.ctx, config); err != nil {
+		return nil, err
+	}
+	log.Info("API: Added outbound handler ", config.Tag)
+	return new(AddOutboundResponse), nil
+}
+
+func (s *ApiServer) removeOutbound(request proto.Message) (proto.Message, error) {
+	tag := request.(*RemoveOutboundRequest).Tag
+	if err := s.ohm.RemoveHandler(s.ctx, tag); err != nil {
+		return nil, err
+	}
+	log.Info("API: Removed outbound handler ", tag)
+	return new(RemoveOutboundResponse), nil
+}
+
+func (s *ApiServer) getStats(request proto.Message) (proto.Message, error) {
+	if s.stats == nil {
+		return nil, errors.New("API: Stats is not enabled.")
+	}
+	req := request.(*GetStatsRequest)
+	counter := s.stats.GetCounter(req.Name)
+	if counter == nil {
+		return nil, errors.Base(stats.ErrCounterNotFound).Message("API: Failed to get stats: ", req.Name)
+	}
+	var value int64
+	if req.Reset_ {
+		value = counter.Set(0)
+	} else {
+		value = counter.Value()
+	}
+	return &GetStatsResponse{
+		Stat: &Stat{
+			Name:  req.Name,
+			Value: value,
+		},
+	}, nil
+}
+
+func (s *ApiServer) queryStats(request proto.Message) (proto.Message, error) {
+	if s.stats == nil {
+		return nil, errors.New("API: Stats is not enabled.")
+	}
+	req := request.(*QueryStatsRequest)
+	response := new(QueryStatsResponse)
+	for name, value := range s.stats.Query(req.Pattern, req.Reset_) {
+		response.Stat = append(response.Stat, &Stat{
+			Name:  name,
+			Value: value,
+		})
+	}
+	return response, nil
+}
+
+func (s *ApiServer) getOutboundStatus(request proto.Message) (proto.Message, error) {
+	if s.observer == nil {
+		return nil, errors.New("API: Observatory is not enabled.")
+	}
+	req := request.(*GetOutboundStatusRequest)
+	if len(req.Tag) == 0 {
+		return &GetOutboundStatusResponse{
+			Status: s.observer.ListStatus(),
+		}, nil
+	}
+	status := s.observer.GetStatus(req.Tag)
+	if status == nil {
+		return nil, errors.New("API: Outbound handler is not probed: ", req.Tag)
+	}
+	return &GetOutboundStatusResponse{
+		Status: []*observatory.OutboundStatus{status},
+	}, nil
+}
+
+func (s *ApiServer) reloadGeoData(proto.Message) (proto.Message, error) {
+	if s.router == nil {
+		return nil, errors.New("API: Router is not enabled.")
+	}
+	reloaded, err := s.router.ReloadGeoData()
+	if err != nil {
+		return nil, err
+	}
+	if reloaded {
+		log.Info("API: Reloaded GeoIP and GeoSite files.")
+	}
+	return &ReloadGeoDataResponse{
+		Reloaded: reloaded,
+	}, nil
+}
+
+func (s *ApiServer) testRoute(request proto.Message) (proto.Message, error) {
+	if s.router == nil {
+		return nil, errors.New("API: Router is not enabled.")
+	}
+	req := request.(*TestRouteRequest)
+	if req.Request == nil || req.Request.Destination == nil {
+		return nil, errors.New("API: Destination is not specified.")
+	}
+	explanation, err := s.router.Explain(router.ContextWithRouteRequest(s.ctx, req.Request))
+	if err != nil && err != router.ErrNoRuleApplicable {
+		return nil, err
+	}
+	return &TestRouteResponse{
+		Explanation: explanation,
+	}, nil
+}
+
+func (s *ApiServer) listRules(request proto.Message) (proto.Message, error) {
+	if s.router == nil {
+		return nil, errors.New("API: Router is not enabled.")
+	}
+	req := request.(*ListRulesRequest)
+	rules, defaultHits := s.router.ListRules(req.Reset_)
+	return &ListRulesResponse{
+		Rule:        rules,
+		DefaultHits: defaultHits,
+	}, nil
+}
+
+func (s *ApiServer) addRule(request proto.Message) (proto.Message, error) {
+	if s.router == nil {
+		return nil, errors.New("API: Router is not enabled.")
+	}
+	req := request.(*AddRuleRequest)
+	if req.Rule == nil {
+		return nil, errors.New("API: Rule is not specified.")
+	}
+	if err := s.router.AddRule(req.Rule, int(req.Index)); err != nil {
+		return nil, err
+	}
+	log.Info("API: Added routing rule ", req.Rule.RuleTag)
+	return new(AddRuleResponse), nil
+}
+
+func (s *ApiServer) removeRule(request proto.Message) (proto.Message, error) {
+	if s.router == nil {
+		return nil, errors.New("API: Router is not enabled.")
+	}
+	tag := request.(*RemoveRuleRequest).RuleTag
+	if err := s.router.RemoveRule(tag); err != nil {
+		return nil, err
+	}
+	log.Info("API: Removed routing rule ", tag)
+	return new(RemoveRuleResponse), nil
+}
+
+func (s *ApiServer) replaceRules(request proto.Message) (proto.Message, error) {
+	if s.router == nil {
+		return nil, errors.New("API: Router is not enabled.")
+	}
+	req := request.(*ReplaceRulesRequest)
+	if err := s.router.ReplaceRules(req.Rule); err != nil {
+		return nil, err
+	}
+	log.Info("API: Replaced all routing rules.")
+	return new(ReplaceRulesResponse), nil
+}
@@ -2,23 +2,32 @@ package impl
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/dns"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/proxyman/mux"
 	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport/ray"
 )
 
 type DefaultDispatcher struct {
+	mux    *mux.Server
 	ohm    proxyman.OutboundHandlerManager
 	router *router.Router
+	stats  *stats.Manager
+	fakeIP dns.FakeIPServer
 }
 
 func NewDefaultDispatcher(ctx context.Context, config *dispatcher.Config) (*DefaultDispatcher, error) {
@@ -27,12 +36,17 @@ func NewDefaultDispatcher(ctx context.Context, config *dispatcher.Config) (*Defa
 		return nil, errors.New("DefaultDispatcher: No space in context.")
 	}
 	d := &DefaultDispatcher{}
+	d.mux = mux.NewServer(d)
 	space.OnInitialize(func() error {
 		d.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
 		if d.ohm == nil {
 			return errors.New("DefaultDispatcher: OutboundHandlerManager is not found in the space.")
 		}
 		d.router = router.FromSpace(space)
+		d.stats = stats.FromSpace(space)
+		if server, ok := dns.FromSpace(space).(dns.FakeIPServer); ok {
+			d.fakeIP = server
+		}
 		return nil
 	})
 	return d, nil
@@ -43,43 +57,173 @@ func (DefaultDispatcher) Interface() interface{} {
 }
 
 func (v *DefaultDispatcher) DispatchToOutbound(ctx context.Context) ray.InboundRay {
-	dispatcher := v.ohm.GetDefaultHandler()
 	destination := proxy.DestinationFromContext(ctx)
 	if !destination.IsValid() {
 		panic("Dispatcher: Invalid destination.")
 	}
-
-	if v.router != nil {
-		if tag, err := v.router.TakeDetour(ctx); err == nil {
-			if handler := v.ohm.GetHandler(tag); handler != nil {
-				log.Info("DefaultDispatcher: Taking detour [", tag, "] for [", destination, "].")
-				dispatcher = handler
-			} else {
-				log.Warning("DefaultDispatcher: Nonexisting tag: ", tag)
-			}
-		} else {
-			log.Info("DefaultDispatcher: Default route for ", destination)
+	if mux.IsMuxDestination(destination) {
+		return v.mux.Dispatch(ctx)
+	}
+	if v.fakeIP != nil && !destination.Address.Family().IsDomain() {
+		if domain := v.fakeIP.GetFakeDomain(destination.Address.IP()); len(domain) > 0 {
+			log.Info("DefaultDispatcher: Restoring domain [", domain, "] for fake IP [", destination.Address, "].")
+			destination.Address = net.DomainAddress(domain)
+			ctx = proxy.ContextWithDestination(ctx, destination)
 		}
 	}
 
 	direct := ray.NewRay(ctx)
-	var waitFunc func() error
+	if v.stats != nil {
+		v.addInboundStatsInspectors(ctx, direct)
+	}
+
 	if allowPassiveConnection, ok := proxy.AllowPassiveConnectionFromContext(ctx); ok && allowPassiveConnection {
-		waitFunc = noOpWait()
-	} else {
+		ctx, dispatcher, tracker := v.route(ctx, ctx, direct, nil)
+		go v.waitAndDispatch(ctx, noOpWait(), direct, dispatcher, tracker)
+		return direct
+	}
+
+	sniffing := proxyman.SniffingConfigFromContext(ctx)
+	if sniffing == nil {
+		ctx, dispatcher, tracker := v.route(ctx, ctx, direct, nil)
 		wdi := &waitDataInspector{
 			hasData: make(chan bool, 1),
 		}
 		direct.AddInspector(wdi)
-		waitFunc = waitForData(wdi)
+		go v.waitAndDispatch(ctx, waitForData(wdi), direct, dispatcher, tracker)
+		return direct
 	}
 
-	go v.waitAndDispatch(ctx, waitFunc, direct, dispatcher)
-
+	// The sniffer must be added before the waitDataInspector, so that it has the payload when data arrives.
+	sniffer := new(sniffingInspector)
+	direct.AddInputInspector(sniffer)
+	var uplink *pendingCounter
+	if v.stats != nil {
+		uplink = new(pendingCounter)
+		direct.AddInputInspector(uplink)
+	}
+	wdi := &waitDataInspector{
+		hasData: make(chan bool, 1),
+	}
+	direct.AddInspector(wdi)
+	go func() {
+		if err := waitForData(wdi)(); err != nil {
+			log.Info("DefaultDispatcher: Failed precondition: ", err)
+			direct.OutboundInput().CloseError()
+			direct.OutboundOutput().CloseError()
+			return
+		}
+		ctx, routeCtx := v.sniff(ctx, sniffer.Payload(), sniffing)
+		ctx, dispatcher, tracker := v.route(ctx, routeCtx, direct, uplink)
+		v.waitAndDispatch(ctx, noOpWait(), direct, dispatcher, tracker)
+	}()
 	return direct
 }
 
-func (v *DefaultDispatcher) waitAndDispatch(ctx context.Context, wait func() error, link ray.OutboundRay, dispatcher proxyman.OutboundHandler) {
+// sniff tries to recover the domain of the destination from the given payload. It returns the context for the
+// connection and the context for routing, which differ if the sniffed domain is only used for routing.
+func (v *DefaultDispatcher) sniff(ctx context.Context, payload []byte, config *proxyman.SniffingConfig) (context.Context, context.Context) {
+	protocol, domain, err := Sniff(payload, config.DestinationOverride)
+	if err != nil {
+		log.Debug("DefaultDispatcher: Failed to sniff: ", err)
+		return ctx, ctx
+	}
+
+	destination := proxy.DestinationFromContext(ctx)
+	log.Info("DefaultDispatcher: Sniffed domain [", domain, "] for [", destination, "] from ", protocol)
+	destination.Address = net.ParseAddress(domain)
+	sniffedCtx := proxy.ContextWithDestination(ctx, destination)
+	if config.RouteOnly {
+		return ctx, sniffedCtx
+	}
+	return sniffedCtx, sniffedCtx
+}
+
+// route picks the outbound handler for the connection by routing with routeCtx. It returns ctx with the outbound
+// tag, the picked handler, and the tracker if the handler is picked by a balancer. If uplink is not nil, it starts
+// counting for the picked handler.
+func (v *DefaultDispatcher) route(ctx context.Context, routeCtx context.Context, link ray.Ray, uplink *pendingCounter) (context.Context, proxyman.OutboundHandler, *balancerTracker) {
+	dispatcher, balancer := v.pickHandler(routeCtx)
+
+	if dispatcher != nil {
+		if tag := dispatcher.Tag(); len(tag) > 0 {
+			ctx = proxy.ContextWithOutboundTag(ctx, tag)
+		}
+	}
+	if v.stats != nil {
+		v.addOutboundStatsInspectors(ctx, link, uplink)
+	}
+
+	var tracker *balancerTracker
+	if balancer != nil {
+		tracker = newBalancerTracker(balancer, dispatcher.Tag())
+		link.AddOutputInspector(tracker)
+	}
+	return ctx, dispatcher, tracker
+}
+
+// pickHandler returns the outbound handler for the connection in the given context, and the balancer that picked it
+// if any.
+func (v *DefaultDispatcher) pickHandler(ctx context.Context) (proxyman.OutboundHandler, *router.Balancer) {
+	dispatcher := v.ohm.GetDefaultHandler()
+	if v.router == nil {
+		return dispatcher, nil
+	}
+
+	destination := proxy.DestinationFromContext(ctx)
+	tag, balancer, err := v.router.PickRoute(ctx)
+	if err != nil {
+		log.Info("DefaultDispatcher: Default route for ", destination)
+		return dispatcher, nil
+	}
+	if balancer != nil {
+		tag, err = balancer.PickOutbound(ctx)
+		if err != nil {
+			log.Warning("DefaultDispatcher: ", err)
+			return dispatcher, nil
+		}
+	}
+	handler := v.ohm.GetHandler(tag)
+	if handler == nil {
+		log.Warning("DefaultDispatcher: Nonexisting tag: ", tag)
+		return dispatcher, nil
+	}
+	log.Info("DefaultDispatcher: Taking detour [", tag, "] for [", destination, "].")
+	return handler, balancer
+}
+
+// addInboundStatsInspectors attaches traffic counters of the user and the inbound in the context to the given ray.
+func (v *DefaultDispatcher) addInboundStatsInspectors(ctx context.Context, link ray.Ray) {
+	if user := protocol.UserFromContext(ctx); user != nil && len(user.Email) > 0 {
+		link.AddInputInspector(v.stats.GetOrCreateCounter(stats.UserUplinkName(user.Email)))
+		link.AddOutputInspector(v.stats.GetOrCreateCounter(stats.UserDownlinkName(user.Email)))
+	}
+	if tag := proxy.InboundTagFromContext(ctx); len(tag) > 0 {
+		link.AddInputInspector(v.stats.GetOrCreateCounter(stats.InboundUplinkName(tag)))
+		link.AddOutputInspector(v.stats.GetOrCreateCounter(stats.InboundDownlinkName(tag)))
+	}
+}
+
+// addOutboundStatsInspectors attaches traffic counters of the outbound in the context to the given ray. If uplink is
+// not nil, it is already attached to the ray, and takes the uplink counter.
+func (v *DefaultDispatcher) addOutboundStatsInspectors(ctx context.Context, link ray.Ray, uplink *pendingCounter) {
+	tag := proxy.OutboundTagFromContext(ctx)
+	if len(tag) == 0 {
+		return
+	}
+	if uplink != nil {
+		uplink.SetCounter(v.stats.GetOrCreateCounter(stats.OutboundUplinkName(tag)))
+	} else {
+		link.AddInputInspector(v.stats.GetOrCreateCounter(stats.OutboundUplinkName(tag)))
+	}
+	link.AddOutputInspector(v.stats.GetOrCreateCounter(stats.OutboundDownlinkName(tag)))
+}
+
+func (v *DefaultDispatcher) waitAndDispatch(ctx context.Context, wait func() error, link ray.OutboundRay, dispatcher proxyman.OutboundHandler, tracker *balancerTracker) {
+	if tracker != nil {
+		defer tracker.Finish()
+	}
+
 	if err := wait(); err != nil {
 		log.Info("DefaultDispatcher: Failed precondition: ", err)
 		link.OutboundInput().CloseError()
@@ -87,6 +231,16 @@ func (v *DefaultDispatcher) waitAndDispatch(ctx context.Context, wait func() err
 		return
 	}
 
+	if dispatcher == nil {
+		log.Warning("DefaultDispatcher: No outbound handler available.")
+		link.OutboundInput().CloseError()
+		link.OutboundOutput().CloseError()
+		return
+	}
+
+	if tracker != nil {
+		tracker.Start()
+	}
 	dispatcher.Dispatch(ctx, link)
 }
 
@@ -130,3 +284,104 @@ func noOpWait() func() error {
 		return nil
 	}
 }
+
+// balancerTracker reports a connection to the balancer that picked its outbound. The latency of the outbound is the
+// time from dispatching to the first response, or the lifetime of the connection if there is no response at all.
+type balancerTracker struct {
+	sync.Mutex
+	balancer *router.Balancer
+	tag      string
+	release  func()
+	start    time.Time
+	reported bool
+}
+
+func newBalancerTracker(balancer *router.Balancer, tag string) *balancerTracker {
+	return &balancerTracker{
+		balancer: balancer,
+		tag:      tag,
+		release:  balancer.Acquire(tag),
+	}
+}
+
+func (t *balancerTracker) Start() {
+	t.Lock()
+	t.start = time.Now()
+	t.Unlock()
+}
+
+// Input implements ray.Inspector. It is called on data from the outbound.
+func (t *balancerTracker) Input(*buf.Buffer) {
+	t.report()
+}
+
+func (t *balancerTracker) report() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.reported || t.start.IsZero() {
+		return
+	}
+	t.reported = true
+	t.balancer.ReportLatency(t.tag, time.Since(t.start))
+}
+
+// Finish is called when the connection finishes.
+func (t *balancerTracker) Finish() {
+	t.report()
+	t.release()
+}
+
+// sniffingInspector keeps a copy of the first payload for sniffing.
+type sniffingInspector struct {
+	sync.Mutex
+	payload []byte
+}
+
+func (si *sniffingInspector) Input(b *buf.Buffer) {
+	si.Lock()
+	defer si.Unlock()
+
+	if si.payload == nil {
+		si.payload = append([]byte(nil), b.Bytes()...)
+	}
+}
+
+func (si *sniffingInspector) Payload() []byte {
+	si.Lock()
+	defer si.Unlock()
+
+	return si.payload
+}
+
+// pendingCounter counts traffic before the counter is known, and adds it to the counter once set.
+type pendingCounter struct {
+	sync.Mutex
+	pending int64
+	counter *stats.Counter
+}
+
+func (pc *pendingCounter) Input(b *buf.Buffer) {
+	pc.InputLength(b.Len())
+}
+
+// InputLength implements ray.LengthInspector.
+func (pc *pendingCounter) InputLength(length int) {
+	pc.Lock()
+	defer pc.Unlock()
+
+	if pc.counter != nil {
+		pc.counter.Add(int64(length))
+	} else {
+		pc.pending += int64(length)
+	}
+}
+
+func (pc *pendingCounter) SetCounter(counter *stats.Counter) {
+	pc.Lock()
+	defer pc.Unlock()
+
+	counter.Add(pc.pending)
+	pc.pending = 0
+	pc.counter = counter
+}
@@ -0,0 +1,176 @@
+package impl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strings"
+
+	"v2ray.com/core/common/errors"
+)
+
+var (
+	ErrNoClue        = errors.New("DefaultDispatcher: Not enough information for making a decision.")
+	errNotHTTPMethod = errors.New("DefaultDispatcher: Not a HTTP method.")
+	errNotTLS        = errors.New("DefaultDispatcher: Not a TLS ClientHello.")
+)
+
+var httpMethods = []string{"GET", "POST", "HEAD", "PUT", "DELETE", "OPTIONS", "CONNECT", "PATCH", "TRACE"}
+
+// SniffHTTP returns the host in the Host header of the HTTP request in the given payload, without port.
+func SniffHTTP(payload []byte) (string, error) {
+	isHTTP := false
+	for _, method := range httpMethods {
+		if len(payload) > len(method) && string(payload[:len(method)]) == method && payload[len(method)] == ' ' {
+			isHTTP = true
+			break
+		}
+	}
+	if !isHTTP {
+		return "", errNotHTTPMethod
+	}
+
+	lines := bytes.Split(payload, []byte("\r\n"))
+	for _, line := range lines[1:] {
+		if len(line) == 0 {
+			break
+		}
+		idx := bytes.IndexByte(line, ':')
+		if idx < 0 || !strings.EqualFold(string(bytes.TrimSpace(line[:idx])), "host") {
+			continue
+		}
+		host := string(bytes.TrimSpace(line[idx+1:]))
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if len(host) == 0 {
+			return "", ErrNoClue
+		}
+		return strings.ToLower(host), nil
+	}
+	return "", ErrNoClue
+}
+
+// SniffTLS returns the server name in the SNI extension of the TLS ClientHello in the given payload.
+func SniffTLS(payload []byte) (string, error) {
+	// Record header: content type, version and length.
+	if len(payload) < 5 || payload[0] != 0x16 || payload[1] != 0x03 {
+		return "", errNotTLS
+	}
+	recordLen := int(binary.BigEndian.Uint16(payload[3:5]))
+	data := payload[5:]
+	if len(data) < recordLen {
+		return "", ErrNoClue
+	}
+	data = data[:recordLen]
+
+	// Handshake header: type and length.
+	if len(data) < 4 || data[0] != 0x01 {
+		return "", errNotTLS
+	}
+	data = data[4:]
+
+	// Client version and random.
+	if len(data) < 34 {
+		return "", ErrNoClue
+	}
+	data = data[34:]
+
+	// Session ID.
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return "", ErrNoClue
+	}
+	data = data[1+int(data[0]):]
+
+	// Cipher suites.
+	if len(data) < 2 {
+		return "", ErrNoClue
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+n {
+		return "", ErrNoClue
+	}
+	data = data[2+n:]
+
+	// Compression methods.
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return "", ErrNoClue
+	}
+	data = data[1+int(data[0]):]
+
+	// Extensions.
+	if len(data) < 2 {
+		return "", ErrNoClue
+	}
+	n = int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < n {
+		return "", ErrNoClue
+	}
+	data = data[:n]
+
+	for len(data) >= 4 {
+		extType := binary.BigEndian.Uint16(data)
+		extLen := int(binary.BigEndian.Uint16(data[2:]))
+		data = data[4:]
+		if len(data) < extLen {
+			return "", ErrNoClue
+		}
+		if extType == 0x00 {
+			return parseServerName(data[:extLen])
+		}
+		data = data[extLen:]
+	}
+	return "", ErrNoClue
+}
+
+func parseServerName(data []byte) (string, error) {
+	if len(data) < 2 {
+		return "", ErrNoClue
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < n {
+		return "", ErrNoClue
+	}
+	data = data[:n]
+
+	for len(data) >= 3 {
+		nameType := data[0]
+		nameLen := int(binary.BigEndian.Uint16(data[1:]))
+		data = data[3:]
+		if len(data) < nameLen {
+			return "", ErrNoClue
+		}
+		if nameType == 0x00 && nameLen > 0 {
+			return strings.ToLower(strings.TrimSuffix(string(data[:nameLen]), ".")), nil
+		}
+		data = data[nameLen:]
+	}
+	return "", ErrNoClue
+}
+
+// Sniff tries the given protocols in order, and returns the first protocol that recovers a domain from the payload.
+// Supported protocols are "http" and "tls".
+func Sniff(payload []byte, protocols []string) (string, string, error) {
+	if len(payload) == 0 {
+		return "", "", ErrNoClue
+	}
+	for _, protocol := range protocols {
+		var domain string
+		var err error
+		switch strings.ToLower(protocol) {
+		case "http":
+			domain, err = SniffHTTP(payload)
+		case "tls":
+			domain, err = SniffTLS(payload)
+		default:
+			continue
+		}
+		if err == nil {
+			return protocol, domain, nil
+		}
+	}
+	return "", "", ErrNoClue
+}
@@ -0,0 +1,60 @@
+package impl_test
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	. "v2ray.com/core/app/dispatcher/impl"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestHTTPSniffing(t *testing.T) {
+	assert := assert.On(t)
+
+	domain, err := SniffHTTP([]byte("GET /index.html HTTP/1.1\r\nUser-Agent: v2ray\r\nhost: WWW.V2Ray.com:8080\r\n\r\n"))
+	assert.Error(err).IsNil()
+	assert.String(domain).Equals("www.v2ray.com")
+
+	_, err = SniffHTTP([]byte("GET / HTTP/1.1\r\nUser-Agent: v2ray\r\n\r\n"))
+	assert.Error(err).IsNotNil()
+
+	_, err = SniffHTTP([]byte("SSH-2.0-OpenSSH_7.4\r\n"))
+	assert.Error(err).IsNotNil()
+}
+
+func clientHello(serverName string) []byte {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	go func() {
+		tls.Client(client, &tls.Config{
+			ServerName: serverName,
+		}).Handshake()
+		client.Close()
+	}()
+
+	payload := make([]byte, 4096)
+	n, _ := server.Read(payload)
+	return payload[:n]
+}
+
+func TestTLSSniffing(t *testing.T) {
+	assert := assert.On(t)
+
+	payload := clientHello("www.v2ray.com")
+	domain, err := SniffTLS(payload)
+	assert.Error(err).IsNil()
+	assert.String(domain).Equals("www.v2ray.com")
+
+	_, err = SniffTLS(payload[:20])
+	assert.Error(err).IsNotNil()
+
+	protocol, domain, err := Sniff(payload, []string{"http", "tls"})
+	assert.Error(err).IsNil()
+	assert.String(protocol).Equals("tls")
+	assert.String(domain).Equals("www.v2ray.com")
+
+	_, _, err = Sniff(payload, []string{"http"})
+	assert.Error(err).IsNotNil()
+}
@@ -5,6 +5,7 @@ import fmt "fmt"
 import math "math"
 import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_net2 "v2ray.com/core/common/net"
+import v2ray_core_transport_internet_tls "v2ray.com/core/transport/internet/tls"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -17,18 +18,463 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type Domain_Type int32
+
+const (
+	Domain_Plain     Domain_Type = 0
+	Domain_Regex     Domain_Type = 1
+	Domain_Subdomain Domain_Type = 2
+	Domain_Full      Domain_Type = 3
+)
+
+var Domain_Type_name = map[int32]string{
+	0: "Plain",
+	1: "Regex",
+	2: "Subdomain",
+	3: "Full",
+}
+var Domain_Type_value = map[string]int32{
+	"Plain":     0,
+	"Regex":     1,
+	"Subdomain": 2,
+	"Full":      3,
+}
+
+func (x Domain_Type) String() string {
+	return proto.EnumName(Domain_Type_name, int32(x))
+}
+func (Domain_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
+
+type NameServer_Type int32
+
+const (
+	// Plain DNS over UDP.
+	NameServer_UDP NameServer_Type = 0
+	// Plain DNS over TCP.
+	NameServer_TCP NameServer_Type = 1
+	// DNS over TLS, RFC 7858.
+	NameServer_TLS NameServer_Type = 2
+	// DNS over HTTPS, RFC 8484.
+	NameServer_HTTPS NameServer_Type = 3
+)
+
+var NameServer_Type_name = map[int32]string{
+	0: "UDP",
+	1: "TCP",
+	2: "TLS",
+	3: "HTTPS",
+}
+var NameServer_Type_value = map[string]int32{
+	"UDP":   0,
+	"TCP":   1,
+	"TLS":   2,
+	"HTTPS": 3,
+}
+
+func (x NameServer_Type) String() string {
+	return proto.EnumName(NameServer_Type_name, int32(x))
+}
+func (NameServer_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 0} }
+
+type Config_QueryStrategy int32
+
+const (
+	// Query A records only.
+	Config_USE_IP4 Config_QueryStrategy = 0
+	// Query AAAA records only.
+	Config_USE_IP6 Config_QueryStrategy = 1
+	// Query A records, and AAAA records if there is no A record.
+	Config_PREFER_IP4 Config_QueryStrategy = 2
+	// Query AAAA records, and A records if there is no AAAA record.
+	Config_PREFER_IP6 Config_QueryStrategy = 3
+)
+
+var Config_QueryStrategy_name = map[int32]string{
+	0: "USE_IP4",
+	1: "USE_IP6",
+	2: "PREFER_IP4",
+	3: "PREFER_IP6",
+}
+var Config_QueryStrategy_value = map[string]int32{
+	"USE_IP4":    0,
+	"USE_IP6":    1,
+	"PREFER_IP4": 2,
+	"PREFER_IP6": 3,
+}
+
+func (x Config_QueryStrategy) String() string {
+	return proto.EnumName(Config_QueryStrategy_name, int32(x))
+}
+func (Config_QueryStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{8, 0} }
+
+// Domain is a domain matching rule, in the same way as v2ray.core.app.router.Domain.
+type Domain struct {
+	Type  Domain_Type `protobuf:"varint,1,opt,name=type,enum=v2ray.core.app.dns.Domain_Type" json:"type,omitempty"`
+	Value string      `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Domain) Reset()                    { *m = Domain{} }
+func (m *Domain) String() string            { return proto.CompactTextString(m) }
+func (*Domain) ProtoMessage()               {}
+func (*Domain) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Domain) GetType() Domain_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Domain_Plain
+}
+
+func (m *Domain) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// CIDR is an IP network, in the same way as v2ray.core.app.router.CIDR.
+type CIDR struct {
+	Ip     []byte `protobuf:"bytes,1,opt,name=ip" json:"ip,omitempty"`
+	Prefix uint32 `protobuf:"varint,2,opt,name=prefix" json:"prefix,omitempty"`
+}
+
+func (m *CIDR) Reset()                    { *m = CIDR{} }
+func (m *CIDR) String() string            { return proto.CompactTextString(m) }
+func (*CIDR) ProtoMessage()               {}
+func (*CIDR) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *CIDR) GetIp() []byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *CIDR) GetPrefix() uint32 {
+	if m != nil {
+		return m.Prefix
+	}
+	return 0
+}
+
+type NameServer struct {
+	Type NameServer_Type `protobuf:"varint,1,opt,name=type,enum=v2ray.core.app.dns.NameServer_Type" json:"type,omitempty"`
+	// Address of the name server. Port defaults to 53 for UDP and TCP, 853 for TLS and 443 for HTTPS.
+	// Queries are sent through the dispatcher, so a domain address here is resolved by the outbound, or by this
+	// DNS itself if routing needs the IP. Use an IP address or a static host for it in that case.
+	Address *v2ray_core_common_net2.Endpoint `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	// TLS settings for TLS and HTTPS name servers. Server name defaults to the address.
+	TlsSettings *v2ray_core_transport_internet_tls.Config `protobuf:"bytes,3,opt,name=tls_settings,json=tlsSettings" json:"tls_settings,omitempty"`
+	// URL path of HTTPS name servers. Defaults to "/dns-query".
+	Path string `protobuf:"bytes,4,opt,name=path" json:"path,omitempty"`
+	// Domains served by this name server. If a domain matches any name server, only the matching ones are queried
+	// for it. Otherwise name servers without domains are queried.
+	Domain []*Domain `protobuf:"bytes,5,rep,name=domain" json:"domain,omitempty"`
+	// If not empty, IPs outside these networks are dropped from the answers of this name server. If no IP is left,
+	// the next name server is queried.
+	ExpectedIp []*CIDR `protobuf:"bytes,6,rep,name=expected_ip,json=expectedIp" json:"expected_ip,omitempty"`
+}
+
+func (m *NameServer) Reset()                    { *m = NameServer{} }
+func (m *NameServer) String() string            { return proto.CompactTextString(m) }
+func (*NameServer) ProtoMessage()               {}
+func (*NameServer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *NameServer) GetType() NameServer_Type {
+	if m != nil {
+		return m.Type
+	}
+	return NameServer_UDP
+}
+
+func (m *NameServer) GetAddress() *v2ray_core_common_net2.Endpoint {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *NameServer) GetTlsSettings() *v2ray_core_transport_internet_tls.Config {
+	if m != nil {
+		return m.TlsSettings
+	}
+	return nil
+}
+
+func (m *NameServer) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *NameServer) GetDomain() []*Domain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *NameServer) GetExpectedIp() []*CIDR {
+	if m != nil {
+		return m.ExpectedIp
+	}
+	return nil
+}
+
+// HostMapping maps domains matching a pattern to static IPs, or to another domain as an alias.
+type HostMapping struct {
+	Type   Domain_Type `protobuf:"varint,1,opt,name=type,enum=v2ray.core.app.dns.Domain_Type" json:"type,omitempty"`
+	Domain string      `protobuf:"bytes,2,opt,name=domain" json:"domain,omitempty"`
+	Ip     [][]byte    `protobuf:"bytes,3,rep,name=ip" json:"ip,omitempty"`
+	// If set, the matching domains are aliases of this domain, which is resolved in the same way, recursively.
+	ProxiedDomain string `protobuf:"bytes,4,opt,name=proxied_domain,json=proxiedDomain" json:"proxied_domain,omitempty"`
+}
+
+func (m *HostMapping) Reset()                    { *m = HostMapping{} }
+func (m *HostMapping) String() string            { return proto.CompactTextString(m) }
+func (*HostMapping) ProtoMessage()               {}
+func (*HostMapping) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *HostMapping) GetType() Domain_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Domain_Plain
+}
+
+func (m *HostMapping) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *HostMapping) GetIp() [][]byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *HostMapping) GetProxiedDomain() string {
+	if m != nil {
+		return m.ProxiedDomain
+	}
+	return ""
+}
+
+// BlockRule refuses to resolve the matching domains. It applies to DNS queries only: the DNS inbound, routing by IP
+// and outbounds resolving domains with UseIP. Connections sent to a domain as is are not blocked.
+type BlockRule struct {
+	Domain []*Domain `protobuf:"bytes,1,rep,name=domain" json:"domain,omitempty"`
+	// IPs answered for the matching domains. If empty, the domains are answered as nonexistent (NXDOMAIN).
+	SinkholeIp [][]byte `protobuf:"bytes,2,rep,name=sinkhole_ip,json=sinkholeIp,proto3" json:"sinkhole_ip,omitempty"`
+}
+
+func (m *BlockRule) Reset()                    { *m = BlockRule{} }
+func (m *BlockRule) String() string            { return proto.CompactTextString(m) }
+func (*BlockRule) ProtoMessage()               {}
+func (*BlockRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *BlockRule) GetDomain() []*Domain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *BlockRule) GetSinkholeIp() [][]byte {
+	if m != nil {
+		return m.SinkholeIp
+	}
+	return nil
+}
+
+type FakeIPPool struct {
+	// Network of the fake IPs, such as 198.18.0.0/15.
+	Cidr *CIDR `protobuf:"bytes,1,opt,name=cidr" json:"cidr,omitempty"`
+	// Maximum number of domains in the pool. When the pool is full, the IP of the least recently used domain is
+	// reused. Defaults to 65535.
+	Size uint32 `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+}
+
+func (m *FakeIPPool) Reset()                    { *m = FakeIPPool{} }
+func (m *FakeIPPool) String() string            { return proto.CompactTextString(m) }
+func (*FakeIPPool) ProtoMessage()               {}
+func (*FakeIPPool) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *FakeIPPool) GetCidr() *CIDR {
+	if m != nil {
+		return m.Cidr
+	}
+	return nil
+}
+
+func (m *FakeIPPool) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type CacheConfig struct {
+	// File to save cached records to. Records in it are loaded at startup. Cache is kept in memory only if empty.
+	File string `protobuf:"bytes,1,opt,name=file" json:"file,omitempty"`
+	// If true, records queried frequently are refreshed in background shortly before they expire.
+	Prefetch bool `protobuf:"varint,2,opt,name=prefetch" json:"prefetch,omitempty"`
+	// Seconds for which an expired record is still returned, while it is refreshed in background. 0 disables it.
+	ServeStale uint32 `protobuf:"varint,3,opt,name=serve_stale,json=serveStale" json:"serve_stale,omitempty"`
+	// Minimum and maximum TTL in seconds of cached records. 0 means no limit.
+	MinTtl uint32 `protobuf:"varint,4,opt,name=min_ttl,json=minTtl" json:"min_ttl,omitempty"`
+	MaxTtl uint32 `protobuf:"varint,5,opt,name=max_ttl,json=maxTtl" json:"max_ttl,omitempty"`
+}
+
+func (m *CacheConfig) Reset()                    { *m = CacheConfig{} }
+func (m *CacheConfig) String() string            { return proto.CompactTextString(m) }
+func (*CacheConfig) ProtoMessage()               {}
+func (*CacheConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *CacheConfig) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *CacheConfig) GetPrefetch() bool {
+	if m != nil {
+		return m.Prefetch
+	}
+	return false
+}
+
+func (m *CacheConfig) GetServeStale() uint32 {
+	if m != nil {
+		return m.ServeStale
+	}
+	return 0
+}
+
+func (m *CacheConfig) GetMinTtl() uint32 {
+	if m != nil {
+		return m.MinTtl
+	}
+	return 0
+}
+
+func (m *CacheConfig) GetMaxTtl() uint32 {
+	if m != nil {
+		return m.MaxTtl
+	}
+	return 0
+}
+
+// CacheSnapshot is the content of the cache file.
+type CacheSnapshot struct {
+	Record []*CacheSnapshot_Record `protobuf:"bytes,1,rep,name=record" json:"record,omitempty"`
+}
+
+func (m *CacheSnapshot) Reset()                    { *m = CacheSnapshot{} }
+func (m *CacheSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*CacheSnapshot) ProtoMessage()               {}
+func (*CacheSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *CacheSnapshot) GetRecord() []*CacheSnapshot_Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+type CacheSnapshot_Record struct {
+	Domain string   `protobuf:"bytes,1,opt,name=domain" json:"domain,omitempty"`
+	Ipv6   bool     `protobuf:"varint,2,opt,name=ipv6" json:"ipv6,omitempty"`
+	Ip     [][]byte `protobuf:"bytes,3,rep,name=ip,proto3" json:"ip,omitempty"`
+	// Expiration time in Unix seconds.
+	Expire int64 `protobuf:"varint,4,opt,name=expire" json:"expire,omitempty"`
+	// TTL in seconds when the record was fetched.
+	Ttl uint32 `protobuf:"varint,5,opt,name=ttl" json:"ttl,omitempty"`
+	// Whether the domain does not exist, as answered by the name server.
+	NotExist bool `protobuf:"varint,6,opt,name=not_exist,json=notExist" json:"not_exist,omitempty"`
+}
+
+func (m *CacheSnapshot_Record) Reset()                    { *m = CacheSnapshot_Record{} }
+func (m *CacheSnapshot_Record) String() string            { return proto.CompactTextString(m) }
+func (*CacheSnapshot_Record) ProtoMessage()               {}
+func (*CacheSnapshot_Record) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7, 0} }
+
+func (m *CacheSnapshot_Record) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *CacheSnapshot_Record) GetIpv6() bool {
+	if m != nil {
+		return m.Ipv6
+	}
+	return false
+}
+
+func (m *CacheSnapshot_Record) GetIp() [][]byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *CacheSnapshot_Record) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+func (m *CacheSnapshot_Record) GetTtl() uint32 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *CacheSnapshot_Record) GetNotExist() bool {
+	if m != nil {
+		return m.NotExist
+	}
+	return false
+}
+
 type Config struct {
-	// Nameservers used by this DNS. Only traditional UDP servers are support at the moment.
+	// Nameservers used by this DNS. TCP endpoints are queried over TCP, others over UDP.
 	// A special value 'localhost' as a domain address can be set to use DNS on local system.
 	NameServers []*v2ray_core_common_net2.Endpoint `protobuf:"bytes,1,rep,name=NameServers" json:"NameServers,omitempty"`
-	// Static hosts. Domain to IP.
+	// Static hosts. Domain to IP, or domain to another domain as an alias.
 	Hosts map[string]*v2ray_core_common_net.IPOrDomain `protobuf:"bytes,2,rep,name=Hosts" json:"Hosts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Which types of records are queried for a domain.
+	QueryStrategy Config_QueryStrategy `protobuf:"varint,3,opt,name=query_strategy,json=queryStrategy,enum=v2ray.core.app.dns.Config_QueryStrategy" json:"query_strategy,omitempty"`
+	// Additional nameservers, queried after the ones in NameServers.
+	NameServer []*NameServer `protobuf:"bytes,4,rep,name=name_server,json=nameServer" json:"name_server,omitempty"`
+	// If set, the DNS server inbound answers queries with fake IPs from this pool, and connections to these IPs
+	// are dispatched to their domains.
+	FakeIpPool *FakeIPPool `protobuf:"bytes,5,opt,name=fake_ip_pool,json=fakeIpPool" json:"fake_ip_pool,omitempty"`
+	// Settings of the record cache.
+	Cache *CacheConfig `protobuf:"bytes,6,opt,name=cache" json:"cache,omitempty"`
+	// Static hosts matched by patterns. Exact matches in Hosts and here come first, then subdomain matches from the
+	// most specific one, then keyword and regex matches in order.
+	StaticHosts []*HostMapping `protobuf:"bytes,7,rep,name=static_hosts,json=staticHosts" json:"static_hosts,omitempty"`
+	// Files in the format of /etc/hosts, loaded as exact static hosts when the config is applied.
+	HostsFile []string `protobuf:"bytes,8,rep,name=hosts_file,json=hostsFile" json:"hosts_file,omitempty"`
+	// Domains matching any of these rules are blocked before static hosts and name servers. The first matching rule
+	// decides the answer.
+	BlockRule []*BlockRule `protobuf:"bytes,9,rep,name=block_rule,json=blockRule" json:"block_rule,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *Config) GetNameServers() []*v2ray_core_common_net2.Endpoint {
 	if m != nil {
@@ -44,31 +490,139 @@ func (m *Config) GetHosts() map[string]*v2ray_core_common_net.IPOrDomain {
 	return nil
 }
 
+func (m *Config) GetQueryStrategy() Config_QueryStrategy {
+	if m != nil {
+		return m.QueryStrategy
+	}
+	return Config_USE_IP4
+}
+
+func (m *Config) GetNameServer() []*NameServer {
+	if m != nil {
+		return m.NameServer
+	}
+	return nil
+}
+
+func (m *Config) GetFakeIpPool() *FakeIPPool {
+	if m != nil {
+		return m.FakeIpPool
+	}
+	return nil
+}
+
+func (m *Config) GetCache() *CacheConfig {
+	if m != nil {
+		return m.Cache
+	}
+	return nil
+}
+
+func (m *Config) GetStaticHosts() []*HostMapping {
+	if m != nil {
+		return m.StaticHosts
+	}
+	return nil
+}
+
+func (m *Config) GetHostsFile() []string {
+	if m != nil {
+		return m.HostsFile
+	}
+	return nil
+}
+
+func (m *Config) GetBlockRule() []*BlockRule {
+	if m != nil {
+		return m.BlockRule
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterType((*Domain)(nil), "v2ray.core.app.dns.Domain")
+	proto.RegisterType((*CIDR)(nil), "v2ray.core.app.dns.CIDR")
+	proto.RegisterType((*NameServer)(nil), "v2ray.core.app.dns.NameServer")
+	proto.RegisterType((*HostMapping)(nil), "v2ray.core.app.dns.HostMapping")
+	proto.RegisterType((*BlockRule)(nil), "v2ray.core.app.dns.BlockRule")
+	proto.RegisterType((*FakeIPPool)(nil), "v2ray.core.app.dns.FakeIPPool")
+	proto.RegisterType((*CacheConfig)(nil), "v2ray.core.app.dns.CacheConfig")
+	proto.RegisterType((*CacheSnapshot)(nil), "v2ray.core.app.dns.CacheSnapshot")
+	proto.RegisterType((*CacheSnapshot_Record)(nil), "v2ray.core.app.dns.CacheSnapshot.Record")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.dns.Config")
+	proto.RegisterEnum("v2ray.core.app.dns.Domain_Type", Domain_Type_name, Domain_Type_value)
+	proto.RegisterEnum("v2ray.core.app.dns.NameServer_Type", NameServer_Type_name, NameServer_Type_value)
+	proto.RegisterEnum("v2ray.core.app.dns.Config_QueryStrategy", Config_QueryStrategy_name, Config_QueryStrategy_value)
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/app/dns/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 290 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x90, 0xcd, 0x4a, 0x03, 0x31,
-	0x10, 0x80, 0xd9, 0x2d, 0x2d, 0x98, 0xbd, 0x48, 0x0e, 0xb2, 0xf4, 0x62, 0x55, 0xc4, 0x82, 0x30,
-	0x81, 0xf5, 0xa0, 0xe8, 0x41, 0xfa, 0x07, 0x7a, 0xd1, 0xb2, 0x82, 0x07, 0x3d, 0xc5, 0x4d, 0x94,
-	0x45, 0x33, 0x13, 0x92, 0x58, 0xd8, 0x57, 0xf2, 0xbd, 0x7c, 0x0f, 0xd9, 0x0d, 0x62, 0x51, 0x7b,
-	0x0b, 0xe1, 0xfb, 0x32, 0x5f, 0x86, 0x1d, 0xac, 0x0a, 0x27, 0x1b, 0xa8, 0xc8, 0x88, 0x8a, 0x9c,
-	0x16, 0xd2, 0x5a, 0xa1, 0xd0, 0x8b, 0x8a, 0xf0, 0xb9, 0x7e, 0x01, 0xeb, 0x28, 0x10, 0xe7, 0xdf,
-	0x90, 0xd3, 0x20, 0xad, 0x05, 0x85, 0x7e, 0x78, 0xf4, 0x4b, 0xac, 0xc8, 0x18, 0x42, 0x81, 0x3a,
-	0x08, 0xa9, 0x94, 0xd3, 0xde, 0x47, 0x79, 0x78, 0xbc, 0x19, 0x54, 0xda, 0x87, 0x1a, 0x65, 0xa8,
-	0x09, 0x23, 0xbc, 0xff, 0x99, 0xb0, 0xc1, 0xac, 0x1b, 0xcd, 0x27, 0x2c, 0xbb, 0x91, 0x46, 0xdf,
-	0x69, 0xb7, 0xd2, 0xce, 0xe7, 0xc9, 0xa8, 0x37, 0xce, 0x8a, 0x5d, 0x58, 0x4b, 0x89, 0x2f, 0x01,
-	0xea, 0x00, 0x0b, 0x54, 0x96, 0x6a, 0x0c, 0xe5, 0xba, 0xc3, 0x2f, 0x58, 0xff, 0x8a, 0x7c, 0xf0,
-	0x79, 0xda, 0xc9, 0x87, 0xf0, 0xf7, 0x1f, 0x10, 0xa7, 0x41, 0xc7, 0x2d, 0x30, 0xb8, 0xa6, 0x8c,
-	0xce, 0xf0, 0x91, 0xb1, 0x9f, 0x4b, 0xbe, 0xcd, 0x7a, 0xaf, 0xba, 0xc9, 0x93, 0x51, 0x32, 0xde,
-	0x2a, 0xdb, 0x23, 0x3f, 0x65, 0xfd, 0x95, 0x7c, 0x7b, 0xd7, 0x79, 0x3a, 0x4a, 0xc6, 0x59, 0xb1,
-	0xb7, 0xa1, 0xec, 0x7a, 0x79, 0xeb, 0xe6, 0x64, 0x64, 0x8d, 0x65, 0xe4, 0xcf, 0xd3, 0xb3, 0x64,
-	0x7a, 0xc9, 0x76, 0x2a, 0x32, 0xff, 0xf4, 0x4c, 0xb3, 0x18, 0xb4, 0x6c, 0xd7, 0xf1, 0xd0, 0x53,
-	0xe8, 0x3f, 0x52, 0x7e, 0x5f, 0x94, 0xb2, 0x81, 0x59, 0x8b, 0x4d, 0xac, 0x85, 0x39, 0xfa, 0xa7,
-	0x41, 0xb7, 0xaf, 0x93, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4e, 0x18, 0xf5, 0xa2, 0xc0, 0x01,
-	0x00, 0x00,
+	// 1049 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0xd1, 0x8e, 0x1b, 0x35,
+	0x14, 0xed, 0x64, 0x92, 0xec, 0xe6, 0xce, 0x26, 0x1a, 0x59, 0x68, 0x89, 0x82, 0x4a, 0xc3, 0x54,
+	0x15, 0x41, 0x54, 0x13, 0x29, 0xa5, 0x2d, 0x05, 0xa4, 0xb6, 0xbb, 0x9b, 0x55, 0x23, 0x95, 0x36,
+	0x38, 0x29, 0x0f, 0xf0, 0x30, 0xcc, 0xce, 0x78, 0x37, 0xd6, 0x4e, 0x6c, 0xd7, 0x76, 0x56, 0x09,
+	0x9f, 0x00, 0x2f, 0xf0, 0x1b, 0x7c, 0x13, 0x12, 0x9f, 0x02, 0xb2, 0x67, 0xb2, 0x49, 0xda, 0x64,
+	0x41, 0xbc, 0xdd, 0xeb, 0x39, 0xc7, 0xf6, 0x3d, 0xf7, 0xf8, 0x26, 0x70, 0xf7, 0xaa, 0x27, 0xe3,
+	0x45, 0x98, 0xf0, 0x69, 0x37, 0xe1, 0x92, 0x74, 0x63, 0x21, 0xba, 0x29, 0x53, 0xdd, 0x84, 0xb3,
+	0x73, 0x7a, 0x11, 0x0a, 0xc9, 0x35, 0x47, 0x68, 0x09, 0x92, 0x24, 0x8c, 0x85, 0x08, 0x53, 0xa6,
+	0x5a, 0x9f, 0xbe, 0x43, 0x4c, 0xf8, 0x74, 0xca, 0x59, 0x97, 0x11, 0xdd, 0x8d, 0xd3, 0x54, 0x12,
+	0xa5, 0x72, 0x72, 0xeb, 0xf3, 0xdd, 0xc0, 0x94, 0x28, 0x4d, 0x59, 0xac, 0x29, 0x67, 0x05, 0xb8,
+	0xf7, 0x0e, 0x58, 0xcb, 0x98, 0x29, 0xc1, 0xa5, 0xee, 0x52, 0xa6, 0x89, 0x34, 0x24, 0x9d, 0x6d,
+	0xde, 0x2e, 0xf8, 0xc5, 0x81, 0xea, 0x09, 0x9f, 0xc6, 0x94, 0xa1, 0x07, 0x50, 0xd6, 0x0b, 0x41,
+	0x9a, 0x4e, 0xdb, 0xe9, 0x34, 0x7a, 0x77, 0xc2, 0xf7, 0xef, 0x1d, 0xe6, 0xc8, 0x70, 0xbc, 0x10,
+	0x04, 0x5b, 0x30, 0xfa, 0x00, 0x2a, 0x57, 0x71, 0x36, 0x23, 0xcd, 0x52, 0xdb, 0xe9, 0xd4, 0x70,
+	0x9e, 0x04, 0x0f, 0xa1, 0x6c, 0x30, 0xa8, 0x06, 0x95, 0x61, 0x16, 0x53, 0xe6, 0xdf, 0x32, 0x21,
+	0x26, 0x17, 0x64, 0xee, 0x3b, 0xa8, 0x0e, 0xb5, 0xd1, 0xec, 0x2c, 0xb5, 0x7b, 0xf9, 0x25, 0xb4,
+	0x0f, 0xe5, 0xd3, 0x59, 0x96, 0xf9, 0x6e, 0x10, 0x42, 0xf9, 0x78, 0x70, 0x82, 0x51, 0x03, 0x4a,
+	0x54, 0xd8, 0x7b, 0x1c, 0xe0, 0x12, 0x15, 0xe8, 0x10, 0xaa, 0x42, 0x92, 0x73, 0x3a, 0xb7, 0xa7,
+	0xd4, 0x71, 0x91, 0x05, 0x7f, 0x97, 0x00, 0x5e, 0xc5, 0x53, 0x32, 0x22, 0xf2, 0x8a, 0x48, 0xf4,
+	0x78, 0xa3, 0x80, 0xbb, 0xdb, 0x0a, 0x58, 0xa1, 0xd7, 0x8b, 0x78, 0x02, 0x7b, 0x85, 0xec, 0xf6,
+	0x00, 0x6f, 0xb3, 0xf8, 0x5c, 0xf3, 0x90, 0x11, 0x1d, 0xf6, 0x59, 0x2a, 0x38, 0x65, 0x1a, 0x2f,
+	0xf1, 0xe8, 0x25, 0x1c, 0xe8, 0x4c, 0x45, 0x8a, 0x68, 0x4d, 0xd9, 0x85, 0x6a, 0xba, 0x96, 0xff,
+	0xd9, 0x3a, 0xff, 0xba, 0x0d, 0xe1, 0xb2, 0x0d, 0xa1, 0xce, 0x54, 0x78, 0x6c, 0xdb, 0x80, 0x3d,
+	0x9d, 0xa9, 0x51, 0xc1, 0x46, 0x08, 0xca, 0x22, 0xd6, 0x93, 0x66, 0xd9, 0x8a, 0x69, 0x63, 0xd4,
+	0x83, 0x6a, 0x2e, 0x55, 0xb3, 0xd2, 0x76, 0x3b, 0x5e, 0xaf, 0xb5, 0xbb, 0x31, 0xb8, 0x40, 0xa2,
+	0x27, 0xe0, 0x91, 0xb9, 0x20, 0x89, 0x26, 0x69, 0x44, 0x45, 0xb3, 0x6a, 0x89, 0xcd, 0x6d, 0x44,
+	0xa3, 0x37, 0x86, 0x25, 0x78, 0x20, 0x82, 0xfb, 0x45, 0xeb, 0xf6, 0xc0, 0x7d, 0x73, 0x32, 0xf4,
+	0x6f, 0x99, 0x60, 0x7c, 0x3c, 0xf4, 0x1d, 0x1b, 0xbc, 0x1c, 0xf9, 0x25, 0xd3, 0xca, 0x17, 0xe3,
+	0xf1, 0x70, 0xe4, 0xbb, 0xc1, 0xef, 0x0e, 0x78, 0x2f, 0xb8, 0xd2, 0xdf, 0xc6, 0x42, 0x50, 0x76,
+	0xf1, 0xff, 0x3c, 0x74, 0x78, 0x5d, 0x61, 0x6e, 0xa2, 0x65, 0x15, 0xb9, 0x0d, 0xdc, 0xb6, 0x5b,
+	0xd8, 0xe0, 0x1e, 0x34, 0x84, 0xe4, 0x73, 0x4a, 0xd2, 0xa8, 0xc0, 0xe7, 0x3a, 0xd5, 0x8b, 0xd5,
+	0x7c, 0xe7, 0xe0, 0x27, 0xa8, 0x1d, 0x65, 0x3c, 0xb9, 0xc4, 0xb3, 0x8c, 0xac, 0xa9, 0xe7, 0xfc,
+	0x67, 0xf5, 0xee, 0x80, 0xa7, 0x28, 0xbb, 0x9c, 0xf0, 0x8c, 0x18, 0xf5, 0x4a, 0xf6, 0x02, 0xb0,
+	0x5c, 0x1a, 0x88, 0xe0, 0x15, 0xc0, 0x69, 0x7c, 0x49, 0x06, 0xc3, 0x21, 0xe7, 0x19, 0xba, 0x0f,
+	0xe5, 0x84, 0xa6, 0xd2, 0xd6, 0x7c, 0x93, 0xca, 0x16, 0x65, 0x5a, 0xac, 0xe8, 0xcf, 0xa4, 0x70,
+	0xb2, 0x8d, 0x83, 0xdf, 0x1c, 0xf0, 0x8e, 0xe3, 0x64, 0x42, 0x72, 0x4f, 0x18, 0xcc, 0x39, 0xcd,
+	0x72, 0x15, 0x6b, 0xd8, 0xc6, 0xa8, 0x05, 0xfb, 0xc6, 0xf5, 0x44, 0x27, 0x13, 0xcb, 0xdd, 0xc7,
+	0xd7, 0xb9, 0xbd, 0xb0, 0x31, 0x75, 0xa4, 0x74, 0x9c, 0x11, 0xeb, 0xc1, 0x3a, 0x06, 0xbb, 0x34,
+	0x32, 0x2b, 0xe8, 0x43, 0xd8, 0x9b, 0x52, 0x16, 0x69, 0x9d, 0x59, 0xc9, 0xea, 0xb8, 0x3a, 0xa5,
+	0x6c, 0xac, 0x33, 0xfb, 0x21, 0x9e, 0xdb, 0x0f, 0x95, 0xe2, 0x43, 0x3c, 0x1f, 0xeb, 0x2c, 0xf8,
+	0xcb, 0x81, 0xba, 0xbd, 0xd2, 0x88, 0xc5, 0x42, 0x4d, 0xb8, 0x46, 0xcf, 0xa0, 0x2a, 0x49, 0xc2,
+	0x65, 0x5a, 0x28, 0xd9, 0xd9, 0x5a, 0xe8, 0x3a, 0x25, 0xc4, 0x16, 0x8f, 0x0b, 0x5e, 0xeb, 0x57,
+	0x07, 0xaa, 0xf9, 0xd2, 0x5a, 0xcb, 0x9d, 0x8d, 0x96, 0x23, 0x28, 0x53, 0x71, 0xf5, 0xa8, 0xa8,
+	0xd0, 0xc6, 0xef, 0xd9, 0xe0, 0x10, 0xaa, 0x64, 0x2e, 0xa8, 0x24, 0xb6, 0x16, 0x17, 0x17, 0x19,
+	0xf2, 0xc1, 0x5d, 0xd5, 0x61, 0x42, 0xf4, 0x11, 0xd4, 0x18, 0xd7, 0x11, 0x99, 0x53, 0xa5, 0x9b,
+	0xd5, 0x5c, 0x34, 0xc6, 0x75, 0xdf, 0xe4, 0xc1, 0x9f, 0x15, 0xa8, 0x16, 0x7a, 0x3f, 0x07, 0x6f,
+	0x35, 0x18, 0x54, 0x51, 0xdf, 0xbf, 0xce, 0x80, 0x75, 0x0e, 0xfa, 0x1a, 0x2a, 0xe6, 0x1d, 0x28,
+	0xeb, 0x16, 0xaf, 0x77, 0x6f, 0xab, 0x38, 0xf6, 0xb4, 0xd0, 0xe2, 0xfa, 0x4c, 0xcb, 0x05, 0xce,
+	0x39, 0xe8, 0x35, 0x34, 0xde, 0xce, 0x88, 0x5c, 0x44, 0x4a, 0xcb, 0x58, 0x93, 0x8b, 0x85, 0x6d,
+	0x61, 0xa3, 0xd7, 0xb9, 0x61, 0x97, 0xef, 0x0c, 0x61, 0x54, 0xe0, 0x71, 0xfd, 0xed, 0x7a, 0x8a,
+	0x9e, 0x82, 0xc7, 0xe2, 0x29, 0x89, 0xac, 0x05, 0x64, 0xb3, 0x6c, 0xef, 0xf4, 0xf1, 0xcd, 0x03,
+	0x11, 0x03, 0xbb, 0x8e, 0xd1, 0x33, 0x38, 0x38, 0x8f, 0x2f, 0x8d, 0xfd, 0x23, 0xc1, 0x79, 0x2e,
+	0xea, 0x8e, 0x1d, 0x56, 0x2f, 0x01, 0x83, 0xe1, 0x0c, 0x84, 0x89, 0xd1, 0x43, 0xa8, 0x24, 0xc6,
+	0x0c, 0x56, 0x77, 0xaf, 0x77, 0x67, 0xa7, 0x5b, 0x8a, 0x39, 0x98, 0xa3, 0xd1, 0x11, 0x1c, 0x28,
+	0x1d, 0x6b, 0x9a, 0x44, 0x13, 0x2b, 0xe7, 0x5e, 0xdb, 0xdd, 0xc5, 0x5e, 0x9b, 0x3b, 0xd8, 0xcb,
+	0x49, 0xb9, 0x9c, 0xb7, 0x01, 0x2c, 0x39, 0xb2, 0x8f, 0x68, 0xbf, 0xed, 0x76, 0x6a, 0xb8, 0x66,
+	0x57, 0x4e, 0xcd, 0x4b, 0xfa, 0x06, 0xe0, 0xcc, 0xcc, 0x87, 0x48, 0xce, 0x32, 0xd2, 0xac, 0xd9,
+	0x03, 0x6e, 0x6f, 0x3b, 0xe0, 0x7a, 0x8a, 0xe0, 0xda, 0xd9, 0x32, 0x6c, 0xfd, 0x08, 0xb0, 0x6a,
+	0xa0, 0xf1, 0xdc, 0x25, 0x59, 0x14, 0x26, 0x36, 0x21, 0x7a, 0xbc, 0xfe, 0x83, 0xe8, 0xf5, 0x3e,
+	0xd9, 0xe1, 0xa2, 0xc1, 0xf0, 0xb5, 0x2c, 0xc6, 0x4e, 0x8e, 0xff, 0xaa, 0xf4, 0xa5, 0x13, 0x0c,
+	0xa0, 0xbe, 0xd1, 0x57, 0xe4, 0xc1, 0xde, 0x9b, 0x51, 0x3f, 0x1a, 0x0c, 0xbf, 0xf0, 0x6f, 0xad,
+	0x92, 0x47, 0xbe, 0x83, 0x1a, 0x00, 0x43, 0xdc, 0x3f, 0xed, 0x63, 0xfb, 0xb1, 0xb4, 0x91, 0x3f,
+	0xf2, 0xdd, 0xa3, 0xa7, 0x70, 0x98, 0xf0, 0xe9, 0x96, 0xb2, 0x8e, 0xbc, 0x5c, 0xf1, 0xa1, 0xe4,
+	0x9a, 0xff, 0xe0, 0xa6, 0x4c, 0xfd, 0x51, 0x42, 0xdf, 0xf7, 0x70, 0xbc, 0x08, 0x8f, 0x0d, 0xec,
+	0xb9, 0x10, 0xe1, 0x09, 0x53, 0x67, 0x55, 0xfb, 0x07, 0xe1, 0xc1, 0x3f, 0x01, 0x00, 0x00, 0xff,
+	0xff, 0x50, 0xf8, 0xd7, 0xf9, 0xe5, 0x08, 0x00, 0x00,
 }
@@ -1,14 +1,67 @@
 package dns
 
 import (
+	"context"
 	"net"
+	"time"
 
 	"v2ray.com/core/app"
+	"v2ray.com/core/common/errors"
+)
+
+var (
+	// ErrBlocked is returned when a domain is blocked and answered as nonexistent.
+	ErrBlocked = errors.New("DNS: Domain is blocked.")
+	// ErrNotExist is returned when the name server answers that a domain does not exist.
+	ErrNotExist = errors.New("DNS: Domain does not exist.")
+	// ErrQueryFailed is returned when no name server answers a query in time.
+	ErrQueryFailed = errors.New("DNS: No name server answers.")
 )
 
 // A Server is a DNS server for responding DNS queries.
 type Server interface {
+	// Get returns the IPs of the given domain, using the query strategy in config.
 	Get(domain string) []net.IP
+	// GetWithStrategy returns the IPs of the given domain, using the given query strategy. Static hosts are
+	// returned only if they are of the queried address family.
+	GetWithStrategy(domain string, strategy Config_QueryStrategy) []net.IP
+}
+
+// A ReloadableServer is a Server that is able to apply a new config at runtime.
+type ReloadableServer interface {
+	Server
+	Reload(config *Config) error
+}
+
+// A ContextServer is a Server that takes the context of the request, so that queries are logged with the inbound
+// and the user asking for the domain. Both methods return ErrBlocked if the domain is blocked without sinkhole IPs,
+// ErrNotExist if the name server answers NXDOMAIN, or ErrQueryFailed if the domain is not cached and no name server
+// answers.
+type ContextServer interface {
+	Server
+	GetContext(ctx context.Context, domain string) ([]net.IP, error)
+	// GetWithStrategyContext also returns how long the IPs may be cached by clients.
+	GetWithStrategyContext(ctx context.Context, domain string, strategy Config_QueryStrategy) ([]net.IP, time.Duration, error)
+}
+
+// GetContext returns the IPs of the given domain from the given server, with the context of the request if the
+// server takes it. It returns ErrBlocked if the server blocks the domain.
+func GetContext(ctx context.Context, server Server, domain string) ([]net.IP, error) {
+	if s, ok := server.(ContextServer); ok {
+		return s.GetContext(ctx, domain)
+	}
+	return server.Get(domain), nil
+}
+
+// A FakeIPServer is a Server that is able to hand out fake IPs for domains.
+type FakeIPServer interface {
+	Server
+	// GetFakeIP returns the fake IP for the given domain, and whether fake IP is enabled for the domain. If the fake
+	// IPs are not of the requested family, no fake IP is handed out, and the returned IP is nil even if fake IP is
+	// enabled. The context of the request is used for logging the query.
+	GetFakeIP(ctx context.Context, domain string, ipv6 bool) (net.IP, bool)
+	// GetFakeDomain returns the domain of the given fake IP, or an empty string if the IP is not a fake IP in use.
+	GetFakeDomain(ip net.IP) string
 }
 
 func FromSpace(space app.Space) Server {
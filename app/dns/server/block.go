@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net"
+
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/errors"
+)
+
+// blockRule is a BlockRule with its domains built into a matcher.
+type blockRule struct {
+	domains  *router.DomainMatcher
+	sinkhole []net.IP
+}
+
+func buildBlockRules(config *dns.Config) ([]*blockRule, error) {
+	rules := make([]*blockRule, 0, len(config.BlockRule))
+	for idx, rule := range config.BlockRule {
+		if len(rule.Domain) == 0 {
+			return nil, errors.New("DNS: No domain in block rule ", idx)
+		}
+		domains := make([]*router.Domain, len(rule.Domain))
+		for i, domain := range rule.Domain {
+			domains[i] = &router.Domain{
+				Type:  router.Domain_Type(domain.Type),
+				Value: domain.Value,
+			}
+		}
+		matcher, err := router.NewDomainMatcher(domains)
+		if err != nil {
+			return nil, errors.Base(err).Message("DNS: Invalid domains in block rule ", idx)
+		}
+		sinkhole := make([]net.IP, 0, len(rule.SinkholeIp))
+		for _, ip := range rule.SinkholeIp {
+			if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+				return nil, errors.New("DNS: Invalid sinkhole IP in block rule ", idx, ": ", ip)
+			}
+			sinkhole = append(sinkhole, net.IP(ip))
+		}
+		rules = append(rules, &blockRule{
+			domains:  matcher,
+			sinkhole: sinkhole,
+		})
+	}
+	return rules, nil
+}
+
+// matchBlockRule returns the first rule blocking the given domain, or nil if the domain is not blocked.
+func matchBlockRule(rules []*blockRule, domain string) *blockRule {
+	for _, rule := range rules {
+		if rule.domains.MatchDomain(domain) {
+			return rule
+		}
+	}
+	return nil
+}
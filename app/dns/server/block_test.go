@@ -0,0 +1,67 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app"
+	v2dns "v2ray.com/core/app/dns"
+	. "v2ray.com/core/app/dns/server"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestCacheServerBlockRules(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &v2dns.Config{
+		Hosts: map[string]*v2net.IPOrDomain{
+			"ads.v2ray.com": v2net.NewIPOrDomain(v2net.ParseAddress("10.0.0.1")),
+		},
+		BlockRule: []*v2dns.BlockRule{
+			{
+				Domain: []*v2dns.Domain{{Type: v2dns.Domain_Subdomain, Value: "malware.example.com"}},
+			},
+			{
+				Domain:     []*v2dns.Domain{{Type: v2dns.Domain_Plain, Value: "ads"}},
+				SinkholeIp: [][]byte{{0, 0, 0, 0}, v2net.ParseAddress("::").IP()},
+			},
+		},
+		FakeIpPool: &v2dns.FakeIPPool{
+			Cidr: &v2dns.CIDR{Ip: []byte{198, 18, 0, 0}, Prefix: 15},
+		},
+	}
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(new(directDispatcher))).IsNil()
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ips, err := server.GetContext(context.Background(), "www.malware.example.com")
+	assert.Error(err).Equals(v2dns.ErrBlocked)
+	assert.Int(len(ips)).Equals(0)
+	assert.Int(len(server.Get("malware.example.com"))).Equals(0)
+	_, err = v2dns.GetContext(context.Background(), server, "malware.example.com")
+	assert.Error(err).Equals(v2dns.ErrBlocked)
+
+	ips, ttl, err := server.GetWithStrategyContext(context.Background(), "ads.v2ray.com", v2dns.Config_USE_IP6)
+	assert.Error(err).IsNil()
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("::")
+	assert.Int64(int64(ttl)).Equals(int64(StaticTTL))
+	ips = server.Get("ads.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("0.0.0.0")
+
+	for domain, expected := range map[string]int{
+		"malware.example.com": 0,
+		"ads.v2ray.com":       0,
+		"v2ray.com":           4,
+	} {
+		ip, _ := server.GetFakeIP(context.Background(), domain, false)
+		assert.Int(len(ip)).Equals(expected)
+	}
+
+	config.BlockRule = append(config.BlockRule, &v2dns.BlockRule{})
+	assert.Error(server.Reload(config)).IsNotNil()
+}
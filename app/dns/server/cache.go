@@ -0,0 +1,239 @@
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+)
+
+const (
+	// CacheSaveInterval is how often the cache is saved to the cache file.
+	CacheSaveInterval = time.Minute * 10
+	// PrefetchHits is the number of hits for a record to be refreshed before it expires, when prefetch is enabled.
+	PrefetchHits = 3
+)
+
+// CachedRecord is an ARecord in the cache, with the usage of it since it was fetched.
+type CachedRecord struct {
+	*ARecord
+	TTL        time.Duration
+	hits       int32
+	refreshing int32
+}
+
+// newCachedRecord returns a record to cache at the given time for the given one, with its TTL clamped by the given
+// config.
+func newCachedRecord(record *ARecord, config *dns.CacheConfig, now time.Time) *CachedRecord {
+	ttl := time.Until(record.Expire)
+	if minTTL := time.Duration(config.GetMinTtl()) * time.Second; minTTL > 0 && ttl < minTTL {
+		ttl = minTTL
+	}
+	if maxTTL := time.Duration(config.GetMaxTtl()) * time.Second; maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return &CachedRecord{
+		ARecord: &ARecord{
+			IPs:      record.IPs,
+			Expire:   now.Add(ttl),
+			NotExist: record.NotExist,
+		},
+		TTL: ttl,
+	}
+}
+
+// hit records a use of this record at the given time, and returns true if it should be refreshed now for prefetching.
+// A record is prefetched when it has been used PrefetchHits times, and is in the last tenth of its TTL.
+func (r *CachedRecord) hit(now time.Time) bool {
+	hits := atomic.AddInt32(&r.hits, 1)
+	return hits >= PrefetchHits && r.Expire.Sub(now) < r.TTL/10
+}
+
+// staleDuration returns how long an expired record is still returned, in the given config.
+func staleDuration(config *dns.CacheConfig) time.Duration {
+	return time.Duration(config.GetServeStale()) * time.Second
+}
+
+// loadCache returns the records in the given cache file. Records expired for longer than the serve-stale window
+// at the given time are skipped.
+func loadCache(file string, config *dns.CacheConfig, now time.Time) (map[string]*DomainRecord, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := new(dns.CacheSnapshot)
+	if err := proto.Unmarshal(content, snapshot); err != nil {
+		return nil, errors.Base(err).Message("DNS: Invalid cache file: ", file)
+	}
+
+	deadline := now.Add(-staleDuration(config))
+	records := make(map[string]*DomainRecord)
+	for _, r := range snapshot.Record {
+		expire := time.Unix(r.Expire, 0)
+		if expire.Before(deadline) {
+			continue
+		}
+		cached := &CachedRecord{
+			ARecord: &ARecord{
+				IPs:      make([]net.IP, 0, len(r.Ip)),
+				Expire:   expire,
+				NotExist: r.NotExist,
+			},
+			TTL: time.Duration(r.Ttl) * time.Second,
+		}
+		for _, ip := range r.Ip {
+			cached.IPs = append(cached.IPs, net.IP(ip))
+		}
+		record, found := records[r.Domain]
+		if !found {
+			record = new(DomainRecord)
+			records[r.Domain] = record
+		}
+		if r.Ipv6 {
+			record.AAAA = cached
+		} else {
+			record.A = cached
+		}
+	}
+	return records, nil
+}
+
+// snapshot returns the records in the cache that are still returned, for saving to the cache file.
+func (v *CacheServer) snapshot() *dns.CacheSnapshot {
+	v.RLock()
+	defer v.RUnlock()
+
+	deadline := v.now().Add(-staleDuration(v.cache))
+	snapshot := new(dns.CacheSnapshot)
+	add := func(domain string, record *CachedRecord, qtype uint16) {
+		if record == nil || record.Expire.Before(deadline) {
+			return
+		}
+		r := &dns.CacheSnapshot_Record{
+			Domain:   domain,
+			Ipv6:     qtype == dnsmsg.TypeAAAA,
+			Expire:   record.Expire.Unix(),
+			Ttl:      uint32(record.TTL / time.Second),
+			NotExist: record.NotExist,
+		}
+		for _, ip := range record.IPs {
+			r.Ip = append(r.Ip, []byte(ip))
+		}
+		snapshot.Record = append(snapshot.Record, r)
+	}
+	for domain, record := range v.records {
+		add(domain, record.A, dnsmsg.TypeA)
+		add(domain, record.AAAA, dnsmsg.TypeAAAA)
+	}
+	return snapshot
+}
+
+// SaveCache writes the cached records to the cache file in config. It does nothing if there is no cache file.
+func (v *CacheServer) SaveCache() error {
+	v.RLock()
+	file := v.cache.GetFile()
+	v.RUnlock()
+	if len(file) == 0 {
+		return nil
+	}
+	content, err := proto.Marshal(v.snapshot())
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so the cache file is never left half written.
+	tmpFile := file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, content, 0644); err != nil {
+		return errors.Base(err).Message("DNS: Failed to write cache file: ", tmpFile)
+	}
+	if err := os.Rename(tmpFile, file); err != nil {
+		return errors.Base(err).Message("DNS: Failed to write cache file: ", file)
+	}
+	log.Debug("DNS: Saved cache to ", file)
+	return nil
+}
+
+// validRecords returns the records in the given cache that are still returned at the given time, in the given config.
+func validRecords(records map[string]*DomainRecord, config *dns.CacheConfig, now time.Time) map[string]*DomainRecord {
+	deadline := now.Add(-staleDuration(config))
+	valid := func(record *CachedRecord) *CachedRecord {
+		if record == nil || record.Expire.Before(deadline) {
+			return nil
+		}
+		return record
+	}
+	kept := make(map[string]*DomainRecord, len(records))
+	for domain, record := range records {
+		a, aaaa := valid(record.A), valid(record.AAAA)
+		if a != nil || aaaa != nil {
+			kept[domain] = &DomainRecord{A: a, AAAA: aaaa}
+		}
+	}
+	return kept
+}
+
+// Start saves the cache to the cache file periodically, if there is one. Saving starts or stops when the cache file
+// is set or unset by Reload.
+func (v *CacheServer) Start() error {
+	v.Lock()
+	defer v.Unlock()
+
+	v.started = true
+	v.updateSaving()
+	return nil
+}
+
+// updateSaving starts or stops saving the cache periodically, as the cache file in the current config is set or not.
+// It must be called with the lock held.
+func (v *CacheServer) updateSaving() {
+	hasFile := len(v.cache.GetFile()) > 0
+	if v.started && hasFile && v.done == nil {
+		v.done = v.saveCachePeriodically()
+	} else if (!v.started || !hasFile) && v.done != nil {
+		close(v.done)
+		v.done = nil
+	}
+}
+
+// saveCachePeriodically saves the cache every CacheSaveInterval until the returned channel is closed.
+func (v *CacheServer) saveCachePeriodically() chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(CacheSaveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := v.SaveCache(); err != nil {
+					log.Warning("DNS: Failed to save cache: ", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return done
+}
+
+// Close stops saving the cache periodically, and saves it for the last time.
+func (v *CacheServer) Close() {
+	v.Lock()
+	saving := v.done != nil
+	v.started = false
+	v.updateSaving()
+	v.Unlock()
+
+	if !saving {
+		return
+	}
+	if err := v.SaveCache(); err != nil {
+		log.Warning("DNS: Failed to save cache: ", err)
+	}
+}
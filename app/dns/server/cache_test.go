@@ -0,0 +1,203 @@
+package server_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/miekg/dns"
+	"v2ray.com/core/app"
+	v2dns "v2ray.com/core/app/dns"
+	. "v2ray.com/core/app/dns/server"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func newCacheServer(t *testing.T, dnsServer *tcpDNSServer, cache *v2dns.CacheConfig) *CacheServer {
+	dest := dnsServer.Destination()
+	config := &v2dns.Config{
+		NameServers: []*v2net.Endpoint{
+			{
+				Network: v2net.Network_TCP,
+				Address: v2net.NewIPOrDomain(dest.Address),
+				Port:    uint32(dest.Port),
+			},
+		},
+		Cache: cache,
+	}
+	space := app.NewSpace()
+	if err := space.AddApplication(new(directDispatcher)); err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := space.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+// fakeClock is a clock for the cache that only moves forward when it is advanced.
+type fakeClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(server *CacheServer) *fakeClock {
+	clock := &fakeClock{now: time.Now()}
+	server.SetClock(clock.Now)
+	return clock
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// waitQueries waits for the given DNS server to receive the given number of queries.
+func waitQueries(dnsServer *tcpDNSServer, queries int32) int32 {
+	for i := 0; i < 50 && atomic.LoadInt32(&dnsServer.queries) < queries; i++ {
+		time.Sleep(time.Millisecond * 20)
+	}
+	return atomic.LoadInt32(&dnsServer.queries)
+}
+
+func TestCacheServeStale(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+	server := newCacheServer(t, dnsServer, &v2dns.CacheConfig{
+		ServeStale: 60,
+		MaxTtl:     1,
+	})
+	clock := newFakeClock(server)
+
+	assert.String(server.Get("v2ray.com")[0].String()).Equals("10.0.0.1")
+	assert.Int(int(atomic.LoadInt32(&dnsServer.queries))).Equals(1)
+
+	clock.Advance(time.Second * 2)
+	assert.Int(len(server.GetCached("v2ray.com.", dns.TypeA))).Equals(0)
+	ips := server.Get("v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("10.0.0.1")
+	assert.Int(int(waitQueries(dnsServer, 2))).Equals(2)
+	for i := 0; i < 50 && server.GetCached("v2ray.com.", dns.TypeA) == nil; i++ {
+		time.Sleep(time.Millisecond * 20)
+	}
+	assert.Int(len(server.GetCached("v2ray.com.", dns.TypeA))).Equals(1)
+}
+
+func TestCachePrefetch(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+	server := newCacheServer(t, dnsServer, &v2dns.CacheConfig{
+		Prefetch: true,
+		MaxTtl:   10,
+	})
+	clock := newFakeClock(server)
+
+	for i := 0; i < PrefetchHits; i++ {
+		server.Get("v2ray.com")
+	}
+	assert.Int(int(atomic.LoadInt32(&dnsServer.queries))).Equals(1)
+
+	clock.Advance(time.Millisecond * 9500)
+	assert.String(server.Get("v2ray.com")[0].String()).Equals("10.0.0.1")
+	assert.Int(int(waitQueries(dnsServer, 2))).Equals(2)
+}
+
+func TestCacheFile(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-dns-cache")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+	cache := &v2dns.CacheConfig{
+		File:   filepath.Join(dir, "dns.cache"),
+		MinTtl: 600,
+	}
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+	server := newCacheServer(t, dnsServer, cache)
+	assert.Error(server.Start()).IsNil()
+	assert.String(server.Get("v2ray.com")[0].String()).Equals("10.0.0.1")
+	server.Close()
+
+	content, err := ioutil.ReadFile(cache.File)
+	assert.Error(err).IsNil()
+	snapshot := new(v2dns.CacheSnapshot)
+	assert.Error(proto.Unmarshal(content, snapshot)).IsNil()
+	assert.Int(len(snapshot.Record)).Equals(1)
+	assert.String(snapshot.Record[0].Domain).Equals("v2ray.com.")
+	assert.Uint32(snapshot.Record[0].Ttl).Equals(600)
+
+	server = newCacheServer(t, dnsServer, cache)
+	assert.String(server.Get("v2ray.com")[0].String()).Equals("10.0.0.1")
+	assert.Int(int(atomic.LoadInt32(&dnsServer.queries))).Equals(1)
+}
+
+func TestCacheReload(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-dns-cache")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+	server := newCacheServer(t, dnsServer, &v2dns.CacheConfig{MaxTtl: 10})
+	clock := newFakeClock(server)
+	assert.Error(server.Start()).IsNil()
+	defer server.Close()
+
+	server.Get("v2ray.com")
+	clock.Advance(time.Second * 5)
+	server.Get("v2fly.org")
+	clock.Advance(time.Second * 6)
+
+	dest := dnsServer.Destination()
+	config := &v2dns.Config{
+		NameServers: []*v2net.Endpoint{
+			{
+				Network: v2net.Network_TCP,
+				Address: v2net.NewIPOrDomain(dest.Address),
+				Port:    uint32(dest.Port),
+			},
+		},
+		Cache: &v2dns.CacheConfig{
+			File:   filepath.Join(dir, "dns.cache"),
+			MaxTtl: 10,
+		},
+	}
+	assert.Error(server.Reload(config)).IsNil()
+	assert.Int(len(server.GetCached("v2ray.com.", dns.TypeA))).Equals(0)
+	assert.Int(len(server.GetCached("v2fly.org.", dns.TypeA))).Equals(1)
+
+	// The cache is saved on Close, as the cache file is set by Reload.
+	server.Close()
+	content, err := ioutil.ReadFile(config.Cache.File)
+	assert.Error(err).IsNil()
+	snapshot := new(v2dns.CacheSnapshot)
+	assert.Error(proto.Unmarshal(content, snapshot)).IsNil()
+	assert.Int(len(snapshot.Record)).Equals(1)
+	assert.String(snapshot.Record[0].Domain).Equals("v2fly.org.")
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common/buf"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/ray"
+)
+
+// rayConn is a net.Conn over a connection from the dispatcher.
+type rayConn struct {
+	stream ray.InboundRay
+	reader *buf.BufferToBytesReader
+	writer *buf.BytesToBufferWriter
+}
+
+// dialThroughDispatcher opens a TCP connection to the given destination through the dispatcher, so that the
+// connection is routed in the same way as proxied connections.
+func dialThroughDispatcher(disp dispatcher.Interface, dest v2net.Destination) net.Conn {
+	ctx := proxy.ContextWithDestination(context.Background(), dest)
+	stream := disp.DispatchToOutbound(ctx)
+	return &rayConn{
+		stream: stream,
+		reader: buf.NewBytesReader(stream.InboundOutput()),
+		writer: buf.NewBytesWriter(stream.InboundInput()),
+	}
+}
+
+// Read implements net.Conn.Read().
+func (c *rayConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// Write implements net.Conn.Write().
+func (c *rayConn) Write(b []byte) (int, error) {
+	return c.writer.Write(b)
+}
+
+// Close implements net.Conn.Close().
+func (c *rayConn) Close() error {
+	c.stream.InboundInput().Close()
+	c.stream.InboundOutput().CloseError()
+	return nil
+}
+
+// LocalAddr implements net.Conn.LocalAddr().
+func (c *rayConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: []byte{0, 0, 0, 0}}
+}
+
+// RemoteAddr implements net.Conn.RemoteAddr().
+func (c *rayConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: []byte{0, 0, 0, 0}}
+}
+
+func (c *rayConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *rayConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *rayConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
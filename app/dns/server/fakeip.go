@@ -0,0 +1,142 @@
+package server
+
+import (
+	"container/list"
+	"net"
+	"sync"
+
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/common/errors"
+)
+
+const DefaultFakeIPPoolSize = 65535
+
+type fakeIPEntry struct {
+	domain string
+	ip     net.IP
+}
+
+// FakeIPPool hands out IPs in a network to domains, and maps the IPs back to the domains. When the pool is full,
+// the IP of the least recently used domain is reused.
+type FakeIPPool struct {
+	sync.Mutex
+	network  *net.IPNet
+	capacity int
+	// next is the offset in the network of the next unused IP, and limit is the offset of the last usable IP.
+	next     uint64
+	limit    uint64
+	entries  *list.List
+	byDomain map[string]*list.Element
+	byIP     map[string]*list.Element
+}
+
+func NewFakeIPPool(config *dns.FakeIPPool) (*FakeIPPool, error) {
+	cidr := config.GetCidr()
+	if cidr == nil {
+		return nil, errors.New("DNS|FakeIP: Network is not specified.")
+	}
+	bits := len(cidr.Ip) * 8
+	if (bits != net.IPv4len*8 && bits != net.IPv6len*8) || int(cidr.Prefix) > bits {
+		return nil, errors.New("DNS|FakeIP: Invalid network: ", net.IP(cidr.Ip), "/", cidr.Prefix)
+	}
+	mask := net.CIDRMask(int(cidr.Prefix), bits)
+	network := &net.IPNet{
+		IP:   net.IP(cidr.Ip).Mask(mask),
+		Mask: mask,
+	}
+
+	// The first and the last IPs in the network are not used.
+	hostBits := uint(bits) - uint(cidr.Prefix)
+	if hostBits < 2 {
+		return nil, errors.New("DNS|FakeIP: Network is too small: ", network)
+	}
+	limit := ^uint64(0) - 1
+	if hostBits < 64 {
+		limit = uint64(1)<<hostBits - 2
+	}
+
+	capacity := int(config.Size)
+	if capacity == 0 {
+		capacity = DefaultFakeIPPoolSize
+	}
+	return &FakeIPPool{
+		network:  network,
+		capacity: capacity,
+		next:     1,
+		limit:    limit,
+		entries:  list.New(),
+		byDomain: make(map[string]*list.Element),
+		byIP:     make(map[string]*list.Element),
+	}, nil
+}
+
+// IsIPv4 returns true if the fake IPs are IPv4 addresses.
+func (p *FakeIPPool) IsIPv4() bool {
+	return len(p.network.IP) == net.IPv4len
+}
+
+// offsetIP returns the IP at the given offset in the network.
+func (p *FakeIPPool) offsetIP(offset uint64) net.IP {
+	ip := make(net.IP, len(p.network.IP))
+	copy(ip, p.network.IP)
+	for i := len(ip) - 1; i >= 0 && offset > 0; i-- {
+		sum := uint64(ip[i]) + offset&0xff
+		ip[i] = byte(sum)
+		offset = offset>>8 + sum>>8
+	}
+	return ip
+}
+
+// Get returns the fake IP of the given domain, assigning one if the domain doesn't have it yet.
+func (p *FakeIPPool) Get(domain string) net.IP {
+	p.Lock()
+	defer p.Unlock()
+
+	if element, found := p.byDomain[domain]; found {
+		p.entries.MoveToFront(element)
+		return element.Value.(*fakeIPEntry).ip
+	}
+
+	var ip net.IP
+	if p.entries.Len() < p.capacity && p.next <= p.limit {
+		ip = p.offsetIP(p.next)
+		p.next++
+	} else {
+		oldest := p.entries.Back()
+		entry := oldest.Value.(*fakeIPEntry)
+		p.entries.Remove(oldest)
+		delete(p.byDomain, entry.domain)
+		delete(p.byIP, string(entry.ip))
+		ip = entry.ip
+	}
+
+	element := p.entries.PushFront(&fakeIPEntry{
+		domain: domain,
+		ip:     ip,
+	})
+	p.byDomain[domain] = element
+	p.byIP[string(ip)] = element
+	return ip
+}
+
+// GetDomain returns the domain of the given fake IP, or an empty string if the IP is not assigned to any domain.
+func (p *FakeIPPool) GetDomain(ip net.IP) string {
+	if p.IsIPv4() {
+		ip = ip.To4()
+	} else if ip.To4() != nil {
+		return ""
+	}
+	if ip == nil || !p.network.Contains(ip) {
+		return ""
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	element, found := p.byIP[string(ip)]
+	if !found {
+		return ""
+	}
+	p.entries.MoveToFront(element)
+	return element.Value.(*fakeIPEntry).domain
+}
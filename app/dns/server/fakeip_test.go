@@ -0,0 +1,97 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"v2ray.com/core/app"
+	v2dns "v2ray.com/core/app/dns"
+	. "v2ray.com/core/app/dns/server"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestFakeIPPool(t *testing.T) {
+	assert := assert.On(t)
+
+	pool, err := NewFakeIPPool(&v2dns.FakeIPPool{
+		Cidr: &v2dns.CIDR{Ip: []byte{198, 18, 0, 0}, Prefix: 30},
+	})
+	assert.Error(err).IsNil()
+
+	ip1 := pool.Get("v2ray.com")
+	assert.String(ip1.String()).Equals("198.18.0.1")
+	ip2 := pool.Get("example.com")
+	assert.String(ip2.String()).Equals("198.18.0.2")
+	assert.String(pool.Get("v2ray.com").String()).Equals("198.18.0.1")
+
+	// The network has only two usable IPs, so the least recently used one is reused.
+	assert.String(pool.GetDomain(net.ParseIP("198.18.0.2"))).Equals("example.com")
+	ip3 := pool.Get("github.com")
+	assert.String(ip3.String()).Equals("198.18.0.1")
+	assert.String(pool.GetDomain(ip3)).Equals("github.com")
+	assert.String(pool.GetDomain(ip2)).Equals("example.com")
+
+	assert.String(pool.GetDomain(net.ParseIP("198.18.0.3"))).Equals("")
+	assert.String(pool.GetDomain(net.ParseIP("10.0.0.1"))).Equals("")
+	assert.String(pool.GetDomain(net.ParseIP("2001:db8::1"))).Equals("")
+}
+
+func TestFakeIPPoolSize(t *testing.T) {
+	assert := assert.On(t)
+
+	pool, err := NewFakeIPPool(&v2dns.FakeIPPool{
+		Cidr: &v2dns.CIDR{Ip: net.ParseIP("fd00::"), Prefix: 8},
+		Size: 2,
+	})
+	assert.Error(err).IsNil()
+
+	assert.String(pool.Get("a.com").String()).Equals("fd00::1")
+	assert.String(pool.Get("b.com").String()).Equals("fd00::2")
+	assert.String(pool.Get("c.com").String()).Equals("fd00::1")
+	assert.String(pool.GetDomain(net.ParseIP("fd00::1"))).Equals("c.com")
+
+	for _, cidr := range []*v2dns.CIDR{nil, {Ip: []byte{198, 18, 0, 0}, Prefix: 31}, {Ip: []byte{198, 18, 0}, Prefix: 16}} {
+		_, err := NewFakeIPPool(&v2dns.FakeIPPool{Cidr: cidr})
+		assert.Error(err).IsNotNil()
+	}
+}
+
+func TestCacheServerFakeIP(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &v2dns.Config{
+		Hosts: map[string]*v2net.IPOrDomain{
+			"v2ray.com": v2net.NewIPOrDomain(v2net.IPAddress([]byte{10, 0, 0, 1})),
+		},
+		FakeIpPool: &v2dns.FakeIPPool{
+			Cidr: &v2dns.CIDR{Ip: []byte{198, 18, 0, 0}, Prefix: 15},
+		},
+	}
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(new(directDispatcher))).IsNil()
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ip, isFake := server.GetFakeIP(context.Background(), "v2ray.com", false)
+	assert.Pointer(ip).IsNil()
+	assert.Bool(isFake).IsFalse()
+	ip, isFake = server.GetFakeIP(context.Background(), "example.com", true)
+	assert.Pointer(ip).IsNil()
+	assert.Bool(isFake).IsTrue()
+	ip, isFake = server.GetFakeIP(context.Background(), "example.com", false)
+	assert.String(ip.String()).Equals("198.18.0.1")
+	assert.Bool(isFake).IsTrue()
+	assert.String(server.GetFakeDomain(ip)).Equals("example.com")
+
+	assert.Error(server.Reload(config)).IsNil()
+	assert.String(server.GetFakeDomain(ip)).Equals("example.com")
+
+	assert.Error(server.Reload(&v2dns.Config{})).IsNil()
+	ip, isFake = server.GetFakeIP(context.Background(), "example.com", false)
+	assert.Pointer(ip).IsNil()
+	assert.Bool(isFake).IsFalse()
+	assert.String(server.GetFakeDomain(ip)).Equals("")
+}
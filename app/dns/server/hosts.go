@@ -0,0 +1,208 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+)
+
+// MaxAliasDepth is the maximum number of aliases followed when resolving a domain in static hosts.
+const MaxAliasDepth = 8
+
+// hostEntry is the IPs of a domain in static hosts, or the domain it is an alias of.
+type hostEntry struct {
+	ips   []net.IP
+	alias string
+}
+
+func (e *hostEntry) add(ips []net.IP, alias string) {
+	e.ips = append(e.ips, ips...)
+	if len(alias) > 0 {
+		e.alias = alias
+	}
+}
+
+type patternHost struct {
+	matcher *router.DomainMatcher
+	entry   *hostEntry
+}
+
+// StaticHosts is the static hosts of DNS. Domains are matched exactly first, then by the most specific parent
+// domain, and then by keyword and regex patterns in order.
+type StaticHosts struct {
+	full      map[string]*hostEntry
+	subdomain map[string]*hostEntry
+	patterns  []*patternHost
+}
+
+// NewStaticHosts returns the static hosts in the given config, including the ones in hosts files.
+func NewStaticHosts(config *dns.Config) (*StaticHosts, error) {
+	hosts := &StaticHosts{
+		full:      make(map[string]*hostEntry),
+		subdomain: make(map[string]*hostEntry),
+	}
+	for domain, ipOrDomain := range config.GetHosts() {
+		address := ipOrDomain.AsAddress()
+		if address.Family().IsDomain() {
+			hosts.addFull(domain, nil, address.Domain())
+		} else {
+			hosts.addFull(domain, []net.IP{address.IP()}, "")
+		}
+	}
+	for _, file := range config.HostsFile {
+		if err := hosts.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	for _, mapping := range config.StaticHosts {
+		if err := hosts.addMapping(mapping); err != nil {
+			return nil, err
+		}
+	}
+	return hosts, nil
+}
+
+func (h *StaticHosts) addFull(domain string, ips []net.IP, alias string) {
+	addHost(h.full, domain, ips, alias)
+}
+
+func addHost(entries map[string]*hostEntry, domain string, ips []net.IP, alias string) {
+	domain = strings.ToLower(domain)
+	entry, found := entries[domain]
+	if !found {
+		entry = new(hostEntry)
+		entries[domain] = entry
+	}
+	entry.add(ips, strings.ToLower(alias))
+}
+
+func (h *StaticHosts) addMapping(mapping *dns.HostMapping) error {
+	if len(mapping.Ip) == 0 && len(mapping.ProxiedDomain) == 0 {
+		return errors.New("DNS: Neither IP nor domain is set for static host: ", mapping.Domain)
+	}
+	ips := make([]net.IP, 0, len(mapping.Ip))
+	for _, ip := range mapping.Ip {
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return errors.New("DNS: Invalid IP for static host ", mapping.Domain, ": ", ip)
+		}
+		ips = append(ips, net.IP(ip))
+	}
+
+	switch mapping.Type {
+	case dns.Domain_Full:
+		h.addFull(mapping.Domain, ips, mapping.ProxiedDomain)
+	case dns.Domain_Subdomain:
+		addHost(h.subdomain, mapping.Domain, ips, mapping.ProxiedDomain)
+	default:
+		matcher, err := router.NewDomainMatcher([]*router.Domain{{
+			Type:  router.Domain_Type(mapping.Type),
+			Value: mapping.Domain,
+		}})
+		if err != nil {
+			return errors.Base(err).Message("DNS: Invalid static host: ", mapping.Domain)
+		}
+		entry := new(hostEntry)
+		entry.add(ips, strings.ToLower(mapping.ProxiedDomain))
+		h.patterns = append(h.patterns, &patternHost{
+			matcher: matcher,
+			entry:   entry,
+		})
+	}
+	return nil
+}
+
+// loadFile adds the hosts in the given file in the format of /etc/hosts.
+func (h *StaticHosts) loadFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return errors.Base(err).Message("DNS: Failed to open hosts file: ", file)
+	}
+	defer f.Close()
+	if err := h.parseHosts(f); err != nil {
+		return errors.Base(err).Message("DNS: Failed to read hosts file: ", file)
+	}
+	return nil
+}
+
+// parseHosts adds the hosts in the given reader, with lines like "127.0.0.1 localhost # comment". Lines with
+// invalid IPs are skipped.
+func (h *StaticHosts) parseHosts(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			log.Info("DNS: Ignoring invalid IP in hosts file: ", fields[0])
+			continue
+		}
+		if ipv4 := ip.To4(); ipv4 != nil {
+			ip = ipv4
+		}
+		for _, domain := range fields[1:] {
+			h.addFull(domain, []net.IP{ip}, "")
+		}
+	}
+	return scanner.Err()
+}
+
+// lookup returns the entry of the given domain, or nil if the domain is not in static hosts.
+func (h *StaticHosts) lookup(domain string) *hostEntry {
+	if entry, found := h.full[domain]; found {
+		return entry
+	}
+	if len(h.subdomain) > 0 {
+		for parent := domain; len(parent) > 0; {
+			if entry, found := h.subdomain[parent]; found {
+				return entry
+			}
+			idx := strings.IndexByte(parent, '.')
+			if idx < 0 {
+				break
+			}
+			parent = parent[idx+1:]
+		}
+	}
+	for _, pattern := range h.patterns {
+		if pattern.matcher.MatchDomain(domain) {
+			return pattern.entry
+		}
+	}
+	return nil
+}
+
+// Contains returns true if the given domain is in static hosts.
+func (h *StaticHosts) Contains(domain string) bool {
+	return h.lookup(strings.ToLower(domain)) != nil
+}
+
+// Resolve follows aliases of the given domain in static hosts. It returns the IPs in static hosts if there are,
+// or the domain to query name servers for otherwise. If aliases are nested too deep, both are empty.
+func (h *StaticHosts) Resolve(domain string) ([]net.IP, string) {
+	domain = strings.ToLower(domain)
+	for depth := 0; depth <= MaxAliasDepth; depth++ {
+		entry := h.lookup(domain)
+		if entry == nil {
+			return nil, domain
+		}
+		if len(entry.ips) > 0 || len(entry.alias) == 0 {
+			return entry.ips, ""
+		}
+		domain = entry.alias
+	}
+	log.Warning("DNS: Too many aliases for domain ", domain, " in static hosts.")
+	return nil, ""
+}
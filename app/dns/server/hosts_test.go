@@ -0,0 +1,127 @@
+package server_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miekg/dns"
+	v2dns "v2ray.com/core/app/dns"
+	. "v2ray.com/core/app/dns/server"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestStaticHosts(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-dns-hosts")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+	hostsFile := filepath.Join(dir, "hosts")
+	assert.Error(ioutil.WriteFile(hostsFile, []byte(`# comment
+127.0.0.1	localhost loopback
+::1	localhost # IPv6
+invalid	broken.example.com
+0.0.0.0 ads.example.com
+`), 0644)).IsNil()
+
+	hosts, err := NewStaticHosts(&v2dns.Config{
+		Hosts: map[string]*v2net.IPOrDomain{
+			"v2ray.com":       v2net.NewIPOrDomain(v2net.ParseAddress("10.0.0.1")),
+			"www.v2ray.com":   v2net.NewIPOrDomain(v2net.ParseAddress("V2Ray.com")),
+			"cdn.example.com": v2net.NewIPOrDomain(v2net.ParseAddress("origin.example.net")),
+		},
+		HostsFile: []string{hostsFile},
+		StaticHosts: []*v2dns.HostMapping{
+			{Type: v2dns.Domain_Subdomain, Domain: "corp.example.com", Ip: [][]byte{{10, 0, 1, 1}, {10, 0, 1, 2}}},
+			{Type: v2dns.Domain_Subdomain, Domain: "db.corp.example.com", Ip: [][]byte{{10, 0, 2, 1}}},
+			{Type: v2dns.Domain_Regex, Domain: `^ads?\d*\.`, Ip: [][]byte{{0, 0, 0, 0}}},
+			{Type: v2dns.Domain_Plain, Domain: "loop", ProxiedDomain: "loop.example.com"},
+		},
+	})
+	assert.Error(err).IsNil()
+
+	for domain, expected := range map[string][]string{
+		"v2ray.com":              {"10.0.0.1"},
+		"WWW.v2ray.com":          {"10.0.0.1"},
+		"localhost":              {"127.0.0.1", "::1"},
+		"loopback":               {"127.0.0.1"},
+		"ads.example.com":        {"0.0.0.0"},
+		"ad1.v2ray.com":          {"0.0.0.0"},
+		"corp.example.com":       {"10.0.1.1", "10.0.1.2"},
+		"mail.corp.example.com":  {"10.0.1.1", "10.0.1.2"},
+		"a.db.corp.example.com":  {"10.0.2.1"},
+		"broken.example.com":     nil,
+		"origin.corp.example.co": nil,
+	} {
+		ips, _ := hosts.Resolve(domain)
+		if len(ips) != len(expected) {
+			t.Error("unexpected IPs for ", domain, ": ", ips)
+			continue
+		}
+		for idx, ip := range ips {
+			assert.String(ip.String()).Equals(expected[idx])
+		}
+	}
+
+	ips, domain := hosts.Resolve("cdn.example.com")
+	assert.Int(len(ips)).Equals(0)
+	assert.String(domain).Equals("origin.example.net")
+
+	ips, domain = hosts.Resolve("v2fly.org")
+	assert.Int(len(ips)).Equals(0)
+	assert.String(domain).Equals("v2fly.org")
+
+	ips, domain = hosts.Resolve("loop.example.com")
+	assert.Int(len(ips)).Equals(0)
+	assert.String(domain).Equals("")
+
+	assert.Bool(hosts.Contains("cdn.example.com")).IsTrue()
+	assert.Bool(hosts.Contains("v2fly.org")).IsFalse()
+
+	_, err = NewStaticHosts(&v2dns.Config{
+		StaticHosts: []*v2dns.HostMapping{{Type: v2dns.Domain_Full, Domain: "v2ray.com"}},
+	})
+	assert.Error(err).IsNotNil()
+
+	_, err = NewStaticHosts(&v2dns.Config{
+		HostsFile: []string{filepath.Join(dir, "missing")},
+	})
+	assert.Error(err).IsNotNil()
+}
+
+func TestCacheServerHostAlias(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+	server := newCacheServer(t, dnsServer, nil)
+	assert.Error(server.Reload(&v2dns.Config{
+		NameServers: []*v2net.Endpoint{
+			{
+				Network: v2net.Network_TCP,
+				Address: v2net.NewIPOrDomain(dnsServer.Destination().Address),
+				Port:    uint32(dnsServer.Destination().Port),
+			},
+		},
+		StaticHosts: []*v2dns.HostMapping{
+			{Type: v2dns.Domain_Subdomain, Domain: "cdn.example.com", ProxiedDomain: "origin.example.net"},
+			{Type: v2dns.Domain_Full, Domain: "dual.example.com", Ip: [][]byte{{10, 0, 0, 2}, v2net.ParseAddress("2001:db8::2").IP()}},
+		},
+	})).IsNil()
+
+	ips := server.Get("img.cdn.example.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("10.0.0.1")
+	assert.Int(len(server.GetCached("origin.example.net.", dns.TypeA))).Equals(1)
+
+	ips = server.GetWithStrategy("dual.example.com", v2dns.Config_USE_IP6)
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("2001:db8::2")
+	ips = server.Get("dual.example.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("10.0.0.2")
+	assert.Int(len(server.GetWithStrategy("dual.example.com", v2dns.Config_PREFER_IP4))).Equals(2)
+}
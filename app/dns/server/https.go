@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/miekg/dns"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	v2net "v2ray.com/core/common/net"
+)
+
+const dnsMessageType = "application/dns-message"
+
+// HTTPSNameServer is a NameServer that sends queries with HTTP POST requests as in RFC 8484. Connections are kept
+// alive and reused among queries.
+type HTTPSNameServer struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSNameServer(address v2net.Destination, path string, dispatcher dispatcher.Interface, tlsConfig *tls.Config) *HTTPSNameServer {
+	if len(tlsConfig.ServerName) == 0 {
+		tlsConfig.ServerName = serverName(address.Address)
+	}
+	host := strings.TrimSuffix(net.JoinHostPort(tlsConfig.ServerName, address.Port.String()), ":443")
+	return &HTTPSNameServer{
+		url: "https://" + host + path,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialTLS: func(network, addr string) (net.Conn, error) {
+					log.Info("DNS: Opening connection to ", address)
+					conn := tls.Client(dialThroughDispatcher(dispatcher, address), tlsConfig)
+					if err := conn.Handshake(); err != nil {
+						conn.Close()
+						return nil, err
+					}
+					return conn, nil
+				},
+				MaxIdleConnsPerHost: 4,
+				IdleConnTimeout:     CleanupInterval,
+			},
+			Timeout: QueryTimeout,
+		},
+	}
+}
+
+// serverName returns the name of the given address for TLS verification.
+func serverName(address v2net.Address) string {
+	if address.Family().IsDomain() {
+		return address.Domain()
+	}
+	return address.IP().String()
+}
+
+func (v *HTTPSNameServer) String() string {
+	return v.url
+}
+
+func (v *HTTPSNameServer) QueryA(domain string) <-chan *ARecord {
+	return v.query(domain, dns.TypeA)
+}
+
+func (v *HTTPSNameServer) QueryAAAA(domain string) <-chan *ARecord {
+	return v.query(domain, dns.TypeAAAA)
+}
+
+func (v *HTTPSNameServer) query(domain string, qtype uint16) <-chan *ARecord {
+	response := make(chan *ARecord, 1)
+
+	go func() {
+		defer close(response)
+
+		// RFC 8484 4.1: ID should be 0 for better caching.
+		query := buildQuery(domain, 0, qtype)
+		defer query.Release()
+		msg, err := v.post(query.Bytes())
+		if err != nil {
+			log.Warning("DNS: Failed to query ", domain, " on ", v.url, ": ", err)
+			return
+		}
+		response <- parseResponse(msg)
+	}()
+
+	return response
+}
+
+func (v *HTTPSNameServer) post(query []byte) (*dns.Msg, error) {
+	request, err := http.NewRequest("POST", v.url, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", dnsMessageType)
+	request.Header.Set("Accept", dnsMessageType)
+
+	resp, err := v.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, errors.New("DNS: Unexpected status: ", resp.Status)
+	}
+	payload, err := ioutil.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+	msg := new(dns.Msg)
+	if err := msg.Unpack(payload); err != nil {
+		return nil, errors.Base(err).Message("DNS: Failed to parse DNS response.")
+	}
+	return msg, nil
+}
@@ -19,6 +19,8 @@ const (
 	DefaultTTL       = uint32(3600)
 	CleanupInterval  = time.Second * 120
 	CleanupThreshold = 512
+	// NegativeTTL is the TTL of nonexistent domains and empty answers without SOA records.
+	NegativeTTL = uint32(60)
 )
 
 var (
@@ -28,36 +30,33 @@ var (
 type ARecord struct {
 	IPs    []net.IP
 	Expire time.Time
+	// NotExist is true if the name server answers that the domain does not exist (NXDOMAIN).
+	NotExist bool
 }
 
 type NameServer interface {
 	QueryA(domain string) <-chan *ARecord
+	QueryAAAA(domain string) <-chan *ARecord
+	// String returns the name server for logs, such as "udp:8.8.8.8:53".
+	String() string
 }
 
 type PendingRequest struct {
 	expire   time.Time
 	response chan<- *ARecord
+	// conn is the connection the request is sent on, for name servers over connections.
+	conn net.Conn
 }
 
-type UDPNameServer struct {
+// pendingRequests keeps track of the queries waiting for responses, by their IDs.
+type pendingRequests struct {
 	sync.Mutex
-	address     v2net.Destination
 	requests    map[uint16]*PendingRequest
-	udpServer   *udp.Dispatcher
 	nextCleanup time.Time
 }
 
-func NewUDPNameServer(address v2net.Destination, dispatcher dispatcher.Interface) *UDPNameServer {
-	s := &UDPNameServer{
-		address:   address,
-		requests:  make(map[uint16]*PendingRequest),
-		udpServer: udp.NewDispatcher(dispatcher),
-	}
-	return s
-}
-
 // Private: Visible for testing.
-func (v *UDPNameServer) Cleanup() {
+func (v *pendingRequests) Cleanup() {
 	expiredRequests := make([]uint16, 0, 16)
 	now := time.Now()
 	v.Lock()
@@ -75,9 +74,12 @@ func (v *UDPNameServer) Cleanup() {
 }
 
 // Private: Visible for testing.
-func (v *UDPNameServer) AssignUnusedID(response chan<- *ARecord) uint16 {
+func (v *pendingRequests) AssignUnusedID(response chan<- *ARecord) uint16 {
 	var id uint16
 	v.Lock()
+	if v.requests == nil {
+		v.requests = make(map[uint16]*PendingRequest)
+	}
 	if len(v.requests) > CleanupThreshold && v.nextCleanup.Before(time.Now()) {
 		v.nextCleanup = time.Now().Add(CleanupInterval)
 		go v.Cleanup()
@@ -99,30 +101,82 @@ func (v *UDPNameServer) AssignUnusedID(response chan<- *ARecord) uint16 {
 	return id
 }
 
-// Private: Visible for testing.
-func (v *UDPNameServer) HandleResponse(payload *buf.Buffer) {
-	msg := new(dns.Msg)
-	err := msg.Unpack(payload.Bytes())
-	if err != nil {
-		log.Warning("DNS: Failed to parse DNS response: ", err)
-		return
+func (v *pendingRequests) isPending(id uint16) bool {
+	v.Lock()
+	_, found := v.requests[id]
+	v.Unlock()
+	return found
+}
+
+// cancel closes the response of the pending request with the given id, if any.
+func (v *pendingRequests) cancel(id uint16) {
+	v.Lock()
+	request, found := v.requests[id]
+	delete(v.requests, id)
+	v.Unlock()
+	if found {
+		close(request.response)
 	}
-	record := &ARecord{
-		IPs: make([]net.IP, 0, 16),
+}
+
+// sentOn records that the pending request with the given id is sent on the given connection.
+func (v *pendingRequests) sentOn(id uint16, conn net.Conn) {
+	v.Lock()
+	if request, found := v.requests[id]; found {
+		request.conn = conn
 	}
-	id := msg.Id
-	ttl := DefaultTTL
-	log.Debug("DNS: Handling response for id ", id, " content: ", msg.String())
+	v.Unlock()
+}
 
+// cancelConn closes the responses of the pending requests sent on the given connection, and returns the number of
+// them.
+func (v *pendingRequests) cancelConn(conn net.Conn) int {
 	v.Lock()
-	request, found := v.requests[id]
+	var cancelled []*PendingRequest
+	for id, request := range v.requests {
+		if request.conn == conn {
+			cancelled = append(cancelled, request)
+			delete(v.requests, id)
+		}
+	}
+	v.Unlock()
+	for _, request := range cancelled {
+		close(request.response)
+	}
+	return len(cancelled)
+}
+
+func (v *pendingRequests) handleMsg(msg *dns.Msg) {
+	log.Debug("DNS: Handling response for id ", msg.Id, " content: ", msg.String())
+
+	v.Lock()
+	request, found := v.requests[msg.Id]
 	if !found {
 		v.Unlock()
 		return
 	}
-	delete(v.requests, id)
+	delete(v.requests, msg.Id)
 	v.Unlock()
 
+	request.response <- parseResponse(msg)
+	close(request.response)
+}
+
+// parseResponse returns the IPs in the answer section of the given response, expiring with the smallest TTL. Negative
+// answers, NXDOMAIN or no IP, expire with the minimum TTL of the SOA record in the authority section (RFC 2308). It
+// returns nil if the name server fails to answer, such as SERVFAIL or REFUSED, so that the next one is tried.
+func parseResponse(msg *dns.Msg) *ARecord {
+	switch msg.Rcode {
+	case dns.RcodeSuccess, dns.RcodeNameError:
+	default:
+		log.Info("DNS: Name server answers ", dns.RcodeToString[msg.Rcode], " for id ", msg.Id)
+		return nil
+	}
+	record := &ARecord{
+		IPs:      make([]net.IP, 0, 16),
+		NotExist: msg.Rcode == dns.RcodeNameError,
+	}
+	ttl := DefaultTTL
 	for _, rr := range msg.Answer {
 		switch rr := rr.(type) {
 		case *dns.A:
@@ -137,21 +191,72 @@ func (v *UDPNameServer) HandleResponse(payload *buf.Buffer) {
 			}
 		}
 	}
+	if record.NotExist || len(record.IPs) == 0 {
+		ttl = negativeTTL(msg)
+	}
 	record.Expire = time.Now().Add(time.Second * time.Duration(ttl))
+	return record
+}
 
-	request.response <- record
-	close(request.response)
+// negativeTTL returns how long a negative answer in the given response may be cached, which is the smaller one of the
+// TTL and the minimum TTL of the SOA record, or NegativeTTL if there is no SOA record.
+func negativeTTL(msg *dns.Msg) uint32 {
+	for _, rr := range msg.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			if soa.Minttl < soa.Hdr.Ttl {
+				return soa.Minttl
+			}
+			return soa.Hdr.Ttl
+		}
+	}
+	return NegativeTTL
+}
+
+type UDPNameServer struct {
+	pendingRequests
+	address   v2net.Destination
+	udpServer *udp.Dispatcher
+}
+
+func NewUDPNameServer(address v2net.Destination, dispatcher dispatcher.Interface) *UDPNameServer {
+	s := &UDPNameServer{
+		address:   address,
+		udpServer: udp.NewDispatcher(dispatcher),
+	}
+	return s
+}
+
+func (v *UDPNameServer) String() string {
+	return v.address.String()
+}
+
+// Private: Visible for testing.
+func (v *UDPNameServer) HandleResponse(payload *buf.Buffer) {
+	msg := new(dns.Msg)
+	err := msg.Unpack(payload.Bytes())
+	if err != nil {
+		log.Warning("DNS: Failed to parse DNS response: ", err)
+		return
+	}
+	v.handleMsg(msg)
 }
 
 func (v *UDPNameServer) BuildQueryA(domain string, id uint16) *buf.Buffer {
+	return buildQuery(domain, id, dns.TypeA)
+}
+
+func (v *UDPNameServer) BuildQueryAAAA(domain string, id uint16) *buf.Buffer {
+	return buildQuery(domain, id, dns.TypeAAAA)
+}
 
+func buildQuery(domain string, id uint16, qtype uint16) *buf.Buffer {
 	msg := new(dns.Msg)
 	msg.Id = id
 	msg.RecursionDesired = true
 	msg.Question = []dns.Question{
 		{
 			Name:   dns.Fqdn(domain),
-			Qtype:  dns.TypeA,
+			Qtype:  qtype,
 			Qclass: dns.ClassINET,
 		}}
 
@@ -165,20 +270,25 @@ func (v *UDPNameServer) BuildQueryA(domain string, id uint16) *buf.Buffer {
 }
 
 func (v *UDPNameServer) QueryA(domain string) <-chan *ARecord {
+	return v.query(domain, v.BuildQueryA)
+}
+
+func (v *UDPNameServer) QueryAAAA(domain string) <-chan *ARecord {
+	return v.query(domain, v.BuildQueryAAAA)
+}
+
+func (v *UDPNameServer) query(domain string, build func(string, uint16) *buf.Buffer) <-chan *ARecord {
 	response := make(chan *ARecord, 1)
 	id := v.AssignUnusedID(response)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*8)
-	v.udpServer.Dispatch(ctx, v.address, v.BuildQueryA(domain, id), v.HandleResponse)
+	v.udpServer.Dispatch(ctx, v.address, build(domain, id), v.HandleResponse)
 
 	go func() {
 		for i := 0; i < 2; i++ {
 			time.Sleep(time.Second)
-			v.Lock()
-			_, found := v.requests[id]
-			v.Unlock()
-			if found {
-				v.udpServer.Dispatch(ctx, v.address, v.BuildQueryA(domain, id), v.HandleResponse)
+			if v.isPending(id) {
+				v.udpServer.Dispatch(ctx, v.address, build(domain, id), v.HandleResponse)
 			} else {
 				break
 			}
@@ -192,7 +302,19 @@ func (v *UDPNameServer) QueryA(domain string) <-chan *ARecord {
 type LocalNameServer struct {
 }
 
+func (*LocalNameServer) String() string {
+	return "localhost"
+}
+
 func (v *LocalNameServer) QueryA(domain string) <-chan *ARecord {
+	return v.query(domain, true)
+}
+
+func (v *LocalNameServer) QueryAAAA(domain string) <-chan *ARecord {
+	return v.query(domain, false)
+}
+
+func (v *LocalNameServer) query(domain string, ipv4 bool) <-chan *ARecord {
 	response := make(chan *ARecord, 1)
 
 	go func() {
@@ -205,10 +327,21 @@ func (v *LocalNameServer) QueryA(domain string) <-chan *ARecord {
 		}
 
 		response <- &ARecord{
-			IPs:    ips,
+			IPs:    filterIPs(ips, ipv4),
 			Expire: time.Now().Add(time.Second * time.Duration(DefaultTTL)),
 		}
 	}()
 
 	return response
 }
+
+// filterIPs returns the IPv4 addresses in the given list if ipv4 is true, or the IPv6 addresses otherwise.
+func filterIPs(ips []net.IP, ipv4 bool) []net.IP {
+	filtered := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		if (ip.To4() != nil) == ipv4 {
+			filtered = append(filtered, ip)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,92 @@
+package server_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	. "v2ray.com/core/app/dns/server"
+	"v2ray.com/core/common/buf"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestUDPNameServerAAAA(t *testing.T) {
+	assert := assert.On(t)
+
+	server := NewUDPNameServer(v2net.UDPDestination(v2net.LocalHostIP, 53), nil)
+	response := make(chan *ARecord, 1)
+	id := server.AssignUnusedID(response)
+
+	query := new(dns.Msg)
+	assert.Error(query.Unpack(server.BuildQueryAAAA("v2ray.com", id).Bytes())).IsNil()
+	assert.Int(len(query.Question)).Equals(1)
+	assert.String(query.Question[0].Name).Equals("v2ray.com.")
+	assert.Bool(query.Question[0].Qtype == dns.TypeAAAA).IsTrue()
+
+	reply := new(dns.Msg)
+	reply.SetReply(query)
+	reply.Answer = []dns.RR{
+		&dns.AAAA{
+			Hdr:  dns.RR_Header{Name: "v2ray.com.", Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 300},
+			AAAA: net.ParseIP("2001:db8::1"),
+		},
+	}
+	payload := buf.New()
+	assert.Error(payload.AppendSupplier(func(b []byte) (int, error) {
+		packed, err := reply.PackBuffer(b)
+		return len(packed), err
+	})).IsNil()
+	server.HandleResponse(payload)
+
+	record := <-response
+	assert.Int(len(record.IPs)).Equals(1)
+	assert.String(record.IPs[0].String()).Equals("2001:db8::1")
+}
+
+func TestUDPNameServerNegativeAnswers(t *testing.T) {
+	assert := assert.On(t)
+
+	server := NewUDPNameServer(v2net.UDPDestination(v2net.LocalHostIP, 53), nil)
+	handle := func(rcode int, ns []dns.RR) *ARecord {
+		response := make(chan *ARecord, 1)
+		id := server.AssignUnusedID(response)
+		query := new(dns.Msg)
+		assert.Error(query.Unpack(server.BuildQueryA("v2ray.com", id).Bytes())).IsNil()
+
+		reply := new(dns.Msg)
+		reply.SetRcode(query, rcode)
+		reply.Ns = ns
+		payload := buf.New()
+		assert.Error(payload.AppendSupplier(func(b []byte) (int, error) {
+			packed, err := reply.PackBuffer(b)
+			return len(packed), err
+		})).IsNil()
+		server.HandleResponse(payload)
+		return <-response
+	}
+	soa := &dns.SOA{
+		Hdr:    dns.RR_Header{Name: "v2ray.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 600},
+		Ns:     "ns.v2ray.com.",
+		Mbox:   "admin.v2ray.com.",
+		Minttl: 30,
+	}
+
+	record := handle(dns.RcodeNameError, []dns.RR{soa})
+	assert.Bool(record.NotExist).IsTrue()
+	assert.Int(len(record.IPs)).Equals(0)
+	assert.Bool(record.Expire.Before(time.Now().Add(time.Second * 31))).IsTrue()
+
+	record = handle(dns.RcodeSuccess, []dns.RR{soa})
+	assert.Bool(record.NotExist).IsFalse()
+	assert.Int(len(record.IPs)).Equals(0)
+	assert.Bool(record.Expire.Before(time.Now().Add(time.Second * 31))).IsTrue()
+
+	record = handle(dns.RcodeNameError, nil)
+	assert.Bool(record.NotExist).IsTrue()
+	assert.Bool(record.Expire.Before(time.Now().Add(time.Second * time.Duration(NegativeTTL+1)))).IsTrue()
+
+	assert.Pointer(handle(dns.RcodeServerFailure, nil)).IsNil()
+	assert.Pointer(handle(dns.RcodeRefused, nil)).IsNil()
+}
@@ -3,33 +3,53 @@ package server
 import (
 	"context"
 	"net"
+	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	dnsmsg "github.com/miekg/dns"
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/proxy"
 )
 
 const (
 	QueryTimeout = time.Second * 8
+	// StaticTTL is the time to live of IPs in static hosts and sinkholes.
+	StaticTTL = time.Minute
+	// StaleTTL is the time to live of stale records, as recommended in RFC 8767.
+	StaleTTL = time.Second * 30
 )
 
 type DomainRecord struct {
-	A *ARecord
+	A    *CachedRecord
+	AAAA *CachedRecord
 }
 
 type CacheServer struct {
 	sync.RWMutex
-	space   app.Space
-	hosts   map[string]net.IP
-	records map[string]*DomainRecord
-	servers []NameServer
+	dispatcher dispatcher.Interface
+	hosts      *StaticHosts
+	records    map[string]*DomainRecord
+	servers    []*nameServerEntry
+	strategy   dns.Config_QueryStrategy
+	fakeIPs    *FakeIPPool
+	fakeConfig *dns.FakeIPPool
+	cache      *dns.CacheConfig
+	blockRules []*blockRule
+	started    bool
+	done       chan struct{}
+	clock      func() time.Time
 }
 
 func NewCacheServer(ctx context.Context, config *dns.Config) (*CacheServer, error) {
@@ -37,84 +57,567 @@ func NewCacheServer(ctx context.Context, config *dns.Config) (*CacheServer, erro
 	if space == nil {
 		return nil, errors.New("DNSCacheServer: No space in context.")
 	}
+	hosts, err := NewStaticHosts(config)
+	if err != nil {
+		return nil, err
+	}
+	blockRules, err := buildBlockRules(config)
+	if err != nil {
+		return nil, err
+	}
 	server := &CacheServer{
-		records: make(map[string]*DomainRecord),
-		servers: make([]NameServer, len(config.NameServers)),
-		hosts:   config.GetInternalHosts(),
+		records:    make(map[string]*DomainRecord),
+		hosts:      hosts,
+		strategy:   config.QueryStrategy,
+		cache:      config.Cache,
+		blockRules: blockRules,
+		clock:      time.Now,
+	}
+	if file := config.Cache.GetFile(); len(file) > 0 {
+		records, err := loadCache(file, config.Cache, server.now())
+		if err == nil {
+			server.records = records
+			log.Info("DNS: Loaded ", len(records), " domains from cache file ", file)
+		} else if !os.IsNotExist(err) {
+			log.Warning("DNS: Failed to load cache: ", err)
+		}
+	}
+	if config.FakeIpPool != nil {
+		pool, err := NewFakeIPPool(config.FakeIpPool)
+		if err != nil {
+			return nil, err
+		}
+		server.fakeIPs = pool
+		server.fakeConfig = config.FakeIpPool
 	}
 	space.OnInitialize(func() error {
 		disp := dispatcher.FromSpace(space)
 		if disp == nil {
 			return errors.New("DNS: Dispatcher is not found in the space.")
 		}
-		for idx, destPB := range config.NameServers {
-			address := destPB.Address.AsAddress()
-			if address.Family().IsDomain() && address.Domain() == "localhost" {
-				server.servers[idx] = &LocalNameServer{}
-			} else {
-				dest := destPB.AsDestination()
-				if dest.Network == v2net.Network_Unknown {
-					dest.Network = v2net.Network_UDP
-				}
-				if dest.Network == v2net.Network_UDP {
-					server.servers[idx] = NewUDPNameServer(dest, disp)
-				}
-			}
-		}
-		if len(config.NameServers) == 0 {
-			server.servers = append(server.servers, &LocalNameServer{})
+		servers, err := buildNameServers(config, disp)
+		if err != nil {
+			return err
 		}
+		server.dispatcher = disp
+		server.servers = servers
 		return nil
 	})
 	return server, nil
 }
 
-func (CacheServer) Interface() interface{} {
+// nameServerEntry is a NameServer with the domains it serves and the IPs it is expected to answer.
+type nameServerEntry struct {
+	server      NameServer
+	domains     *router.DomainMatcher
+	expectedIPs *router.CIDRListMatcher
+}
+
+// buildNameServers returns the name servers in the given config. If there is no name server for all domains,
+// the DNS of local system is used for domains not served by others, with a warning if there are name servers for
+// some domains.
+func buildNameServers(config *dns.Config, disp dispatcher.Interface) ([]*nameServerEntry, error) {
+	servers := make([]*nameServerEntry, 0, len(config.NameServers)+len(config.NameServer)+1)
+	hasDefault := false
+	for _, destPB := range config.NameServers {
+		address := destPB.Address.AsAddress()
+		if address.Family().IsDomain() && address.Domain() == "localhost" {
+			servers = append(servers, &nameServerEntry{server: &LocalNameServer{}})
+		} else {
+			dest := destPB.AsDestination()
+			switch dest.Network {
+			case v2net.Network_TCP:
+				servers = append(servers, &nameServerEntry{server: NewTCPNameServer(dest, disp, nil)})
+			case v2net.Network_Unknown, v2net.Network_UDP:
+				dest.Network = v2net.Network_UDP
+				servers = append(servers, &nameServerEntry{server: NewUDPNameServer(dest, disp)})
+			default:
+				continue
+			}
+		}
+		hasDefault = true
+	}
+	for _, ns := range config.NameServer {
+		entry, err := buildNameServerEntry(ns, disp)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, entry)
+		if entry.domains == nil {
+			hasDefault = true
+		}
+	}
+	if !hasDefault {
+		if len(servers) > 0 {
+			log.Warning("DNS: All name servers are for specific domains. Other domains are resolved by the DNS of local system.")
+		}
+		servers = append(servers, &nameServerEntry{server: &LocalNameServer{}})
+	}
+	return servers, nil
+}
+
+func buildNameServerEntry(ns *dns.NameServer, disp dispatcher.Interface) (*nameServerEntry, error) {
+	entry := &nameServerEntry{
+		server: buildNameServer(ns, disp),
+	}
+	if len(ns.Domain) > 0 {
+		domains := make([]*router.Domain, len(ns.Domain))
+		for idx, domain := range ns.Domain {
+			domains[idx] = &router.Domain{
+				Type:  router.Domain_Type(domain.Type),
+				Value: domain.Value,
+			}
+		}
+		matcher, err := router.NewDomainMatcher(domains)
+		if err != nil {
+			return nil, errors.Base(err).Message("DNS: Invalid domains of name server ", ns.GetAddress().AsDestination())
+		}
+		entry.domains = matcher
+	}
+	if len(ns.ExpectedIp) > 0 {
+		cidrs := make([]*router.CIDR, len(ns.ExpectedIp))
+		for idx, cidr := range ns.ExpectedIp {
+			cidrs[idx] = &router.CIDR{
+				Ip:     cidr.Ip,
+				Prefix: cidr.Prefix,
+			}
+		}
+		matcher, err := router.NewCIDRListMatcher(cidrs, false)
+		if err != nil {
+			return nil, errors.Base(err).Message("DNS: Invalid expected IPs of name server ", ns.GetAddress().AsDestination())
+		}
+		entry.expectedIPs = matcher
+	}
+	return entry, nil
+}
+
+// filter returns a record with the IPs in the given record that are expected from this name server.
+func (e *nameServerEntry) filter(record *ARecord) *ARecord {
+	if e.expectedIPs == nil {
+		return record
+	}
+	ips := make([]net.IP, 0, len(record.IPs))
+	for _, ip := range record.IPs {
+		if e.expectedIPs.Contains(ip) {
+			ips = append(ips, ip)
+		}
+	}
+	return &ARecord{
+		IPs:    ips,
+		Expire: record.Expire,
+	}
+}
+
+// selectServers returns the name servers whose domains match the given domain, or the ones for all domains if
+// there is none.
+func selectServers(domain string, servers []*nameServerEntry) []*nameServerEntry {
+	selected := make([]*nameServerEntry, 0, len(servers))
+	for _, server := range servers {
+		if server.domains != nil && server.domains.MatchDomain(domain) {
+			selected = append(selected, server)
+		}
+	}
+	if len(selected) > 0 {
+		return selected
+	}
+	for _, server := range servers {
+		if server.domains == nil {
+			selected = append(selected, server)
+		}
+	}
+	return selected
+}
+
+func buildNameServer(ns *dns.NameServer, disp dispatcher.Interface) NameServer {
+	dest := ns.GetAddress().AsDestination()
+	if dest.Address.Family().IsDomain() && dest.Address.Domain() == "localhost" {
+		return &LocalNameServer{}
+	}
+	if dest.Port == 0 {
+		switch ns.Type {
+		case dns.NameServer_TLS:
+			dest.Port = 853
+		case dns.NameServer_HTTPS:
+			dest.Port = 443
+		default:
+			dest.Port = 53
+		}
+	}
+	if ns.Type == dns.NameServer_UDP {
+		dest.Network = v2net.Network_UDP
+		return NewUDPNameServer(dest, disp)
+	}
+
+	dest.Network = v2net.Network_TCP
+	switch ns.Type {
+	case dns.NameServer_TLS:
+		tlsConfig := ns.TlsSettings.GetTLSConfig()
+		tlsConfig.NextProtos = nil
+		if len(tlsConfig.ServerName) == 0 {
+			tlsConfig.ServerName = serverName(dest.Address)
+		}
+		return NewTCPNameServer(dest, disp, tlsConfig)
+	case dns.NameServer_HTTPS:
+		path := ns.Path
+		if len(path) == 0 {
+			path = "/dns-query"
+		}
+		return NewHTTPSNameServer(dest, path, disp, ns.TlsSettings.GetTLSConfig())
+	default:
+		return NewTCPNameServer(dest, disp, nil)
+	}
+}
+
+func (*CacheServer) Interface() interface{} {
 	return (*dns.Server)(nil)
 }
 
 // Private: Visible for testing.
-func (v *CacheServer) GetCached(domain string) []net.IP {
+func (v *CacheServer) SetClock(clock func() time.Time) {
+	v.Lock()
+	defer v.Unlock()
+
+	v.clock = clock
+}
+
+// now returns the current time of the cache.
+func (v *CacheServer) now() time.Time {
 	v.RLock()
 	defer v.RUnlock()
 
-	if record, found := v.records[domain]; found && record.A.Expire.After(time.Now()) {
-		return record.A.IPs
+	return v.clock()
+}
+
+// Reload replaces the name servers and static hosts of this server with the ones in the given config. Hosts files
+// are loaded again. Cached records are kept until they expire, including the serve-stale window in the new config,
+// and the new cache settings apply to records fetched later. The cache is saved periodically if the new config has a
+// cache file. Fake IPs are kept if the fake IP pool is not changed.
+func (v *CacheServer) Reload(config *dns.Config) error {
+	if v.dispatcher == nil {
+		return errors.New("DNSCacheServer: Server is not initialized.")
+	}
+	servers, err := buildNameServers(config, v.dispatcher)
+	if err != nil {
+		return err
+	}
+	hosts, err := NewStaticHosts(config)
+	if err != nil {
+		return err
+	}
+	blockRules, err := buildBlockRules(config)
+	if err != nil {
+		return err
+	}
+
+	v.RLock()
+	fakeIPs := v.fakeIPs
+	fakeChanged := !proto.Equal(v.fakeConfig, config.FakeIpPool)
+	v.RUnlock()
+	if fakeChanged {
+		fakeIPs = nil
+		if config.FakeIpPool != nil {
+			fakeIPs, err = NewFakeIPPool(config.FakeIpPool)
+			if err != nil {
+				return err
+			}
+		}
 	}
+
+	v.Lock()
+	v.fakeIPs = fakeIPs
+	v.fakeConfig = config.FakeIpPool
+	v.servers = servers
+	v.hosts = hosts
+	v.blockRules = blockRules
+	v.strategy = config.QueryStrategy
+	v.cache = config.Cache
+	v.records = validRecords(v.records, config.Cache, v.clock())
+	v.updateSaving()
+	v.Unlock()
 	return nil
 }
 
+// Private: Visible for testing.
+func (v *CacheServer) GetCached(domain string, qtype uint16) []net.IP {
+	a, _ := v.getRecord(domain, qtype)
+	if a != nil && a.Expire.After(v.now()) {
+		return a.IPs
+	}
+	return nil
+}
+
+// getRecord returns the cached record of the given type for the given domain, even if it is expired, together
+// with the cache settings.
+func (v *CacheServer) getRecord(domain string, qtype uint16) (*CachedRecord, *dns.CacheConfig) {
+	v.RLock()
+	defer v.RUnlock()
+
+	record, found := v.records[domain]
+	if !found {
+		return nil, v.cache
+	}
+	if qtype == dnsmsg.TypeAAAA {
+		return record.AAAA, v.cache
+	}
+	return record.A, v.cache
+}
+
+// Get returns the IPs of the given domain. Depending on the query strategy in config, either A records,
+// AAAA records or both are queried.
 func (v *CacheServer) Get(domain string) []net.IP {
-	if ip, found := v.hosts[domain]; found {
-		return []net.IP{ip}
+	ips, _ := v.GetContext(context.Background(), domain)
+	return ips
+}
+
+func (v *CacheServer) GetWithStrategy(domain string, strategy dns.Config_QueryStrategy) []net.IP {
+	ips, _, _ := v.GetWithStrategyContext(context.Background(), domain, strategy)
+	return ips
+}
+
+// GetContext implements dns.ContextServer.
+func (v *CacheServer) GetContext(ctx context.Context, domain string) ([]net.IP, error) {
+	v.RLock()
+	strategy := v.strategy
+	v.RUnlock()
+
+	ips, _, err := v.resolve(ctx, domain, strategy)
+	return ips, err
+}
+
+// GetWithStrategyContext implements dns.ContextServer.
+func (v *CacheServer) GetWithStrategyContext(ctx context.Context, domain string, strategy dns.Config_QueryStrategy) ([]net.IP, time.Duration, error) {
+	return v.resolve(ctx, domain, strategy)
+}
+
+// resolve returns the IPs of the given domain and their time to live, and logs the query. IPs in static hosts and
+// sinkholes are filtered by the given strategy, in the same way as the ones from name servers.
+func (v *CacheServer) resolve(ctx context.Context, domain string, strategy dns.Config_QueryStrategy) ([]net.IP, time.Duration, error) {
+	start := time.Now()
+
+	v.RLock()
+	hosts := v.hosts
+	blockRules := v.blockRules
+	v.RUnlock()
+
+	var ips []net.IP
+	var upstream string
+	var err error
+	filterStatic := true
+	ttl := StaticTTL
+	if rule := matchBlockRule(blockRules, domain); rule != nil {
+		upstream = "block"
+		if len(rule.sinkhole) == 0 {
+			err = dns.ErrBlocked
+		} else {
+			ips = rule.sinkhole
+		}
+	} else if hostIPs, alias := hosts.Resolve(domain); len(hostIPs) > 0 || len(alias) == 0 {
+		upstream = "hosts"
+		ips = hostIPs
+	} else {
+		var record *CachedRecord
+		record, upstream, err = v.lookup(alias, strategy)
+		if record != nil {
+			ips = record.IPs
+			ttl = record.Expire.Sub(v.now())
+			if ttl <= 0 {
+				ttl = StaleTTL
+			}
+			if record.NotExist {
+				err = dns.ErrNotExist
+			}
+		}
+		filterStatic = false
+	}
+	if filterStatic {
+		switch strategy {
+		case dns.Config_USE_IP4:
+			ips = filterIPs(ips, true)
+		case dns.Config_USE_IP6:
+			ips = filterIPs(ips, false)
+		}
 	}
 
+	logQuery(ctx, domain, ips, upstream, err, time.Since(start))
+	return ips, ttl, err
+}
+
+// logQuery writes the given query to the DNS log, with the source, the inbound and the user in the given context.
+func logQuery(ctx context.Context, domain string, ips []net.IP, upstream string, err error, latency time.Duration) {
+	query := &log.DNSQuery{
+		Inbound:  proxy.InboundTagFromContext(ctx),
+		Domain:   domain,
+		Upstream: upstream,
+		Latency:  latency,
+	}
+	if source := proxy.SourceFromContext(ctx); source.IsValid() {
+		query.Source = source.String()
+	}
+	if user := protocol.UserFromContext(ctx); user != nil {
+		query.User = user.Email
+	}
+	switch {
+	case err == dns.ErrBlocked, err == dns.ErrNotExist:
+		query.Answer = "NXDOMAIN"
+	case err != nil:
+		query.Answer = "SERVFAIL"
+	case len(ips) == 0:
+		query.Answer = "NODATA"
+	default:
+		answer := make([]string, len(ips))
+		for idx, ip := range ips {
+			answer[idx] = ip.String()
+		}
+		query.Answer = strings.Join(answer, ",")
+	}
+	log.DNS(query)
+}
+
+// GetFakeIP implements dns.FakeIPServer. Domains in static hosts or blocked don't have fake IPs.
+func (v *CacheServer) GetFakeIP(ctx context.Context, domain string, ipv6 bool) (net.IP, bool) {
+	start := time.Now()
+
+	v.RLock()
+	hosts := v.hosts
+	blockRules := v.blockRules
+	fakeIPs := v.fakeIPs
+	v.RUnlock()
+
+	if fakeIPs == nil {
+		return nil, false
+	}
+	if hosts.Contains(domain) || matchBlockRule(blockRules, domain) != nil {
+		return nil, false
+	}
+	if fakeIPs.IsIPv4() == ipv6 {
+		logQuery(ctx, domain, nil, "fakeip", nil, time.Since(start))
+		return nil, true
+	}
+	ip := fakeIPs.Get(domain)
+	if ip == nil {
+		return nil, false
+	}
+	logQuery(ctx, domain, []net.IP{ip}, "fakeip", nil, time.Since(start))
+	return ip, true
+}
+
+// GetFakeDomain implements dns.FakeIPServer.
+func (v *CacheServer) GetFakeDomain(ip net.IP) string {
+	v.RLock()
+	fakeIPs := v.fakeIPs
+	v.RUnlock()
+
+	if fakeIPs == nil {
+		return ""
+	}
+	return fakeIPs.GetDomain(ip)
+}
+
+// lookup returns the record of the given domain from the cache or name servers, and where it is from.
+func (v *CacheServer) lookup(domain string, strategy dns.Config_QueryStrategy) (*CachedRecord, string, error) {
+	v.RLock()
+	servers := v.servers
+	v.RUnlock()
+
+	servers = selectServers(domain, servers)
 	domain = dnsmsg.Fqdn(domain)
-	ips := v.GetCached(domain)
-	if ips != nil {
-		return ips
+	switch strategy {
+	case dns.Config_USE_IP6:
+		return v.query(domain, servers, dnsmsg.TypeAAAA)
+	case dns.Config_PREFER_IP4:
+		if record, upstream, err := v.query(domain, servers, dnsmsg.TypeA); record != nil && (len(record.IPs) > 0 || record.NotExist) {
+			return record, upstream, err
+		}
+		return v.query(domain, servers, dnsmsg.TypeAAAA)
+	case dns.Config_PREFER_IP6:
+		if record, upstream, err := v.query(domain, servers, dnsmsg.TypeAAAA); record != nil && (len(record.IPs) > 0 || record.NotExist) {
+			return record, upstream, err
+		}
+		return v.query(domain, servers, dnsmsg.TypeA)
+	default:
+		return v.query(domain, servers, dnsmsg.TypeA)
+	}
+}
+
+// query returns the record of the given type for the given domain from the cache, or from the given name servers if
+// the domain is not cached. Expired records are returned within the serve-stale window, and refreshed in background.
+// It also returns where the record is from: "cache", "stale" or the name server.
+func (v *CacheServer) query(domain string, servers []*nameServerEntry, qtype uint16) (*CachedRecord, string, error) {
+	record, config := v.getRecord(domain, qtype)
+	if record != nil {
+		now := v.now()
+		prefetch := record.hit(now)
+		if record.Expire.After(now) {
+			if prefetch && config.GetPrefetch() {
+				log.Debug("DNS: Prefetching ", domain)
+				v.refresh(domain, servers, qtype, record)
+			}
+			return record, "cache", nil
+		}
+		if record.Expire.Add(staleDuration(config)).After(now) {
+			log.Debug("DNS: Returning stale record for domain ", domain)
+			v.refresh(domain, servers, qtype, record)
+			return record, "stale", nil
+		}
+	}
+	return v.fetch(domain, servers, qtype)
+}
+
+// refresh fetches the given record again in background, unless it is being refreshed already.
+func (v *CacheServer) refresh(domain string, servers []*nameServerEntry, qtype uint16, record *CachedRecord) {
+	if !atomic.CompareAndSwapInt32(&record.refreshing, 0, 1) {
+		return
 	}
+	go func() {
+		if _, _, err := v.fetch(domain, servers, qtype); err != nil {
+			atomic.StoreInt32(&record.refreshing, 0)
+		}
+	}()
+}
 
-	for _, server := range v.servers {
-		response := server.QueryA(domain)
+// fetch queries the given name servers in order for the IPs of the given type, and caches the first answer. It also
+// returns the name server answering, or ErrQueryFailed if none answers.
+func (v *CacheServer) fetch(domain string, servers []*nameServerEntry, qtype uint16) (*CachedRecord, string, error) {
+	for _, server := range servers {
+		var response <-chan *ARecord
+		if qtype == dnsmsg.TypeAAAA {
+			response = server.server.QueryAAAA(domain)
+		} else {
+			response = server.server.QueryA(domain)
+		}
 		select {
 		case a, open := <-response:
 			if !open || a == nil {
 				continue
 			}
+			if server.expectedIPs != nil {
+				a = server.filter(a)
+				if len(a.IPs) == 0 {
+					log.Info("DNS: No expected IP for domain ", domain, ", trying next name server.")
+					continue
+				}
+			}
 			v.Lock()
-			v.records[domain] = &DomainRecord{
-				A: a,
+			record, found := v.records[domain]
+			if !found {
+				record = new(DomainRecord)
+				v.records[domain] = record
+			}
+			cached := newCachedRecord(a, v.cache, v.clock())
+			if qtype == dnsmsg.TypeAAAA {
+				record.AAAA = cached
+			} else {
+				record.A = cached
 			}
 			v.Unlock()
 			log.Debug("DNS: Returning ", len(a.IPs), " IPs for domain ", domain)
-			return a.IPs
+			return cached, server.server.String(), nil
 		case <-time.After(QueryTimeout):
 		}
 	}
 
 	log.Debug("DNS: Returning nil for domain ", domain)
-	return nil
+	return nil, "", dns.ErrQueryFailed
 }
 
 func init() {
@@ -0,0 +1,347 @@
+package server_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	v2dns "v2ray.com/core/app/dns"
+	. "v2ray.com/core/app/dns/server"
+	"v2ray.com/core/common/buf"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+// directDispatcher dispatches connections to their destinations directly, counting the connections.
+type directDispatcher struct {
+	dials int32
+}
+
+func (*directDispatcher) Interface() interface{} {
+	return (*dispatcher.Interface)(nil)
+}
+
+func (d *directDispatcher) DispatchToOutbound(ctx context.Context) ray.InboundRay {
+	atomic.AddInt32(&d.dials, 1)
+	dest := proxy.DestinationFromContext(ctx)
+	stream := ray.NewRay(ctx)
+	go func() {
+		defer stream.OutboundOutput().Close()
+		conn, err := net.Dial("tcp", dest.NetAddr())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		go buf.PipeUntilEOF(stream.OutboundInput(), buf.NewWriter(conn))
+		buf.PipeUntilEOF(buf.NewReader(conn), stream.OutboundOutput())
+	}()
+	return stream
+}
+
+func answer(query *dns.Msg, ipv4 string) *dns.Msg {
+	reply := new(dns.Msg)
+	reply.SetReply(query)
+	question := query.Question[0]
+	header := dns.RR_Header{Name: question.Name, Rrtype: question.Qtype, Class: dns.ClassINET, Ttl: 300}
+	switch question.Qtype {
+	case dns.TypeA:
+		reply.Answer = append(reply.Answer, &dns.A{Hdr: header, A: net.ParseIP(ipv4)})
+	case dns.TypeAAAA:
+		reply.Answer = append(reply.Answer, &dns.AAAA{Hdr: header, AAAA: net.ParseIP("2001:db8::1")})
+	}
+	return reply
+}
+
+// tcpDNSServer is a DNS server over TCP, answering A queries with a fixed IP.
+type tcpDNSServer struct {
+	listener net.Listener
+	ipv4     string
+	queries  int32
+	// drops is the number of queries to drop by closing the connection.
+	drops int32
+	// rcode is the response code of the answers, which have no records if it is not success.
+	rcode int32
+}
+
+func startTCPDNSServer(t *testing.T, ipv4 string) *tcpDNSServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &tcpDNSServer{
+		listener: listener,
+		ipv4:     ipv4,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *tcpDNSServer) Destination() v2net.Destination {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return v2net.TCPDestination(v2net.IPAddress(addr.IP), v2net.Port(addr.Port))
+}
+
+func (s *tcpDNSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var length uint16
+		if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+			return
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return
+		}
+		query := new(dns.Msg)
+		if err := query.Unpack(payload); err != nil {
+			return
+		}
+		atomic.AddInt32(&s.queries, 1)
+		if atomic.AddInt32(&s.drops, -1) >= 0 {
+			return
+		}
+		msg := answer(query, s.ipv4)
+		if rcode := int(atomic.LoadInt32(&s.rcode)); rcode != dns.RcodeSuccess {
+			msg = new(dns.Msg)
+			msg.SetRcode(query, rcode)
+		}
+		reply, err := msg.Pack()
+		if err != nil {
+			return
+		}
+		binary.Write(conn, binary.BigEndian, uint16(len(reply)))
+		conn.Write(reply)
+	}
+}
+
+func TestTCPNameServer(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+
+	disp := new(directDispatcher)
+	server := NewTCPNameServer(dnsServer.Destination(), disp, nil)
+
+	a := server.QueryA("v2ray.com")
+	aaaa := server.QueryAAAA("v2ray.com")
+	record := <-a
+	assert.Int(len(record.IPs)).Equals(1)
+	assert.String(record.IPs[0].String()).Equals("10.0.0.1")
+	record = <-aaaa
+	assert.Int(len(record.IPs)).Equals(1)
+	assert.String(record.IPs[0].String()).Equals("2001:db8::1")
+	assert.Int(int(atomic.LoadInt32(&disp.dials))).Equals(1)
+}
+
+func TestTCPNameServerConnectionClosed(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	defer dnsServer.listener.Close()
+	dnsServer.drops = 1
+
+	server := NewTCPNameServer(dnsServer.Destination(), new(directDispatcher), nil)
+	select {
+	case record := <-server.QueryA("v2ray.com"):
+		assert.Pointer(record).IsNil()
+	case <-time.After(time.Second * 2):
+		t.Fatal("query is not failed when the connection is closed")
+	}
+
+	record := <-server.QueryA("v2ray.com")
+	assert.Int(len(record.IPs)).Equals(1)
+	assert.String(record.IPs[0].String()).Equals("10.0.0.1")
+}
+
+func TestHTTPSNameServer(t *testing.T) {
+	assert := assert.On(t)
+
+	var requests int32
+	httpServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Method != "POST" || r.URL.Path != "/dns-query" || r.Header.Get("Content-Type") != "application/dns-message" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		query := new(dns.Msg)
+		if err := query.Unpack(payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reply, _ := answer(query, "10.0.0.1").Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(reply)
+	}))
+	defer httpServer.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(httpServer.Certificate())
+	disp := new(directDispatcher)
+	addr := httpServer.Listener.Addr().(*net.TCPAddr)
+	server := NewHTTPSNameServer(v2net.TCPDestination(v2net.IPAddress(addr.IP), v2net.Port(addr.Port)), "/dns-query", disp, &tls.Config{RootCAs: roots})
+
+	for i := 0; i < 3; i++ {
+		record := <-server.QueryA("v2ray.com")
+		assert.Pointer(record).IsNotNil()
+		assert.Int(len(record.IPs)).Equals(1)
+		assert.String(record.IPs[0].String()).Equals("10.0.0.1")
+	}
+	record := <-server.QueryAAAA("v2ray.com")
+	assert.Pointer(record).IsNotNil()
+	assert.String(record.IPs[0].String()).Equals("2001:db8::1")
+
+	assert.Int(int(atomic.LoadInt32(&requests))).Equals(4)
+	assert.Int(int(atomic.LoadInt32(&disp.dials))).Equals(1)
+
+	bad := NewHTTPSNameServer(v2net.TCPDestination(v2net.IPAddress(addr.IP), v2net.Port(addr.Port)), "/unknown", disp, &tls.Config{RootCAs: roots})
+	_, open := <-bad.QueryA("v2ray.com")
+	assert.Bool(open).IsFalse()
+}
+
+func TestNameServerDomains(t *testing.T) {
+	assert := assert.On(t)
+
+	internal := startTCPDNSServer(t, "10.0.0.1")
+	defer internal.listener.Close()
+	bogus := startTCPDNSServer(t, "1.2.3.4")
+	defer bogus.listener.Close()
+	public := startTCPDNSServer(t, "1.2.3.4")
+	defer public.listener.Close()
+
+	endpoint := func(s *tcpDNSServer) *v2net.Endpoint {
+		dest := s.Destination()
+		return &v2net.Endpoint{
+			Network: v2net.Network_TCP,
+			Address: v2net.NewIPOrDomain(dest.Address),
+			Port:    uint32(dest.Port),
+		}
+	}
+	expectedIP := []*v2dns.CIDR{
+		{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+	}
+	config := &v2dns.Config{
+		NameServer: []*v2dns.NameServer{
+			{
+				Type:       v2dns.NameServer_TCP,
+				Address:    endpoint(bogus),
+				Domain:     []*v2dns.Domain{{Type: v2dns.Domain_Full, Value: "www.corp.example.com"}},
+				ExpectedIp: expectedIP,
+			},
+			{
+				Type:       v2dns.NameServer_TCP,
+				Address:    endpoint(internal),
+				Domain:     []*v2dns.Domain{{Type: v2dns.Domain_Subdomain, Value: "corp.example.com"}},
+				ExpectedIp: expectedIP,
+			},
+			{
+				Type:    v2dns.NameServer_TCP,
+				Address: endpoint(public),
+			},
+		},
+	}
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(new(directDispatcher))).IsNil()
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	for domain, ip := range map[string]string{
+		"www.corp.example.com":  "10.0.0.1",
+		"mail.corp.example.com": "10.0.0.1",
+		"v2ray.com":             "1.2.3.4",
+	} {
+		ips := server.Get(domain)
+		if len(ips) != 1 || ips[0].String() != ip {
+			t.Error("unexpected IPs for ", domain, ": ", ips)
+		}
+	}
+	assert.Int(int(atomic.LoadInt32(&bogus.queries))).Equals(1)
+	assert.Int(int(atomic.LoadInt32(&internal.queries))).Equals(2)
+	assert.Int(int(atomic.LoadInt32(&public.queries))).Equals(1)
+
+	config.NameServer[0].Domain[0].Type = v2dns.Domain_Regex
+	config.NameServer[0].Domain[0].Value = "("
+	assert.Error(server.Reload(config)).IsNotNil()
+}
+
+func TestCacheServerQueryFailed(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer := startTCPDNSServer(t, "10.0.0.1")
+	server := newCacheServer(t, dnsServer, nil)
+	dnsServer.listener.Close()
+
+	ips, err := server.GetContext(context.Background(), "v2ray.com")
+	assert.Error(err).Equals(v2dns.ErrQueryFailed)
+	assert.Int(len(ips)).Equals(0)
+}
+
+func TestCacheServerRcode(t *testing.T) {
+	assert := assert.On(t)
+
+	failing := startTCPDNSServer(t, "10.0.0.1")
+	defer failing.listener.Close()
+	atomic.StoreInt32(&failing.rcode, dns.RcodeServerFailure)
+	dnsServer := startTCPDNSServer(t, "10.0.0.2")
+	defer dnsServer.listener.Close()
+
+	config := &v2dns.Config{}
+	for _, s := range []*tcpDNSServer{failing, dnsServer} {
+		dest := s.Destination()
+		config.NameServers = append(config.NameServers, &v2net.Endpoint{
+			Network: v2net.Network_TCP,
+			Address: v2net.NewIPOrDomain(dest.Address),
+			Port:    uint32(dest.Port),
+		})
+	}
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(new(directDispatcher))).IsNil()
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ips, err := server.GetContext(context.Background(), "v2ray.com")
+	assert.Error(err).IsNil()
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("10.0.0.2")
+
+	atomic.StoreInt32(&dnsServer.rcode, dns.RcodeNameError)
+	ips, err = server.GetContext(context.Background(), "www.v2ray.com")
+	assert.Error(err).Equals(v2dns.ErrNotExist)
+	assert.Int(len(ips)).Equals(0)
+
+	// The nonexistent domain is cached.
+	queries := atomic.LoadInt32(&dnsServer.queries)
+	_, err = server.GetContext(context.Background(), "www.v2ray.com")
+	assert.Error(err).Equals(v2dns.ErrNotExist)
+	assert.Int(int(atomic.LoadInt32(&dnsServer.queries))).Equals(int(queries))
+}
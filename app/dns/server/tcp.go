@@ -0,0 +1,175 @@
+package server
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	v2net "v2ray.com/core/common/net"
+)
+
+// TCPNameServer is a NameServer that sends queries over TCP, or TLS if a TLS config is given. All queries are
+// pipelined on a single connection, which is reopened for the next query when closed. Queries pending on a closed
+// connection fail immediately.
+type TCPNameServer struct {
+	pendingRequests
+	address    v2net.Destination
+	dispatcher dispatcher.Interface
+	tlsConfig  *tls.Config
+
+	connLock sync.Mutex
+	conn     net.Conn
+}
+
+func NewTCPNameServer(address v2net.Destination, dispatcher dispatcher.Interface, tlsConfig *tls.Config) *TCPNameServer {
+	return &TCPNameServer{
+		address:    address,
+		dispatcher: dispatcher,
+		tlsConfig:  tlsConfig,
+	}
+}
+
+func (v *TCPNameServer) String() string {
+	if v.tlsConfig != nil {
+		return "tls:" + v.address.NetAddr()
+	}
+	return v.address.String()
+}
+
+func (v *TCPNameServer) QueryA(domain string) <-chan *ARecord {
+	return v.query(domain, dns.TypeA)
+}
+
+func (v *TCPNameServer) QueryAAAA(domain string) <-chan *ARecord {
+	return v.query(domain, dns.TypeAAAA)
+}
+
+// query sends the query in background, so that a stalled connection doesn't block the caller, who waits for the
+// response with a timeout.
+func (v *TCPNameServer) query(domain string, qtype uint16) <-chan *ARecord {
+	response := make(chan *ARecord, 1)
+	id := v.AssignUnusedID(response)
+
+	go func() {
+		query := buildQuery(domain, id, qtype)
+		defer query.Release()
+		if err := v.send(id, query.Bytes()); err != nil {
+			log.Warning("DNS: Failed to send query to ", v.address, ": ", err)
+			v.cancel(id)
+		}
+	}()
+	return response
+}
+
+// send writes the given query to the connection, prefixed by its length as in RFC 1035 4.2.2. If the existing
+// connection is broken, a new one is opened and the query is sent again.
+func (v *TCPNameServer) send(id uint16, query []byte) error {
+	payload := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(payload, uint16(len(query)))
+	copy(payload[2:], query)
+
+	v.connLock.Lock()
+	defer v.connLock.Unlock()
+
+	reused := v.conn != nil
+	if !reused {
+		conn, err := v.dial()
+		if err != nil {
+			return err
+		}
+		v.conn = conn
+	}
+	_, err := v.conn.Write(payload)
+	if err != nil && reused {
+		v.conn.Close()
+		v.conn = nil
+		conn, err := v.dial()
+		if err != nil {
+			return err
+		}
+		v.conn = conn
+		_, err = v.conn.Write(payload)
+	}
+	if err != nil {
+		v.conn.Close()
+		v.conn = nil
+		return err
+	}
+	v.sentOn(id, v.conn)
+	return nil
+}
+
+// dial opens a new connection, and completes the TLS handshake if TLS is used. As connections through the dispatcher
+// have no deadline, the handshake is aborted by closing the connection after QueryTimeout.
+func (v *TCPNameServer) dial() (net.Conn, error) {
+	log.Info("DNS: Opening connection to ", v.address)
+	conn := dialThroughDispatcher(v.dispatcher, v.address)
+	if v.tlsConfig != nil {
+		tlsConn := tls.Client(conn, v.tlsConfig)
+		timer := time.AfterFunc(QueryTimeout, func() {
+			conn.Close()
+		})
+		err := tlsConn.Handshake()
+		if !timer.Stop() && err == nil {
+			err = errors.New("DNS: TLS handshake timed out.")
+		}
+		if err != nil {
+			conn.Close()
+			return nil, errors.Base(err).Message("DNS: Failed to complete TLS handshake with ", v.address)
+		}
+		conn = tlsConn
+	}
+	go v.readResponses(conn)
+	return conn, nil
+}
+
+func (v *TCPNameServer) readResponses(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := readMsg(reader)
+		if err != nil {
+			if err != io.EOF {
+				log.Info("DNS: Connection to ", v.address, " closed: ", err)
+			}
+			break
+		}
+		v.handleMsg(msg)
+	}
+
+	v.connLock.Lock()
+	if v.conn == conn {
+		v.conn = nil
+	}
+	v.connLock.Unlock()
+	conn.Close()
+
+	if cancelled := v.cancelConn(conn); cancelled > 0 {
+		log.Info("DNS: ", cancelled, " queries to ", v.address, " failed as the connection is closed.")
+	}
+}
+
+// readMsg reads a length prefixed DNS message from the given reader.
+func readMsg(reader io.Reader) (*dns.Msg, error) {
+	var length uint16
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	payload := buf.NewLocal(int(length))
+	if err := payload.AppendSupplier(buf.ReadFullFrom(reader, int(length))); err != nil {
+		return nil, err
+	}
+	msg := new(dns.Msg)
+	if err := msg.Unpack(payload.Bytes()); err != nil {
+		return nil, errors.Base(err).Message("DNS: Failed to parse DNS response.")
+	}
+	return msg, nil
+}
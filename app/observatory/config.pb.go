@@ -0,0 +1,170 @@
+package observatory
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Config is the settings of the observatory, which probes outbound handlers periodically.
+type Config struct {
+	// Outbound handlers whose tags start with any of the selectors are probed.
+	SubjectSelector []string `protobuf:"bytes,1,rep,name=subject_selector,json=subjectSelector" json:"subject_selector,omitempty"`
+	// Address of the probe target. A HTTP HEAD request is sent to this address through each outbound handler.
+	ProbeAddress *v2ray_core_common_net.IPOrDomain `protobuf:"bytes,2,opt,name=probe_address,json=probeAddress" json:"probe_address,omitempty"`
+	// Port of the probe target. Default to 80.
+	ProbePort uint32 `protobuf:"varint,3,opt,name=probe_port,json=probePort" json:"probe_port,omitempty"`
+	// Interval between two rounds of probes, in seconds. Default to 60.
+	ProbeInterval uint32 `protobuf:"varint,4,opt,name=probe_interval,json=probeInterval" json:"probe_interval,omitempty"`
+	// Timeout of a probe, in seconds. Default to 10.
+	ProbeTimeout uint32 `protobuf:"varint,5,opt,name=probe_timeout,json=probeTimeout" json:"probe_timeout,omitempty"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Config) GetSubjectSelector() []string {
+	if m != nil {
+		return m.SubjectSelector
+	}
+	return nil
+}
+
+func (m *Config) GetProbeAddress() *v2ray_core_common_net.IPOrDomain {
+	if m != nil {
+		return m.ProbeAddress
+	}
+	return nil
+}
+
+func (m *Config) GetProbePort() uint32 {
+	if m != nil {
+		return m.ProbePort
+	}
+	return 0
+}
+
+func (m *Config) GetProbeInterval() uint32 {
+	if m != nil {
+		return m.ProbeInterval
+	}
+	return 0
+}
+
+func (m *Config) GetProbeTimeout() uint32 {
+	if m != nil {
+		return m.ProbeTimeout
+	}
+	return 0
+}
+
+// OutboundStatus is the result of the latest probe of an outbound handler.
+type OutboundStatus struct {
+	OutboundTag string `protobuf:"bytes,1,opt,name=outbound_tag,json=outboundTag" json:"outbound_tag,omitempty"`
+	// Whether the latest probe succeeded.
+	Alive bool `protobuf:"varint,2,opt,name=alive" json:"alive,omitempty"`
+	// Latency of the latest successful probe, in milliseconds.
+	Delay int64 `protobuf:"varint,3,opt,name=delay" json:"delay,omitempty"`
+	// Reason of the latest failed probe.
+	LastErrorReason string `protobuf:"bytes,4,opt,name=last_error_reason,json=lastErrorReason" json:"last_error_reason,omitempty"`
+	// Unix time of the latest successful probe.
+	LastSeenTime int64 `protobuf:"varint,5,opt,name=last_seen_time,json=lastSeenTime" json:"last_seen_time,omitempty"`
+	// Unix time of the latest probe.
+	LastTryTime int64 `protobuf:"varint,6,opt,name=last_try_time,json=lastTryTime" json:"last_try_time,omitempty"`
+}
+
+func (m *OutboundStatus) Reset()                    { *m = OutboundStatus{} }
+func (m *OutboundStatus) String() string            { return proto.CompactTextString(m) }
+func (*OutboundStatus) ProtoMessage()               {}
+func (*OutboundStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *OutboundStatus) GetOutboundTag() string {
+	if m != nil {
+		return m.OutboundTag
+	}
+	return ""
+}
+
+func (m *OutboundStatus) GetAlive() bool {
+	if m != nil {
+		return m.Alive
+	}
+	return false
+}
+
+func (m *OutboundStatus) GetDelay() int64 {
+	if m != nil {
+		return m.Delay
+	}
+	return 0
+}
+
+func (m *OutboundStatus) GetLastErrorReason() string {
+	if m != nil {
+		return m.LastErrorReason
+	}
+	return ""
+}
+
+func (m *OutboundStatus) GetLastSeenTime() int64 {
+	if m != nil {
+		return m.LastSeenTime
+	}
+	return 0
+}
+
+func (m *OutboundStatus) GetLastTryTime() int64 {
+	if m != nil {
+		return m.LastTryTime
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.observatory.Config")
+	proto.RegisterType((*OutboundStatus)(nil), "v2ray.core.app.observatory.OutboundStatus")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/observatory/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 416 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x92, 0xe1, 0xaa, 0xd3, 0x30,
+	0x14, 0xc7, 0xe9, 0x9d, 0x77, 0xb8, 0x74, 0xdb, 0xd5, 0xe0, 0x87, 0x32, 0x50, 0x76, 0xa7, 0xe2,
+	0x54, 0x48, 0x61, 0x3e, 0xc1, 0xbd, 0x57, 0x85, 0x7d, 0xda, 0xc8, 0x86, 0x82, 0x5f, 0x4a, 0xda,
+	0x1d, 0x47, 0xa5, 0xcd, 0x29, 0x27, 0xe9, 0xa0, 0xaf, 0xe4, 0xfb, 0xf8, 0x0a, 0x3e, 0x87, 0x24,
+	0xe9, 0x70, 0x08, 0x7e, 0xcc, 0xef, 0xfc, 0xce, 0x39, 0xff, 0x90, 0xb0, 0xf7, 0xa7, 0x15, 0xa9,
+	0x4e, 0x14, 0x58, 0xa7, 0x05, 0x12, 0xa4, 0xaa, 0x69, 0x52, 0xcc, 0x0d, 0xd0, 0x49, 0x59, 0xa4,
+	0x2e, 0x2d, 0x50, 0x7f, 0x2f, 0x8f, 0xa2, 0x21, 0xb4, 0xc8, 0x67, 0x67, 0x99, 0x40, 0xa8, 0xa6,
+	0x11, 0x17, 0xe2, 0xec, 0xcd, 0x3f, 0x83, 0x0a, 0xac, 0x6b, 0xd4, 0xa9, 0x06, 0x9b, 0xaa, 0xc3,
+	0x81, 0xc0, 0x98, 0x30, 0x64, 0xf1, 0x3b, 0x62, 0xc3, 0x07, 0x3f, 0x95, 0xbf, 0x65, 0x4f, 0x4c,
+	0x9b, 0xff, 0x80, 0xc2, 0x66, 0x06, 0x2a, 0x28, 0x2c, 0x52, 0x12, 0xcd, 0x07, 0xcb, 0x91, 0xbc,
+	0xe9, 0xf9, 0xae, 0xc7, 0xfc, 0x33, 0x9b, 0x34, 0x84, 0x39, 0x64, 0xfd, 0xb0, 0xe4, 0x6a, 0x1e,
+	0x2d, 0xe3, 0xd5, 0xad, 0xb8, 0x88, 0x14, 0x56, 0x0a, 0x0d, 0x56, 0xac, 0xb7, 0x1b, 0xfa, 0x88,
+	0xb5, 0x2a, 0xb5, 0x1c, 0xfb, 0xbe, 0xbb, 0xd0, 0xc6, 0x9f, 0x33, 0x16, 0xe6, 0x34, 0x48, 0x36,
+	0x19, 0xcc, 0xa3, 0xe5, 0x44, 0x8e, 0x3c, 0xd9, 0x22, 0x59, 0xfe, 0x9a, 0x4d, 0x43, 0xb9, 0xd4,
+	0xd6, 0x5d, 0xad, 0x4a, 0x1e, 0x79, 0x25, 0x2c, 0x5f, 0xf7, 0x90, 0xbf, 0x3c, 0xa7, 0xb1, 0x65,
+	0x0d, 0xd8, 0xda, 0xe4, 0xda, 0x5b, 0x61, 0xd5, 0x3e, 0xb0, 0xc5, 0xaf, 0x88, 0x4d, 0x37, 0xad,
+	0xcd, 0xb1, 0xd5, 0x87, 0x9d, 0x55, 0xb6, 0x35, 0xfc, 0x96, 0x8d, 0xb1, 0x27, 0x99, 0x55, 0xc7,
+	0x24, 0x9a, 0x47, 0xcb, 0x91, 0x8c, 0xcf, 0x6c, 0xaf, 0x8e, 0xfc, 0x19, 0xbb, 0x56, 0x55, 0x79,
+	0x02, 0x7f, 0xc1, 0xc7, 0x32, 0x1c, 0x1c, 0x3d, 0x40, 0xa5, 0x3a, 0x9f, 0x78, 0x20, 0xc3, 0x81,
+	0xbf, 0x63, 0x4f, 0x2b, 0x65, 0x6c, 0x06, 0x44, 0x48, 0x19, 0x81, 0x32, 0xa8, 0x7d, 0xe0, 0x91,
+	0xbc, 0x71, 0x85, 0x4f, 0x8e, 0x4b, 0x8f, 0xf9, 0x2b, 0x36, 0xf5, 0xae, 0x01, 0xd0, 0x3e, 0xb6,
+	0xcf, 0x3c, 0x90, 0x63, 0x47, 0x77, 0x00, 0xda, 0xc5, 0xe6, 0x0b, 0x36, 0xf1, 0x96, 0xa5, 0x2e,
+	0x48, 0x43, 0x2f, 0xc5, 0x0e, 0xee, 0xa9, 0x73, 0xce, 0xfd, 0x57, 0xf6, 0xa2, 0xc0, 0x5a, 0xfc,
+	0xff, 0x2f, 0xdc, 0xc7, 0xe1, 0x7d, 0xb7, 0xee, 0xbd, 0xbf, 0xc5, 0x17, 0x95, 0x9f, 0x57, 0xb3,
+	0x2f, 0x2b, 0xa9, 0x3a, 0xf1, 0xe0, 0xda, 0xee, 0x9a, 0x46, 0x6c, 0xfe, 0x16, 0xf3, 0xa1, 0xff,
+	0x20, 0x1f, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0x3c, 0x74, 0x81, 0x65, 0x94, 0x02, 0x00, 0x00,
+}
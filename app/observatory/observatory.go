@@ -0,0 +1,243 @@
+// Package observatory probes outbound handlers periodically, so that dead handlers can be skipped by routing.
+package observatory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/ray"
+)
+
+// Observatory sends probe requests through outbound handlers periodically, and records the results.
+type Observatory struct {
+	sync.RWMutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	config   *Config
+	dest     v2net.Destination
+	interval time.Duration
+	timeout  time.Duration
+	ohm      proxyman.OutboundHandlerManager
+	status   map[string]*OutboundStatus
+}
+
+func NewObservatory(ctx context.Context, config *Config) (*Observatory, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, errors.New("Observatory: No space in context.")
+	}
+	if config.ProbeAddress == nil {
+		return nil, errors.New("Observatory: Probe address is not specified.")
+	}
+
+	port := config.ProbePort
+	if port == 0 {
+		port = 80
+	}
+	interval := time.Duration(config.ProbeInterval) * time.Second
+	if interval == 0 {
+		interval = time.Minute
+	}
+	timeout := time.Duration(config.ProbeTimeout) * time.Second
+	if timeout == 0 {
+		timeout = time.Second * 10
+	}
+
+	o := &Observatory{
+		ctx:      ctx,
+		config:   config,
+		dest:     v2net.TCPDestination(config.ProbeAddress.AsAddress(), v2net.Port(port)),
+		interval: interval,
+		timeout:  timeout,
+		status:   make(map[string]*OutboundStatus),
+	}
+	space.OnInitialize(func() error {
+		o.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
+		if o.ohm == nil {
+			return errors.New("Observatory: OutboundHandlerManager is not found in the space.")
+		}
+		return nil
+	})
+	return o, nil
+}
+
+func (*Observatory) Interface() interface{} {
+	return (*Observatory)(nil)
+}
+
+// Start starts probing in background. The first round of probes starts immediately.
+func (o *Observatory) Start() error {
+	ctx, cancel := context.WithCancel(o.ctx)
+	o.cancel = cancel
+	go func() {
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+
+		for {
+			o.Probe(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	log.Info("Observatory: Probing ", o.dest, " every ", o.interval)
+	return nil
+}
+
+func (o *Observatory) Close() {
+	if o.cancel != nil {
+		o.cancel()
+	}
+}
+
+func (o *Observatory) selectSubjects(ctx context.Context) []string {
+	var tags []string
+	for _, tag := range o.ohm.ListTags(ctx) {
+		for _, selector := range o.config.SubjectSelector {
+			if strings.HasPrefix(tag, selector) {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// Probe runs one round of probes on all selected outbound handlers, and waits for the results.
+func (o *Observatory) Probe(ctx context.Context) {
+	tags := o.selectSubjects(ctx)
+	results := make([]*OutboundStatus, len(tags))
+
+	var wg sync.WaitGroup
+	for idx, tag := range tags {
+		handler := o.ohm.GetHandler(tag)
+		if handler == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, handler proxyman.OutboundHandler) {
+			defer wg.Done()
+			results[idx] = o.probe(ctx, handler)
+		}(idx, handler)
+	}
+	wg.Wait()
+
+	o.Lock()
+	defer o.Unlock()
+
+	status := make(map[string]*OutboundStatus, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		previous, found := o.status[result.OutboundTag]
+		if found {
+			if !result.Alive {
+				result.Delay = previous.Delay
+				result.LastSeenTime = previous.LastSeenTime
+			}
+			if previous.Alive != result.Alive {
+				if result.Alive {
+					log.Warning("Observatory: Outbound [", result.OutboundTag, "] is up.")
+				} else {
+					log.Warning("Observatory: Outbound [", result.OutboundTag, "] is down: ", result.LastErrorReason)
+				}
+			}
+		}
+		status[result.OutboundTag] = result
+	}
+	o.status = status
+}
+
+// probe sends a HTTP HEAD request to the probe target through the given handler. The probe succeeds if there is any
+// response before timeout.
+func (o *Observatory) probe(ctx context.Context, handler proxyman.OutboundHandler) *OutboundStatus {
+	status := &OutboundStatus{
+		OutboundTag: handler.Tag(),
+		LastTryTime: time.Now().Unix(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	ctx = proxy.ContextWithDestination(ctx, o.dest)
+	link := ray.NewRay(ctx)
+	defer link.InboundOutput().CloseError()
+
+	start := time.Now()
+	go handler.Dispatch(ctx, link)
+
+	request := buf.New()
+	request.Append([]byte("HEAD / HTTP/1.1\r\nHost: " + o.dest.Address.String() + "\r\nConnection: close\r\n\r\n"))
+	if err := link.InboundInput().Write(request); err != nil {
+		status.LastErrorReason = err.Error()
+		return status
+	}
+
+	response, err := link.InboundOutput().ReadTimeout(o.timeout)
+	if err != nil {
+		status.LastErrorReason = err.Error()
+		return status
+	}
+	response.Release()
+
+	status.Alive = true
+	status.Delay = int64(time.Since(start) / time.Millisecond)
+	status.LastSeenTime = time.Now().Unix()
+	return status
+}
+
+// GetStatus returns the latest probe result of the given outbound handler, or nil if it has not been probed.
+func (o *Observatory) GetStatus(tag string) *OutboundStatus {
+	o.RLock()
+	defer o.RUnlock()
+
+	return o.status[tag]
+}
+
+// IsAlive returns false if the latest probe of the given outbound handler failed, and true otherwise.
+func (o *Observatory) IsAlive(tag string) bool {
+	status := o.GetStatus(tag)
+	return status == nil || status.Alive
+}
+
+// ListStatus returns the latest probe results of all probed outbound handlers, sorted by tag.
+func (o *Observatory) ListStatus() []*OutboundStatus {
+	o.RLock()
+	defer o.RUnlock()
+
+	list := make([]*OutboundStatus, 0, len(o.status))
+	for _, status := range o.status {
+		list = append(list, status)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].OutboundTag < list[j].OutboundTag
+	})
+	return list
+}
+
+func FromSpace(space app.Space) *Observatory {
+	app := space.GetApplication((*Observatory)(nil))
+	if app == nil {
+		return nil
+	}
+	return app.(*Observatory)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewObservatory(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,71 @@
+package observatory_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app"
+	. "v2ray.com/core/app/observatory"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/testing/servers/tcp"
+	_ "v2ray.com/core/transport/internet/tcp"
+)
+
+func TestProbe(t *testing.T) {
+	assert := assert.On(t)
+
+	tcpServer := tcp.Server{
+		MsgProcessor: func(msg []byte) []byte {
+			return []byte("HTTP/1.1 200 OK\r\n\r\n")
+		},
+	}
+	dest, err := tcpServer.Start()
+	assert.Error(err).IsNil()
+	defer tcpServer.Close()
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, &Config{
+		SubjectSelector: []string{"probe-"},
+		ProbeAddress:    net.NewIPOrDomain(dest.Address),
+		ProbePort:       uint32(dest.Port),
+		ProbeTimeout:    2,
+	})).IsNil()
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(space)
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "probe-ok",
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+	})).IsNil()
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "probe-dead",
+		ProxySettings: serial.ToTypedMessage(new(blackhole.Config)),
+	})).IsNil()
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "other",
+		ProxySettings: serial.ToTypedMessage(new(blackhole.Config)),
+	})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	observer := FromSpace(space)
+	observer.Probe(ctx)
+
+	status := observer.ListStatus()
+	assert.Int(len(status)).Equals(2)
+	assert.String(status[0].OutboundTag).Equals("probe-dead")
+	assert.Bool(status[0].Alive).IsFalse()
+	assert.String(status[1].OutboundTag).Equals("probe-ok")
+	assert.Bool(status[1].Alive).IsTrue()
+
+	assert.Bool(observer.IsAlive("probe-ok")).IsTrue()
+	assert.Bool(observer.IsAlive("probe-dead")).IsFalse()
+	assert.Bool(observer.IsAlive("other")).IsTrue()
+	assert.Bool(observer.GetStatus("other") == nil).IsTrue()
+}
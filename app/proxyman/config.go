@@ -3,6 +3,7 @@ package proxyman
 import (
 	"context"
 	"errors"
+	"time"
 
 	"v2ray.com/core/proxy"
 )
@@ -31,3 +32,17 @@ func (c *OutboundHandlerConfig) GetProxyHandler(ctx context.Context) (proxy.Outb
 	}
 	return proxy.CreateOutboundHandler(ctx, config)
 }
+
+// ExpireTime returns the time when the handler expires, or zero time if the handler never expires.
+func (c *OutboundHandlerConfig) ExpireTime() time.Time {
+	if c.Expire <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.Expire, 0)
+}
+
+// HasExpired returns true if the handler has expired at the given time.
+func (c *OutboundHandlerConfig) HasExpired(now time.Time) bool {
+	expire := c.ExpireTime()
+	return !expire.IsZero() && !now.Before(expire)
+}
@@ -141,6 +141,7 @@ type ReceiverConfig struct {
 	StreamSettings             *v2ray_core_transport_internet.StreamConfig `protobuf:"bytes,4,opt,name=stream_settings,json=streamSettings" json:"stream_settings,omitempty"`
 	ReceiveOriginalDestination bool                                        `protobuf:"varint,5,opt,name=receive_original_destination,json=receiveOriginalDestination" json:"receive_original_destination,omitempty"`
 	AllowPassiveConnection     bool                                        `protobuf:"varint,6,opt,name=allow_passive_connection,json=allowPassiveConnection" json:"allow_passive_connection,omitempty"`
+	SniffingSettings           *SniffingConfig                             `protobuf:"bytes,7,opt,name=sniffing_settings,json=sniffingSettings" json:"sniffing_settings,omitempty"`
 }
 
 func (m *ReceiverConfig) Reset()                    { *m = ReceiverConfig{} }
@@ -190,6 +191,49 @@ func (m *ReceiverConfig) GetAllowPassiveConnection() bool {
 	return false
 }
 
+func (m *ReceiverConfig) GetSniffingSettings() *SniffingConfig {
+	if m != nil {
+		return m.SniffingSettings
+	}
+	return nil
+}
+
+// SniffingConfig controls recovering the domain of a connection from its first payload.
+type SniffingConfig struct {
+	// Whether to sniff the first payload of each connection.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
+	// Protocols to sniff, "http" and "tls". The domain sniffed by any of the protocols overrides the destination.
+	DestinationOverride []string `protobuf:"bytes,2,rep,name=destination_override,json=destinationOverride" json:"destination_override,omitempty"`
+	// If true, the sniffed domain is only used for routing, and the connection goes to the original destination.
+	RouteOnly bool `protobuf:"varint,3,opt,name=route_only,json=routeOnly" json:"route_only,omitempty"`
+}
+
+func (m *SniffingConfig) Reset()                    { *m = SniffingConfig{} }
+func (m *SniffingConfig) String() string            { return proto.CompactTextString(m) }
+func (*SniffingConfig) ProtoMessage()               {}
+func (*SniffingConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *SniffingConfig) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *SniffingConfig) GetDestinationOverride() []string {
+	if m != nil {
+		return m.DestinationOverride
+	}
+	return nil
+}
+
+func (m *SniffingConfig) GetRouteOnly() bool {
+	if m != nil {
+		return m.RouteOnly
+	}
+	return false
+}
+
 type InboundHandlerConfig struct {
 	Tag              string                                 `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
 	ReceiverSettings *v2ray_core_common_serial.TypedMessage `protobuf:"bytes,2,opt,name=receiver_settings,json=receiverSettings" json:"receiver_settings,omitempty"`
@@ -199,7 +243,7 @@ type InboundHandlerConfig struct {
 func (m *InboundHandlerConfig) Reset()                    { *m = InboundHandlerConfig{} }
 func (m *InboundHandlerConfig) String() string            { return proto.CompactTextString(m) }
 func (*InboundHandlerConfig) ProtoMessage()               {}
-func (*InboundHandlerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*InboundHandlerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *InboundHandlerConfig) GetTag() string {
 	if m != nil {
@@ -228,19 +272,20 @@ type OutboundConfig struct {
 func (m *OutboundConfig) Reset()                    { *m = OutboundConfig{} }
 func (m *OutboundConfig) String() string            { return proto.CompactTextString(m) }
 func (*OutboundConfig) ProtoMessage()               {}
-func (*OutboundConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*OutboundConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 type SenderConfig struct {
 	// Send traffic through the given IP. Only IP is allowed.
-	Via            *v2ray_core_common_net.IPOrDomain           `protobuf:"bytes,1,opt,name=via" json:"via,omitempty"`
-	StreamSettings *v2ray_core_transport_internet.StreamConfig `protobuf:"bytes,2,opt,name=stream_settings,json=streamSettings" json:"stream_settings,omitempty"`
-	ProxySettings  *v2ray_core_transport_internet.ProxyConfig  `protobuf:"bytes,3,opt,name=proxy_settings,json=proxySettings" json:"proxy_settings,omitempty"`
+	Via               *v2ray_core_common_net.IPOrDomain           `protobuf:"bytes,1,opt,name=via" json:"via,omitempty"`
+	StreamSettings    *v2ray_core_transport_internet.StreamConfig `protobuf:"bytes,2,opt,name=stream_settings,json=streamSettings" json:"stream_settings,omitempty"`
+	ProxySettings     *v2ray_core_transport_internet.ProxyConfig  `protobuf:"bytes,3,opt,name=proxy_settings,json=proxySettings" json:"proxy_settings,omitempty"`
+	MultiplexSettings *MultiplexingConfig                         `protobuf:"bytes,4,opt,name=multiplex_settings,json=multiplexSettings" json:"multiplex_settings,omitempty"`
 }
 
 func (m *SenderConfig) Reset()                    { *m = SenderConfig{} }
 func (m *SenderConfig) String() string            { return proto.CompactTextString(m) }
 func (*SenderConfig) ProtoMessage()               {}
-func (*SenderConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*SenderConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *SenderConfig) GetVia() *v2ray_core_common_net.IPOrDomain {
 	if m != nil {
@@ -263,6 +308,40 @@ func (m *SenderConfig) GetProxySettings() *v2ray_core_transport_internet.ProxyCo
 	return nil
 }
 
+func (m *SenderConfig) GetMultiplexSettings() *MultiplexingConfig {
+	if m != nil {
+		return m.MultiplexSettings
+	}
+	return nil
+}
+
+// MultiplexingConfig controls carrying multiple connections over a single outbound connection.
+type MultiplexingConfig struct {
+	// Whether to multiplex connections of this outbound handler.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
+	// Max number of concurrent connections over a single outbound connection. Default to 8.
+	Concurrency uint32 `protobuf:"varint,2,opt,name=concurrency" json:"concurrency,omitempty"`
+}
+
+func (m *MultiplexingConfig) Reset()                    { *m = MultiplexingConfig{} }
+func (m *MultiplexingConfig) String() string            { return proto.CompactTextString(m) }
+func (*MultiplexingConfig) ProtoMessage()               {}
+func (*MultiplexingConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *MultiplexingConfig) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *MultiplexingConfig) GetConcurrency() uint32 {
+	if m != nil {
+		return m.Concurrency
+	}
+	return 0
+}
+
 type OutboundHandlerConfig struct {
 	Tag            string                                 `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
 	SenderSettings *v2ray_core_common_serial.TypedMessage `protobuf:"bytes,2,opt,name=sender_settings,json=senderSettings" json:"sender_settings,omitempty"`
@@ -274,7 +353,7 @@ type OutboundHandlerConfig struct {
 func (m *OutboundHandlerConfig) Reset()                    { *m = OutboundHandlerConfig{} }
 func (m *OutboundHandlerConfig) String() string            { return proto.CompactTextString(m) }
 func (*OutboundHandlerConfig) ProtoMessage()               {}
-func (*OutboundHandlerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*OutboundHandlerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *OutboundHandlerConfig) GetTag() string {
 	if m != nil {
@@ -317,9 +396,11 @@ func init() {
 	proto.RegisterType((*AllocationStrategy_AllocationStrategyConcurrency)(nil), "v2ray.core.app.proxyman.AllocationStrategy.AllocationStrategyConcurrency")
 	proto.RegisterType((*AllocationStrategy_AllocationStrategyRefresh)(nil), "v2ray.core.app.proxyman.AllocationStrategy.AllocationStrategyRefresh")
 	proto.RegisterType((*ReceiverConfig)(nil), "v2ray.core.app.proxyman.ReceiverConfig")
+	proto.RegisterType((*SniffingConfig)(nil), "v2ray.core.app.proxyman.SniffingConfig")
 	proto.RegisterType((*InboundHandlerConfig)(nil), "v2ray.core.app.proxyman.InboundHandlerConfig")
 	proto.RegisterType((*OutboundConfig)(nil), "v2ray.core.app.proxyman.OutboundConfig")
 	proto.RegisterType((*SenderConfig)(nil), "v2ray.core.app.proxyman.SenderConfig")
+	proto.RegisterType((*MultiplexingConfig)(nil), "v2ray.core.app.proxyman.MultiplexingConfig")
 	proto.RegisterType((*OutboundHandlerConfig)(nil), "v2ray.core.app.proxyman.OutboundHandlerConfig")
 	proto.RegisterEnum("v2ray.core.app.proxyman.AllocationStrategy_Type", AllocationStrategy_Type_name, AllocationStrategy_Type_value)
 }
@@ -327,52 +408,60 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/app/proxyman/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 737 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x55, 0xd1, 0x6e, 0xdb, 0x36,
-	0x14, 0x9d, 0xec, 0xc4, 0x49, 0xae, 0x13, 0xc7, 0xe3, 0xb2, 0xc4, 0xf3, 0x36, 0xc0, 0x33, 0x86,
-	0xcd, 0xd8, 0x06, 0x29, 0x73, 0x30, 0x60, 0x7b, 0xda, 0x12, 0x27, 0xc0, 0xf2, 0x90, 0xd9, 0xa3,
-	0x83, 0x3e, 0x14, 0x05, 0x04, 0x46, 0x62, 0x5c, 0xa1, 0x12, 0x29, 0x90, 0xb4, 0x13, 0xfd, 0x52,
-	0xbf, 0xa1, 0x0f, 0xfd, 0x80, 0x7e, 0x4a, 0xff, 0xa0, 0x2f, 0x05, 0x49, 0xc9, 0x36, 0x62, 0xbb,
-	0x69, 0x1a, 0xf4, 0x4d, 0x24, 0xcf, 0x39, 0xe4, 0x3d, 0x87, 0x97, 0x82, 0xce, 0xa4, 0x2b, 0x48,
-	0xe6, 0x06, 0x3c, 0xf1, 0x02, 0x2e, 0xa8, 0x47, 0xd2, 0xd4, 0x4b, 0x05, 0xbf, 0xcd, 0x12, 0xc2,
-	0xbc, 0x80, 0xb3, 0xeb, 0x68, 0xe4, 0xa6, 0x82, 0x2b, 0x8e, 0x0e, 0x0a, 0xa4, 0xa0, 0x2e, 0x49,
-	0x53, 0xb7, 0x40, 0x35, 0x0f, 0xef, 0x48, 0x04, 0x3c, 0x49, 0x38, 0xf3, 0x24, 0x15, 0x11, 0x89,
-	0x3d, 0x95, 0xa5, 0x34, 0xf4, 0x13, 0x2a, 0x25, 0x19, 0x51, 0x2b, 0xd5, 0xfc, 0x79, 0x39, 0x83,
-	0x51, 0xe5, 0x91, 0x30, 0x14, 0x54, 0xca, 0x1c, 0xf8, 0xe3, 0x6a, 0x60, 0xca, 0x85, 0xca, 0x51,
-	0xee, 0x1d, 0x94, 0x12, 0x84, 0x49, 0xbd, 0xee, 0x45, 0x4c, 0x51, 0xa1, 0xd1, 0xf3, 0x95, 0xb4,
-	0x77, 0x61, 0xe7, 0x9c, 0x5d, 0xf1, 0x31, 0x0b, 0x7b, 0x66, 0xba, 0xfd, 0xba, 0x0c, 0xe8, 0x38,
-	0x8e, 0x79, 0x40, 0x54, 0xc4, 0xd9, 0x50, 0x09, 0xa2, 0xe8, 0x28, 0x43, 0xa7, 0xb0, 0xa6, 0x4f,
-	0xdf, 0x70, 0x5a, 0x4e, 0xa7, 0xd6, 0x3d, 0x74, 0x57, 0x18, 0xe0, 0x2e, 0x52, 0xdd, 0xcb, 0x2c,
-	0xa5, 0xd8, 0xb0, 0xd1, 0x0b, 0xa8, 0x06, 0x9c, 0x05, 0x63, 0x21, 0x28, 0x0b, 0xb2, 0x46, 0xa9,
-	0xe5, 0x74, 0xaa, 0xdd, 0xf3, 0x87, 0x88, 0x2d, 0x4e, 0xf5, 0x66, 0x82, 0x78, 0x5e, 0x1d, 0xf9,
-	0xb0, 0x21, 0xe8, 0xb5, 0xa0, 0xf2, 0x79, 0xa3, 0x6c, 0x36, 0x3a, 0x7b, 0xdc, 0x46, 0xd8, 0x8a,
-	0xe1, 0x42, 0xb5, 0xf9, 0x07, 0x7c, 0xff, 0xc1, 0xe3, 0xa0, 0x3d, 0x58, 0x9f, 0x90, 0x78, 0x6c,
-	0x5d, 0xdb, 0xc1, 0x76, 0xd0, 0xfc, 0x1d, 0xbe, 0x59, 0x29, 0xbe, 0x9c, 0xd2, 0xfe, 0x0d, 0xd6,
-	0xb4, 0x8b, 0x08, 0xa0, 0x72, 0x1c, 0xdf, 0x90, 0x4c, 0xd6, 0xbf, 0xd0, 0xdf, 0x98, 0xb0, 0x90,
-	0x27, 0x75, 0x07, 0x6d, 0xc3, 0xe6, 0xd9, 0xad, 0x8e, 0x97, 0xc4, 0xf5, 0x52, 0xfb, 0x55, 0x19,
-	0x6a, 0x98, 0x06, 0x34, 0x9a, 0x50, 0x61, 0x53, 0x45, 0x7f, 0x03, 0xe8, 0x4b, 0xe0, 0x0b, 0xc2,
-	0x46, 0x56, 0xbb, 0xda, 0x6d, 0xcd, 0xdb, 0x61, 0x6f, 0x93, 0xcb, 0xa8, 0x72, 0x07, 0x5c, 0x28,
-	0xac, 0x71, 0x78, 0x2b, 0x2d, 0x3e, 0xd1, 0x5f, 0x50, 0x89, 0x23, 0xa9, 0x28, 0xcb, 0x43, 0xfb,
-	0x61, 0x05, 0xf9, 0x7c, 0xd0, 0x17, 0xa7, 0x3c, 0x21, 0x11, 0xc3, 0x39, 0x01, 0x3d, 0x83, 0xaf,
-	0xc8, 0xb4, 0x5e, 0x5f, 0xe6, 0x05, 0xe7, 0x99, 0xfc, 0xfa, 0x80, 0x4c, 0x30, 0x22, 0x8b, 0x17,
-	0xf3, 0x12, 0x76, 0xa5, 0x12, 0x94, 0x24, 0xbe, 0xa4, 0x4a, 0x45, 0x6c, 0x24, 0x1b, 0x6b, 0x8b,
-	0xca, 0xd3, 0x36, 0x70, 0x8b, 0x36, 0x70, 0x87, 0x86, 0x65, 0xfd, 0xc1, 0x35, 0xab, 0x31, 0xcc,
-	0x25, 0xd0, 0x3f, 0xf0, 0x9d, 0xb0, 0x0e, 0xfa, 0x5c, 0x44, 0xa3, 0x88, 0x91, 0xd8, 0x0f, 0xa9,
-	0x54, 0x11, 0x33, 0xbb, 0x37, 0xd6, 0x5b, 0x4e, 0x67, 0x13, 0x37, 0x73, 0x4c, 0x3f, 0x87, 0x9c,
-	0xce, 0x10, 0xe8, 0x4f, 0x68, 0xe8, 0xd3, 0xde, 0xf8, 0x29, 0x91, 0x52, 0xeb, 0x04, 0x9c, 0x31,
-	0x1a, 0x18, 0x76, 0xc5, 0xb0, 0xf7, 0xcd, 0xfa, 0xc0, 0x2e, 0xf7, 0xa6, 0xab, 0xed, 0x37, 0x0e,
-	0xec, 0xe5, 0x3d, 0xf9, 0x2f, 0x61, 0x61, 0x3c, 0x0d, 0xb1, 0x0e, 0x65, 0x45, 0x46, 0x26, 0xbd,
-	0x2d, 0xac, 0x3f, 0xd1, 0x10, 0xbe, 0xcc, 0x8f, 0x20, 0x66, 0xe5, 0xdb, 0x80, 0x7e, 0x5a, 0x12,
-	0x90, 0x7d, 0x86, 0x4c, 0x43, 0x86, 0x17, 0xf6, 0x15, 0xc2, 0xf5, 0x42, 0x60, 0x5a, 0xfb, 0x05,
-	0xd4, 0x4c, 0x08, 0x33, 0xc5, 0xf2, 0x83, 0x14, 0x77, 0x0c, 0xbb, 0x90, 0x6b, 0xd7, 0xa1, 0xd6,
-	0x1f, 0xab, 0xf9, 0x27, 0xe6, 0xad, 0x03, 0xdb, 0x43, 0xca, 0xc2, 0x69, 0x61, 0x47, 0x50, 0x9e,
-	0x44, 0x24, 0xbf, 0x96, 0x1f, 0x71, 0xb3, 0x34, 0x7a, 0x59, 0xf0, 0xa5, 0xc7, 0x07, 0xff, 0xff,
-	0x8a, 0xe2, 0x7f, 0xb9, 0x47, 0x74, 0xa0, 0x49, 0xb9, 0xe6, 0x1d, 0x03, 0xde, 0x39, 0xf0, 0x75,
-	0xe1, 0xc0, 0x7d, 0x81, 0xf6, 0x61, 0x57, 0x1a, 0x67, 0x3e, 0x35, 0xce, 0x9a, 0xa5, 0x7f, 0xa6,
-	0x30, 0xd1, 0x3e, 0x54, 0xe8, 0x6d, 0x1a, 0x09, 0x6a, 0x9a, 0xac, 0x8c, 0xf3, 0x11, 0x6a, 0xc0,
-	0x86, 0x16, 0xa1, 0x4c, 0x99, 0xd6, 0xd8, 0xc2, 0xc5, 0xf0, 0xe4, 0x3f, 0xf8, 0x36, 0xe0, 0xc9,
-	0xaa, 0x2e, 0x3f, 0xa9, 0x5a, 0x2b, 0x06, 0xfa, 0x67, 0xf4, 0x74, 0xb3, 0x98, 0x7e, 0x59, 0x3a,
-	0x78, 0xd2, 0xc5, 0x24, 0x73, 0x7b, 0x9a, 0x70, 0x9c, 0xa6, 0xd6, 0xdf, 0x84, 0xb0, 0xab, 0x8a,
-	0xf9, 0x6f, 0x1d, 0xbd, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x26, 0xa3, 0xe8, 0x2c, 0xad, 0x07, 0x00,
-	0x00,
+	// 866 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x56, 0xd1, 0x6e, 0x23, 0x35,
+	0x14, 0x25, 0x49, 0xb7, 0x4d, 0x6e, 0xb7, 0x69, 0xea, 0x2d, 0xbb, 0x43, 0x60, 0xa5, 0x10, 0x21,
+	0x36, 0x02, 0x34, 0xd9, 0xcd, 0x0a, 0x09, 0x9e, 0xa0, 0xdb, 0xae, 0x44, 0x1f, 0x4a, 0x82, 0x53,
+	0xf1, 0xb0, 0x42, 0x1a, 0xb9, 0x33, 0xb7, 0xc1, 0x62, 0xc6, 0x1e, 0xd9, 0x4e, 0xb6, 0x23, 0xf1,
+	0x15, 0x7c, 0x06, 0x5f, 0xc1, 0x23, 0x0f, 0xfc, 0x11, 0x2f, 0x68, 0xec, 0x99, 0x49, 0xba, 0x69,
+	0xba, 0x94, 0x15, 0x6f, 0x63, 0xfb, 0x9c, 0x63, 0xdf, 0x73, 0x8f, 0x9d, 0xc0, 0x60, 0x31, 0x52,
+	0x2c, 0xf3, 0x43, 0x99, 0x0c, 0x43, 0xa9, 0x70, 0xc8, 0xd2, 0x74, 0x98, 0x2a, 0x79, 0x95, 0x25,
+	0x4c, 0x0c, 0x43, 0x29, 0x2e, 0xf9, 0xcc, 0x4f, 0x95, 0x34, 0x92, 0x3c, 0x2a, 0x91, 0x0a, 0x7d,
+	0x96, 0xa6, 0x7e, 0x89, 0xea, 0x3e, 0x7d, 0x43, 0x22, 0x94, 0x49, 0x22, 0xc5, 0x50, 0xa3, 0xe2,
+	0x2c, 0x1e, 0x9a, 0x2c, 0xc5, 0x28, 0x48, 0x50, 0x6b, 0x36, 0x43, 0x27, 0xd5, 0x7d, 0x72, 0x33,
+	0x43, 0xa0, 0x19, 0xb2, 0x28, 0x52, 0xa8, 0x75, 0x01, 0xfc, 0x64, 0x33, 0x30, 0x95, 0xca, 0x14,
+	0x28, 0xff, 0x0d, 0x94, 0x51, 0x4c, 0xe8, 0x7c, 0x7d, 0xc8, 0x85, 0x41, 0x95, 0xa3, 0x57, 0x2b,
+	0xe9, 0xef, 0xc3, 0xde, 0xa9, 0xb8, 0x90, 0x73, 0x11, 0x1d, 0xdb, 0xe9, 0xfe, 0x1f, 0x0d, 0x20,
+	0x47, 0x71, 0x2c, 0x43, 0x66, 0xb8, 0x14, 0x53, 0xa3, 0x98, 0xc1, 0x59, 0x46, 0x4e, 0x60, 0x2b,
+	0x3f, 0xbd, 0x57, 0xeb, 0xd5, 0x06, 0xed, 0xd1, 0x53, 0x7f, 0x83, 0x01, 0xfe, 0x3a, 0xd5, 0x3f,
+	0xcf, 0x52, 0xa4, 0x96, 0x4d, 0x7e, 0x81, 0xdd, 0x50, 0x8a, 0x70, 0xae, 0x14, 0x8a, 0x30, 0xf3,
+	0xea, 0xbd, 0xda, 0x60, 0x77, 0x74, 0x7a, 0x17, 0xb1, 0xf5, 0xa9, 0xe3, 0xa5, 0x20, 0x5d, 0x55,
+	0x27, 0x01, 0xec, 0x28, 0xbc, 0x54, 0xa8, 0x7f, 0xf6, 0x1a, 0x76, 0xa3, 0x97, 0xef, 0xb6, 0x11,
+	0x75, 0x62, 0xb4, 0x54, 0xed, 0x7e, 0x09, 0x8f, 0x6f, 0x3d, 0x0e, 0x39, 0x84, 0x7b, 0x0b, 0x16,
+	0xcf, 0x9d, 0x6b, 0x7b, 0xd4, 0x0d, 0xba, 0xcf, 0xe0, 0x83, 0x8d, 0xe2, 0x37, 0x53, 0xfa, 0x5f,
+	0xc0, 0x56, 0xee, 0x22, 0x01, 0xd8, 0x3e, 0x8a, 0x5f, 0xb3, 0x4c, 0x77, 0xde, 0xcb, 0xbf, 0x29,
+	0x13, 0x91, 0x4c, 0x3a, 0x35, 0x72, 0x1f, 0x9a, 0x2f, 0xaf, 0xf2, 0xf6, 0xb2, 0xb8, 0x53, 0xef,
+	0xff, 0xb6, 0x05, 0x6d, 0x8a, 0x21, 0xf2, 0x05, 0x2a, 0xd7, 0x55, 0xf2, 0x0d, 0x40, 0x1e, 0x82,
+	0x40, 0x31, 0x31, 0x73, 0xda, 0xbb, 0xa3, 0xde, 0xaa, 0x1d, 0x2e, 0x4d, 0xbe, 0x40, 0xe3, 0x4f,
+	0xa4, 0x32, 0x34, 0xc7, 0xd1, 0x56, 0x5a, 0x7e, 0x92, 0xaf, 0x61, 0x3b, 0xe6, 0xda, 0xa0, 0x28,
+	0x9a, 0xf6, 0xf1, 0x06, 0xf2, 0xe9, 0x64, 0xac, 0x4e, 0x64, 0xc2, 0xb8, 0xa0, 0x05, 0x81, 0xfc,
+	0x04, 0x0f, 0x58, 0x55, 0x6f, 0xa0, 0x8b, 0x82, 0x8b, 0x9e, 0x7c, 0x7e, 0x87, 0x9e, 0x50, 0xc2,
+	0xd6, 0x83, 0x79, 0x0e, 0xfb, 0xda, 0x28, 0x64, 0x49, 0xa0, 0xd1, 0x18, 0x2e, 0x66, 0xda, 0xdb,
+	0x5a, 0x57, 0xae, 0xae, 0x81, 0x5f, 0x5e, 0x03, 0x7f, 0x6a, 0x59, 0xce, 0x1f, 0xda, 0x76, 0x1a,
+	0xd3, 0x42, 0x82, 0x7c, 0x0b, 0x1f, 0x29, 0xe7, 0x60, 0x20, 0x15, 0x9f, 0x71, 0xc1, 0xe2, 0x20,
+	0x42, 0x6d, 0xb8, 0xb0, 0xbb, 0x7b, 0xf7, 0x7a, 0xb5, 0x41, 0x93, 0x76, 0x0b, 0xcc, 0xb8, 0x80,
+	0x9c, 0x2c, 0x11, 0xe4, 0x2b, 0xf0, 0xf2, 0xd3, 0xbe, 0x0e, 0x52, 0xa6, 0x75, 0xae, 0x13, 0x4a,
+	0x21, 0x30, 0xb4, 0xec, 0x6d, 0xcb, 0x7e, 0x68, 0xd7, 0x27, 0x6e, 0xf9, 0xb8, 0x5a, 0x25, 0xe7,
+	0x70, 0xa0, 0x05, 0xbf, 0xbc, 0xe4, 0x62, 0xb6, 0xac, 0x69, 0xc7, 0xd6, 0xf4, 0x64, 0xa3, 0x5b,
+	0xd3, 0x82, 0x51, 0xd4, 0xd3, 0x29, 0x15, 0xca, 0x8a, 0xfa, 0xbf, 0x42, 0xfb, 0x3a, 0x86, 0x78,
+	0xb0, 0x83, 0x82, 0x5d, 0xc4, 0x18, 0xd9, 0x40, 0x34, 0x69, 0x39, 0x24, 0xcf, 0xe0, 0x70, 0xa5,
+	0xd8, 0x40, 0x2e, 0x50, 0x29, 0x1e, 0xa1, 0x57, 0xef, 0x35, 0x06, 0x2d, 0xfa, 0x60, 0x65, 0x6d,
+	0x5c, 0x2c, 0x91, 0xc7, 0x00, 0x4a, 0xce, 0x0d, 0x06, 0x52, 0xc4, 0xae, 0xb7, 0x4d, 0xda, 0xb2,
+	0x33, 0x63, 0x11, 0x67, 0xfd, 0xbf, 0x6a, 0x70, 0x58, 0xbc, 0x33, 0xdf, 0x31, 0x11, 0xc5, 0x55,
+	0x30, 0x3b, 0xd0, 0x30, 0x6c, 0x66, 0x0f, 0xd0, 0xa2, 0xf9, 0x27, 0x99, 0xc2, 0x41, 0x61, 0xab,
+	0x5a, 0x96, 0xef, 0x42, 0xf7, 0xe9, 0x0d, 0xa1, 0x73, 0x4f, 0xab, 0x7d, 0x64, 0xa2, 0x33, 0xf7,
+	0xb2, 0xd2, 0x4e, 0x29, 0x50, 0xf5, 0xf3, 0x0c, 0xda, 0xd6, 0xaa, 0xa5, 0x62, 0xe3, 0x4e, 0x8a,
+	0x7b, 0x96, 0x5d, 0x99, 0xd9, 0x81, 0xf6, 0x78, 0x6e, 0x56, 0x9f, 0xcd, 0x3f, 0xeb, 0x70, 0x7f,
+	0x8a, 0x22, 0xaa, 0x0a, 0x7b, 0x0e, 0x8d, 0x05, 0x67, 0x5e, 0xed, 0xdf, 0xde, 0x96, 0x1c, 0x7d,
+	0x53, 0x98, 0xeb, 0xef, 0x1e, 0xe6, 0x1f, 0x36, 0x14, 0xff, 0xd9, 0x5b, 0x44, 0x27, 0x39, 0xa9,
+	0xd0, 0xbc, 0x6e, 0x00, 0x79, 0x05, 0x24, 0x99, 0xc7, 0x86, 0xa7, 0x31, 0x5e, 0xdd, 0x7a, 0xf1,
+	0xae, 0x85, 0xf4, 0xac, 0xa4, 0x2c, 0x83, 0x7a, 0x50, 0xc9, 0x54, 0xe6, 0x4e, 0x80, 0xac, 0x03,
+	0x6f, 0x49, 0x6b, 0x6f, 0xfd, 0x47, 0x65, 0xef, 0xda, 0x2f, 0x41, 0xff, 0xef, 0x1a, 0xbc, 0x5f,
+	0xf6, 0xeb, 0x6d, 0xf1, 0x1b, 0xc3, 0xbe, 0xb6, 0x7d, 0xfc, 0xaf, 0xe1, 0x6b, 0x3b, 0xfa, 0xff,
+	0x14, 0x3d, 0xf2, 0x10, 0xb6, 0xf1, 0x2a, 0xe5, 0x0a, 0xad, 0xdb, 0x0d, 0x5a, 0x8c, 0x72, 0x7f,
+	0x72, 0x11, 0x14, 0xc6, 0x3e, 0x4e, 0x2d, 0x5a, 0x0e, 0x5f, 0x7c, 0x0f, 0x1f, 0x86, 0x32, 0xd9,
+	0xd4, 0x94, 0x17, 0xbb, 0xce, 0x8a, 0x89, 0x92, 0x46, 0xbe, 0x6a, 0x96, 0xd3, 0xbf, 0xd7, 0x1f,
+	0xfd, 0x38, 0xa2, 0x2c, 0xf3, 0x8f, 0x73, 0xc2, 0x51, 0x9a, 0xba, 0x34, 0x24, 0x4c, 0x5c, 0x6c,
+	0xdb, 0x7f, 0x0e, 0xcf, 0xff, 0x09, 0x00, 0x00, 0xff, 0xff, 0x82, 0x99, 0xcf, 0x0d, 0x2f, 0x09,
+	0x00, 0x00,
 }
@@ -0,0 +1,25 @@
+package proxyman
+
+import (
+	"context"
+)
+
+type key int
+
+const (
+	sniffingConfigKey key = iota
+)
+
+// ContextWithSniffingConfig returns a new context that carries the sniffing settings of the inbound handler.
+func ContextWithSniffingConfig(ctx context.Context, config *SniffingConfig) context.Context {
+	return context.WithValue(ctx, sniffingConfigKey, config)
+}
+
+// SniffingConfigFromContext returns the sniffing settings in the given context, or nil if there is none.
+func SniffingConfigFromContext(ctx context.Context) *SniffingConfig {
+	config, ok := ctx.Value(sniffingConfigKey).(*SniffingConfig)
+	if !ok {
+		return nil
+	}
+	return config
+}
@@ -5,8 +5,10 @@ import (
 
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/proxy"
 )
 
@@ -42,6 +44,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 				recvOrigDest:     receiverConfig.ReceiveOriginalDestination,
 				tag:              tag,
 				allowPassiveConn: receiverConfig.AllowPassiveConnection,
+				sniffing:         receiverConfig.SniffingSettings,
 			}
 			h.workers = append(h.workers, worker)
 		}
@@ -80,3 +83,21 @@ func (h *AlwaysOnInboundHandler) GetRandomInboundProxy() (proxy.Inbound, net.Por
 	w := h.workers[dice.Roll(len(h.workers))]
 	return w.Proxy(), w.Port(), 9999
 }
+
+// AddUser implements proxy.UserManager. It fails if the underlying proxy doesn't support user management.
+func (h *AlwaysOnInboundHandler) AddUser(ctx context.Context, user *protocol.User) error {
+	um, ok := h.proxy.(proxy.UserManager)
+	if !ok {
+		return errors.New("Proxyman|DefaultInboundHandler: Proxy doesn't support user management.")
+	}
+	return um.AddUser(ctx, user)
+}
+
+// RemoveUser implements proxy.UserManager. It fails if the underlying proxy doesn't support user management.
+func (h *AlwaysOnInboundHandler) RemoveUser(ctx context.Context, email string) error {
+	um, ok := h.proxy.(proxy.UserManager)
+	if !ok {
+		return errors.New("Proxyman|DefaultInboundHandler: Proxy doesn't support user management.")
+	}
+	return um.RemoveUser(ctx, email)
+}
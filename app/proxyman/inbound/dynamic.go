@@ -15,6 +15,7 @@ import (
 type DynamicInboundHandler struct {
 	sync.Mutex
 	tag            string
+	parentCtx      context.Context
 	ctx            context.Context
 	cancel         context.CancelFunc
 	proxyConfig    interface{}
@@ -23,14 +24,13 @@ type DynamicInboundHandler struct {
 	worker         []worker
 	worker2Recycle []worker
 	lastRefresh    time.Time
+	closed         bool
 }
 
 func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*DynamicInboundHandler, error) {
-	ctx, cancel := context.WithCancel(ctx)
 	h := &DynamicInboundHandler{
-		ctx:            ctx,
+		parentCtx:      ctx,
 		tag:            tag,
-		cancel:         cancel,
 		proxyConfig:    proxyConfig,
 		receiverConfig: receiverConfig,
 		portsInUse:     make(map[v2net.Port]bool),
@@ -56,11 +56,22 @@ func (h *DynamicInboundHandler) allocatePort() v2net.Port {
 	}
 }
 
+// refresh recycles the workers of the last refresh, and starts new workers on newly allocated ports. It does nothing
+// if the handler is closed.
 func (h *DynamicInboundHandler) refresh() error {
+	h.Lock()
+	if h.closed {
+		h.Unlock()
+		return nil
+	}
 	h.lastRefresh = time.Now()
+	ctx := h.ctx
+	workers2Close := h.worker2Recycle
+	h.worker2Recycle, h.worker = h.worker, nil
+	h.Unlock()
 
 	ports2Del := make([]v2net.Port, 0, 16)
-	for _, worker := range h.worker2Recycle {
+	for _, worker := range workers2Close {
 		worker.Close()
 		ports2Del = append(ports2Del, worker.Port())
 	}
@@ -71,7 +82,22 @@ func (h *DynamicInboundHandler) refresh() error {
 	}
 	h.Unlock()
 
-	h.worker2Recycle, h.worker = h.worker, h.worker2Recycle[:0]
+	var workers []worker
+	defer func() {
+		h.Lock()
+		closed := h.closed
+		if !closed {
+			h.worker = workers
+		}
+		h.Unlock()
+
+		// Workers started while the handler is being closed are not seen by Close.
+		if closed {
+			for _, worker := range workers {
+				worker.Close()
+			}
+		}
+	}()
 
 	address := h.receiverConfig.Listen.AsAddress()
 	if address == nil {
@@ -79,7 +105,7 @@ func (h *DynamicInboundHandler) refresh() error {
 	}
 	for i := uint32(0); i < h.receiverConfig.AllocationStrategy.GetConcurrencyValue(); i++ {
 		port := h.allocatePort()
-		p, err := proxy.CreateInboundHandler(h.ctx, h.proxyConfig)
+		p, err := proxy.CreateInboundHandler(ctx, h.proxyConfig)
 		if err != nil {
 			log.Warning("Proxyman|DefaultInboundHandler: Failed to create proxy instance: ", err)
 			continue
@@ -94,11 +120,12 @@ func (h *DynamicInboundHandler) refresh() error {
 				stream:           h.receiverConfig.StreamSettings,
 				recvOrigDest:     h.receiverConfig.ReceiveOriginalDestination,
 				allowPassiveConn: h.receiverConfig.AllowPassiveConnection,
+				sniffing:         h.receiverConfig.SniffingSettings,
 			}
 			if err := worker.Start(); err != nil {
 				return err
 			}
-			h.worker = append(h.worker, worker)
+			workers = append(workers, worker)
 		}
 
 		if nl.HasNetwork(v2net.Network_UDP) {
@@ -112,17 +139,17 @@ func (h *DynamicInboundHandler) refresh() error {
 			if err := worker.Start(); err != nil {
 				return err
 			}
-			h.worker = append(h.worker, worker)
+			workers = append(workers, worker)
 		}
 	}
 
 	return nil
 }
 
-func (h *DynamicInboundHandler) monitor() {
+func (h *DynamicInboundHandler) monitor(ctx context.Context) {
 	for {
 		select {
-		case <-h.ctx.Done():
+		case <-ctx.Done():
 			return
 		case <-time.After(time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue())):
 			h.refresh()
@@ -130,17 +157,41 @@ func (h *DynamicInboundHandler) monitor() {
 	}
 }
 
+// Start starts listening on newly allocated ports, which are reallocated periodically. The handler is able to start
+// again after it is closed.
 func (h *DynamicInboundHandler) Start() error {
+	h.Lock()
+	h.closed = false
+	h.ctx, h.cancel = context.WithCancel(h.parentCtx)
+	ctx := h.ctx
+	h.Unlock()
+
 	err := h.refresh()
-	go h.monitor()
+	go h.monitor(ctx)
 	return err
 }
 
 func (h *DynamicInboundHandler) Close() {
-	h.cancel()
+	h.Lock()
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.closed = true
+	workers := make([]worker, 0, len(h.worker)+len(h.worker2Recycle))
+	workers = append(workers, h.worker...)
+	workers = append(workers, h.worker2Recycle...)
+	h.worker, h.worker2Recycle = nil, nil
+	h.Unlock()
+
+	for _, worker := range workers {
+		worker.Close()
+	}
 }
 
 func (h *DynamicInboundHandler) GetRandomInboundProxy() (proxy.Inbound, v2net.Port, int) {
+	h.Lock()
+	defer h.Unlock()
+
 	w := h.worker[dice.Roll(len(h.worker))]
 	expire := h.receiverConfig.AllocationStrategy.GetRefreshValue() - uint32(time.Since(h.lastRefresh)/time.Minute)
 	return w.Proxy(), w.Port(), int(expire)
@@ -2,14 +2,19 @@ package inbound
 
 import (
 	"context"
+	"sync"
 
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
 )
 
 type DefaultInboundHandlerManager struct {
+	sync.RWMutex
+	running        bool
 	handlers       []proxyman.InboundHandler
+	configs        []*proxyman.InboundHandlerConfig
 	taggedHandlers map[string]proxyman.InboundHandler
 }
 
@@ -19,41 +24,55 @@ func New(ctx context.Context, config *proxyman.InboundConfig) (*DefaultInboundHa
 	}, nil
 }
 
-func (m *DefaultInboundHandlerManager) AddHandler(ctx context.Context, config *proxyman.InboundHandlerConfig) error {
+func newHandler(ctx context.Context, config *proxyman.InboundHandlerConfig) (proxyman.InboundHandler, error) {
 	rawReceiverSettings, err := config.ReceiverSettings.GetInstance()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	receiverSettings, ok := rawReceiverSettings.(*proxyman.ReceiverConfig)
 	if !ok {
-		return errors.New("Proxyman|DefaultInboundHandlerManager: Not a ReceiverConfig.")
+		return nil, errors.New("Proxyman|DefaultInboundHandlerManager: Not a ReceiverConfig.")
 	}
 	proxySettings, err := config.ProxySettings.GetInstance()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var handler proxyman.InboundHandler
 	tag := config.Tag
+
 	allocStrategy := receiverSettings.AllocationStrategy
 	if allocStrategy == nil || allocStrategy.Type == proxyman.AllocationStrategy_Always {
-		h, err := NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
-		if err != nil {
-			return err
-		}
-		handler = h
-	} else if allocStrategy.Type == proxyman.AllocationStrategy_Random {
-		h, err := NewDynamicInboundHandler(ctx, tag, receiverSettings, proxySettings)
-		if err != nil {
-			return err
-		}
-		handler = h
+		return NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
+	}
+	if allocStrategy.Type == proxyman.AllocationStrategy_Random {
+		return NewDynamicInboundHandler(ctx, tag, receiverSettings, proxySettings)
+	}
+	return nil, errors.New("Proxyman|DefaultInboundHandlerManager: Unknown allocation strategy: ", receiverSettings.AllocationStrategy.Type)
+}
+
+func (m *DefaultInboundHandlerManager) AddHandler(ctx context.Context, config *proxyman.InboundHandlerConfig) error {
+	tag := config.Tag
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, found := m.taggedHandlers[tag]; found && len(tag) > 0 {
+		return errors.New("Proxyman|DefaultInboundHandlerManager: Handler already exists: ", tag)
+	}
+
+	handler, err := newHandler(ctx, config)
+	if err != nil {
+		return err
 	}
 
-	if handler == nil {
-		return errors.New("Proxyman|DefaultInboundHandlerManager: Unknown allocation strategy: ", receiverSettings.AllocationStrategy.Type)
+	if m.running {
+		if err := handler.Start(); err != nil {
+			handler.Close()
+			return err
+		}
 	}
 
 	m.handlers = append(m.handlers, handler)
+	m.configs = append(m.configs, config)
 	if len(tag) > 0 {
 		m.taggedHandlers[tag] = handler
 	}
@@ -61,6 +80,9 @@ func (m *DefaultInboundHandlerManager) AddHandler(ctx context.Context, config *p
 }
 
 func (m *DefaultInboundHandlerManager) GetHandler(ctx context.Context, tag string) (proxyman.InboundHandler, error) {
+	m.RLock()
+	defer m.RUnlock()
+
 	handler, found := m.taggedHandlers[tag]
 	if !found {
 		return nil, errors.New("Proxymand|DefaultInboundHandlerManager: Handler not found: ", tag)
@@ -68,16 +90,134 @@ func (m *DefaultInboundHandlerManager) GetHandler(ctx context.Context, tag strin
 	return handler, nil
 }
 
+func (m *DefaultInboundHandlerManager) RemoveHandler(ctx context.Context, tag string) error {
+	if len(tag) == 0 {
+		return errors.New("Proxyman|DefaultInboundHandlerManager: Empty tag.")
+	}
+
+	m.Lock()
+	handler, found := m.taggedHandlers[tag]
+	if !found {
+		m.Unlock()
+		return errors.New("Proxyman|DefaultInboundHandlerManager: Handler not found: ", tag)
+	}
+	delete(m.taggedHandlers, tag)
+	for idx, h := range m.handlers {
+		if h == handler {
+			m.handlers = append(m.handlers[:idx], m.handlers[idx+1:]...)
+			m.configs = append(m.configs[:idx], m.configs[idx+1:]...)
+			break
+		}
+	}
+	m.Unlock()
+
+	handler.Close()
+	return nil
+}
+
+// ReplaceHandler starts a new handler in place of the one that has the same tag as the given config. The new handler
+// is started before the old one is closed. If it fails to start, for example because it listens on the same ports as
+// the old one, the old handler is closed and the new one is started again. If the new handler still fails, the old
+// handler is restarted and the error is returned. The tag is removed from this manager only if neither of them
+// starts.
+func (m *DefaultInboundHandlerManager) ReplaceHandler(ctx context.Context, config *proxyman.InboundHandlerConfig) error {
+	tag := config.Tag
+	if len(tag) == 0 {
+		return errors.New("Proxyman|DefaultInboundHandlerManager: Empty tag.")
+	}
+
+	handler, err := newHandler(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	oldHandler, found := m.taggedHandlers[tag]
+	if !found {
+		return errors.New("Proxyman|DefaultInboundHandlerManager: Handler not found: ", tag)
+	}
+	idx := 0
+	for m.handlers[idx] != oldHandler {
+		idx++
+	}
+
+	if !m.running {
+		oldHandler.Close()
+	} else if err := startReplacement(handler, oldHandler); err != nil {
+		if oldErr := oldHandler.Start(); oldErr != nil {
+			oldHandler.Close()
+			log.Warning("Proxyman|DefaultInboundHandlerManager: Failed to restart handler ", tag, ": ", oldErr)
+			delete(m.taggedHandlers, tag)
+			m.handlers = append(m.handlers[:idx], m.handlers[idx+1:]...)
+			m.configs = append(m.configs[:idx], m.configs[idx+1:]...)
+		}
+		return err
+	}
+
+	m.taggedHandlers[tag] = handler
+	m.handlers[idx] = handler
+	m.configs[idx] = config
+	return nil
+}
+
+// startReplacement starts the given handler and closes the old one. If the handler fails to start while the old one
+// is running, it is started again after the old one is closed. The old handler is closed when an error is returned.
+func startReplacement(handler proxyman.InboundHandler, oldHandler proxyman.InboundHandler) error {
+	if err := handler.Start(); err == nil {
+		oldHandler.Close()
+		return nil
+	}
+	handler.Close()
+	oldHandler.Close()
+	if err := handler.Start(); err != nil {
+		handler.Close()
+		return err
+	}
+	return nil
+}
+
+func (m *DefaultInboundHandlerManager) ListHandlers(ctx context.Context) []*proxyman.InboundHandlerConfig {
+	m.RLock()
+	defer m.RUnlock()
+
+	configs := make([]*proxyman.InboundHandlerConfig, len(m.configs))
+	copy(configs, m.configs)
+	return configs
+}
+
+func (m *DefaultInboundHandlerManager) ListTags(ctx context.Context) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	tags := make([]string, 0, len(m.taggedHandlers))
+	for _, config := range m.configs {
+		if len(config.Tag) > 0 {
+			tags = append(tags, config.Tag)
+		}
+	}
+	return tags
+}
+
 func (m *DefaultInboundHandlerManager) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
 	for _, handler := range m.handlers {
 		if err := handler.Start(); err != nil {
 			return err
 		}
 	}
+	m.running = true
 	return nil
 }
 
 func (m *DefaultInboundHandlerManager) Close() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.running = false
 	for _, handler := range m.handlers {
 		handler.Close()
 	}
@@ -8,6 +8,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common/buf"
 	v2net "v2ray.com/core/common/net"
 	"v2ray.com/core/proxy"
@@ -24,6 +25,8 @@ type worker interface {
 }
 
 type tcpWorker struct {
+	sync.Mutex
+
 	address          v2net.Address
 	port             v2net.Port
 	proxy            proxy.Inbound
@@ -31,13 +34,40 @@ type tcpWorker struct {
 	recvOrigDest     bool
 	tag              string
 	allowPassiveConn bool
+	sniffing         *proxyman.SniffingConfig
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	hub        *internet.TCPHub
+	activeConn map[internet.Connection]bool
+}
+
+func (w *tcpWorker) addConn(conn internet.Connection) bool {
+	w.Lock()
+	defer w.Unlock()
 
-	ctx    context.Context
-	cancel context.CancelFunc
-	hub    *internet.TCPHub
+	if w.activeConn == nil {
+		return false
+	}
+	w.activeConn[conn] = true
+	return true
+}
+
+func (w *tcpWorker) removeConn(conn internet.Connection) {
+	w.Lock()
+	if w.activeConn != nil {
+		delete(w.activeConn, conn)
+	}
+	w.Unlock()
 }
 
 func (w *tcpWorker) callback(conn internet.Connection) {
+	if !w.addConn(conn) {
+		conn.Close()
+		return
+	}
+	defer w.removeConn(conn)
+
 	ctx, cancel := context.WithCancel(w.ctx)
 	if w.recvOrigDest {
 		dest := tcp.GetOriginalDestination(conn)
@@ -49,6 +79,9 @@ func (w *tcpWorker) callback(conn internet.Connection) {
 		ctx = proxy.ContextWithInboundTag(ctx, w.tag)
 	}
 	ctx = proxy.ContextWithAllowPassiveConnection(ctx, w.allowPassiveConn)
+	if w.sniffing != nil && w.sniffing.Enabled {
+		ctx = proxyman.ContextWithSniffingConfig(ctx, w.sniffing)
+	}
 	ctx = proxy.ContextWithInboundDestination(ctx, v2net.TCPDestination(w.address, w.port))
 	w.proxy.Process(ctx, v2net.Network_TCP, conn)
 	cancel()
@@ -63,6 +96,9 @@ func (w *tcpWorker) Start() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	w.ctx = ctx
 	w.cancel = cancel
+	w.Lock()
+	w.activeConn = make(map[internet.Connection]bool)
+	w.Unlock()
 	hub, err := internet.ListenTCP(w.address, w.port, w.callback, w.stream)
 	if err != nil {
 		return err
@@ -71,9 +107,19 @@ func (w *tcpWorker) Start() error {
 	return nil
 }
 
+// Close stops listening and closes all connections in progress.
 func (w *tcpWorker) Close() {
-	w.hub.Close()
-	w.cancel()
+	if w.hub != nil {
+		w.hub.Close()
+		w.cancel()
+	}
+
+	w.Lock()
+	for conn := range w.activeConn {
+		conn.Close()
+	}
+	w.activeConn = nil
+	w.Unlock()
 }
 
 func (w *tcpWorker) Port() v2net.Port {
@@ -86,6 +132,7 @@ type udpConn struct {
 	output           func([]byte) (int, error)
 	remote           net.Addr
 	local            net.Addr
+	done             <-chan struct{}
 	cancel           context.CancelFunc
 }
 
@@ -94,12 +141,16 @@ func (c *udpConn) updateActivity() {
 }
 
 func (c *udpConn) Read(buf []byte) (int, error) {
-	in, open := <-c.input
-	if !open {
+	select {
+	case in, open := <-c.input:
+		if !open {
+			return 0, io.EOF
+		}
+		c.updateActivity()
+		return copy(buf, in), nil
+	case <-c.done:
 		return 0, io.EOF
 	}
-	c.updateActivity()
-	return copy(buf, in), nil
 }
 
 func (c *udpConn) Write(buf []byte) (int, error) {
@@ -188,10 +239,12 @@ func (w *udpWorker) callback(b *buf.Buffer, source v2net.Destination, originalDe
 	conn.input <- b.Bytes()
 
 	if !existing {
+		ctx, cancel := context.WithCancel(w.ctx)
+		w.Lock()
+		conn.cancel = cancel
+		conn.done = ctx.Done()
+		w.Unlock()
 		go func() {
-			ctx := w.ctx
-			ctx, cancel := context.WithCancel(ctx)
-			conn.cancel = cancel
 			if originalDest.IsValid() {
 				ctx = proxy.ContextWithOriginalDestination(ctx, originalDest)
 			}
@@ -230,9 +283,21 @@ func (w *udpWorker) Start() error {
 	return nil
 }
 
+// Close stops listening and cancels all connections in progress.
 func (w *udpWorker) Close() {
-	w.hub.Close()
-	w.cancel()
+	if w.hub != nil {
+		w.hub.Close()
+		w.cancel()
+	}
+
+	w.Lock()
+	for addr, conn := range w.activeConn {
+		delete(w.activeConn, addr)
+		if conn.cancel != nil {
+			conn.cancel()
+		}
+	}
+	w.Unlock()
 }
 
 func (w *udpWorker) monitor() {
@@ -246,7 +311,9 @@ func (w *udpWorker) monitor() {
 			for addr, conn := range w.activeConn {
 				if nowSec-conn.lastActivityTime > 8 {
 					delete(w.activeConn, addr)
-					conn.cancel()
+					if conn.cancel != nil {
+						conn.cancel()
+					}
 				}
 			}
 			w.Unlock()
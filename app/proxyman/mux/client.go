@@ -0,0 +1,200 @@
+package mux
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/ray"
+)
+
+const (
+	defaultConcurrency = 8
+
+	// idleTimeout is how long a mux connection without sessions is kept open.
+	idleTimeout = time.Second * 16
+)
+
+// DispatchFunc sends a connection through an outbound proxy.
+type DispatchFunc func(ctx context.Context, link ray.OutboundRay)
+
+// ClientManager dispatches connections of an outbound handler over mux connections, creating mux connections as
+// needed.
+type ClientManager struct {
+	sync.Mutex
+	ctx         context.Context
+	dispatch    DispatchFunc
+	concurrency int
+	clients     []*Client
+	closed      bool
+}
+
+// NewClientManager creates a ClientManager that opens mux connections by the given dispatch function. ctx is the base
+// context of all mux connections.
+func NewClientManager(ctx context.Context, config *proxyman.MultiplexingConfig, dispatch DispatchFunc) *ClientManager {
+	concurrency := int(config.Concurrency)
+	if concurrency == 0 {
+		concurrency = defaultConcurrency
+	}
+	return &ClientManager{
+		ctx:         ctx,
+		dispatch:    dispatch,
+		concurrency: concurrency,
+	}
+}
+
+// Dispatch sends the connection in the given context over a mux connection, and returns after the connection finishes.
+func (m *ClientManager) Dispatch(ctx context.Context, link ray.OutboundRay) error {
+	dest := proxy.DestinationFromContext(ctx)
+	if !dest.IsValid() {
+		return errors.New("Proxyman|Mux: Target not specified.")
+	}
+
+	session := m.openSession(dest, link)
+	if session == nil {
+		return errors.New("Proxyman|Mux: Failed to open session to ", dest)
+	}
+	<-session.Done()
+	return nil
+}
+
+func (m *ClientManager) openSession(dest net.Destination, link ray.OutboundRay) *Session {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.closed {
+		return nil
+	}
+
+	for _, client := range m.clients {
+		if client.conn.sessionCount() < m.concurrency {
+			if s := client.openSession(dest, link); s != nil {
+				return s
+			}
+		}
+	}
+
+	client := newClient(m)
+	m.clients = append(m.clients, client)
+	return client.openSession(dest, link)
+}
+
+func (m *ClientManager) removeClient(client *Client) {
+	m.Lock()
+	defer m.Unlock()
+
+	for idx, c := range m.clients {
+		if c == client {
+			m.clients = append(m.clients[:idx], m.clients[idx+1:]...)
+			return
+		}
+	}
+}
+
+// Close closes all mux connections of this manager. Connections dispatched after it is closed are refused.
+func (m *ClientManager) Close() {
+	m.Lock()
+	clients := m.clients
+	m.clients = nil
+	m.closed = true
+	m.Unlock()
+
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+// Client is the client side of a mux connection.
+type Client struct {
+	sync.Mutex
+	manager   *ClientManager
+	link      ray.Ray
+	conn      *connection
+	nextID    uint16
+	idleTimer *time.Timer
+}
+
+func newClient(m *ClientManager) *Client {
+	ctx := proxy.ContextWithDestination(m.ctx, net.TCPDestination(muxCoolAddress, muxCoolPort))
+	link := ray.NewRay(ctx)
+	c := &Client{
+		manager: m,
+		link:    link,
+		conn:    newConnection(link.InboundInput()),
+	}
+	c.conn.onIdle = c.startIdleTimer
+
+	go m.dispatch(ctx, link)
+	go func() {
+		if err := c.conn.readLoop(buf.NewBytesReader(link.InboundOutput()), nil); err != nil {
+			log.Info("Proxyman|Mux: Connection closed: ", err)
+		}
+		c.Close()
+	}()
+	return c
+}
+
+// openSession starts a new session to the given destination. It returns nil if the connection is closed.
+func (c *Client) openSession(dest net.Destination, link ray.OutboundRay) *Session {
+	c.Lock()
+	if c.idleTimer != nil {
+		c.idleTimer.Stop()
+		c.idleTimer = nil
+	}
+	var s *Session
+	for retry := 0; retry < 16 && s == nil; retry++ {
+		c.nextID++
+		if c.nextID == 0 {
+			c.nextID++
+		}
+		s = c.conn.addSession(c.nextID, link.OutboundInput(), link.OutboundOutput())
+		if c.conn.isClosed() {
+			break
+		}
+	}
+	c.Unlock()
+
+	if s == nil {
+		return nil
+	}
+	if err := c.conn.writeFrame(&FrameMetadata{
+		SessionID:     s.ID,
+		SessionStatus: SessionStatusNew,
+		Target:        dest,
+	}, nil); err != nil {
+		s.Close()
+	}
+	go s.run()
+	return s
+}
+
+func (c *Client) startIdleTimer() {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.idleTimer != nil {
+		c.idleTimer.Stop()
+	}
+	c.idleTimer = time.AfterFunc(idleTimeout, func() {
+		c.manager.Lock()
+		idle := c.conn.sessionCount() == 0
+		c.manager.Unlock()
+		if idle {
+			c.Close()
+		}
+	})
+}
+
+// Close closes the mux connection and all sessions in it.
+func (c *Client) Close() {
+	c.manager.removeClient(c)
+	c.conn.close()
+	c.link.InboundInput().Close()
+	c.link.InboundOutput().CloseError()
+}
@@ -0,0 +1,193 @@
+package mux
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/transport/ray"
+)
+
+// connection carries frames of multiple sessions over a single stream.
+type connection struct {
+	sync.Mutex
+	writeLock sync.Mutex
+	writer    buf.Writer
+	sessions  map[uint16]*Session
+	closed    bool
+	onIdle    func()
+}
+
+func newConnection(writer buf.Writer) *connection {
+	return &connection{
+		writer:   writer,
+		sessions: make(map[uint16]*Session),
+	}
+}
+
+func (c *connection) writeFrame(meta *FrameMetadata, data []byte) error {
+	frame := meta.Bytes(data)
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_, err := buf.NewBytesWriter(c.writer).Write(frame)
+	return err
+}
+
+// addSession starts a session with the given ID. It returns nil if the connection is closed or the ID is in use.
+func (c *connection) addSession(id uint16, input ray.InputStream, output ray.OutputStream) *Session {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	if _, found := c.sessions[id]; found {
+		return nil
+	}
+	s := newSession(id, c, input, output)
+	c.sessions[id] = s
+	return s
+}
+
+func (c *connection) getSession(id uint16) *Session {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.sessions[id]
+}
+
+func (c *connection) removeSession(id uint16) {
+	c.Lock()
+	delete(c.sessions, id)
+	idle := len(c.sessions) == 0 && !c.closed
+	onIdle := c.onIdle
+	c.Unlock()
+
+	if idle && onIdle != nil {
+		onIdle()
+	}
+}
+
+func (c *connection) sessionCount() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.sessions)
+}
+
+func (c *connection) isClosed() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.closed
+}
+
+// close marks the connection closed and terminates all its sessions.
+func (c *connection) close() {
+	c.Lock()
+	if c.closed {
+		c.Unlock()
+		return
+	}
+	c.closed = true
+	sessions := make([]*Session, 0, len(c.sessions))
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	c.Unlock()
+
+	for _, s := range sessions {
+		s.Close()
+	}
+}
+
+// readLoop reads frames from the given reader and delivers them to sessions, until the reader fails. onNew is called
+// for frames of new sessions.
+func (c *connection) readLoop(reader io.Reader, onNew func(meta *FrameMetadata) *Session) error {
+	for {
+		meta, err := ReadFrameMetadata(reader)
+		if err != nil {
+			return err
+		}
+
+		var data *buf.Buffer
+		if meta.Option.Has(OptionData) {
+			data, err = ReadFrameData(reader)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := c.handleFrame(meta, data, onNew); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *connection) handleFrame(meta *FrameMetadata, data *buf.Buffer, onNew func(meta *FrameMetadata) *Session) error {
+	switch meta.SessionStatus {
+	case SessionStatusNew:
+		var s *Session
+		if onNew != nil {
+			s = onNew(meta)
+		}
+		if s == nil {
+			if data != nil {
+				data.Release()
+			}
+			return c.writeFrame(&FrameMetadata{
+				SessionID:     meta.SessionID,
+				SessionStatus: SessionStatusEnd,
+				Option:        OptionError,
+			}, nil)
+		}
+		if data != nil {
+			s.deliver(data)
+		}
+	case SessionStatusKeep:
+		s := c.getSession(meta.SessionID)
+		if s == nil {
+			if data != nil {
+				data.Release()
+			}
+			return c.writeFrame(&FrameMetadata{
+				SessionID:     meta.SessionID,
+				SessionStatus: SessionStatusEnd,
+				Option:        OptionError,
+			}, nil)
+		}
+		if data != nil {
+			s.deliver(data)
+		}
+	case SessionStatusEnd:
+		if data != nil {
+			data.Release()
+		}
+		if s := c.getSession(meta.SessionID); s != nil {
+			s.end(meta.Option.Has(OptionError))
+			if meta.Option.Has(OptionError) {
+				s.Close()
+			}
+		}
+	case SessionStatusCredit:
+		if data == nil || data.Len() != 4 {
+			return errors.New("Proxyman|Mux: Invalid credit frame.")
+		}
+		credit := binary.BigEndian.Uint32(data.Bytes())
+		data.Release()
+		if s := c.getSession(meta.SessionID); s != nil {
+			s.addCredit(int(credit))
+		}
+	default:
+		if data != nil {
+			data.Release()
+		}
+		log.Info("Proxyman|Mux: Unknown session status: ", meta.SessionStatus)
+	}
+	return nil
+}
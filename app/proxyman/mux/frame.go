@@ -0,0 +1,181 @@
+package mux
+
+import (
+	"encoding/binary"
+	"io"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/net"
+)
+
+// SessionStatus is the type of a frame.
+type SessionStatus byte
+
+const (
+	// SessionStatusNew starts a new session to the target in the frame.
+	SessionStatusNew SessionStatus = 0x01
+	// SessionStatusKeep carries data of an existing session.
+	SessionStatusKeep SessionStatus = 0x02
+	// SessionStatusEnd ends the sending direction of a session.
+	SessionStatusEnd SessionStatus = 0x03
+	// SessionStatusCredit allows the peer to send more data in a session. The data is a 4-byte increment.
+	SessionStatusCredit SessionStatus = 0x04
+)
+
+// Option is a set of flags of a frame.
+type Option byte
+
+const (
+	// OptionData indicates that the frame carries data.
+	OptionData Option = 0x01
+	// OptionError indicates that the session ends with an error.
+	OptionError Option = 0x02
+)
+
+func (o Option) Has(option Option) bool {
+	return o&option == option
+}
+
+const (
+	addressTypeIPv4   byte = 0x01
+	addressTypeDomain byte = 0x02
+	addressTypeIPv6   byte = 0x03
+
+	networkTCP byte = 0x01
+	networkUDP byte = 0x02
+
+	// maxDataSize is the max size of data in a frame. Data in a frame always fits in a buffer.
+	maxDataSize = buf.Size
+)
+
+// FrameMetadata is the header of a frame. A frame is encoded as:
+//
+//	2 bytes length of metadata | metadata | [2 bytes length of data | data]
+//
+// where metadata is:
+//
+//	2 bytes session ID | 1 byte status | 1 byte option | [1 byte network | 2 bytes port | address]
+//
+// The target is present only in frames of SessionStatusNew, and data is present only if OptionData is set.
+type FrameMetadata struct {
+	SessionID     uint16
+	SessionStatus SessionStatus
+	Option        Option
+	Target        net.Destination
+}
+
+// Bytes returns the encoded frame of the metadata and the given data.
+func (f *FrameMetadata) Bytes(data []byte) []byte {
+	meta := make([]byte, 6, 6+4+256)
+	binary.BigEndian.PutUint16(meta[2:], f.SessionID)
+	meta[4] = byte(f.SessionStatus)
+	meta[5] = byte(f.Option)
+
+	if f.SessionStatus == SessionStatusNew {
+		network := networkTCP
+		if f.Target.Network == net.Network_UDP {
+			network = networkUDP
+		}
+		meta = append(meta, network, byte(f.Target.Port>>8), byte(f.Target.Port))
+		address := f.Target.Address
+		switch address.Family() {
+		case net.AddressFamilyIPv4:
+			meta = append(meta, addressTypeIPv4)
+			meta = append(meta, address.IP()...)
+		case net.AddressFamilyIPv6:
+			meta = append(meta, addressTypeIPv6)
+			meta = append(meta, address.IP()...)
+		default:
+			domain := address.Domain()
+			meta = append(meta, addressTypeDomain, byte(len(domain)))
+			meta = append(meta, domain...)
+		}
+	}
+	binary.BigEndian.PutUint16(meta, uint16(len(meta)-2))
+
+	if f.Option.Has(OptionData) {
+		meta = append(meta, byte(len(data)>>8), byte(len(data)))
+		meta = append(meta, data...)
+	}
+	return meta
+}
+
+// ReadFrameMetadata reads the metadata of a frame from the given reader.
+func ReadFrameMetadata(reader io.Reader) (*FrameMetadata, error) {
+	var lenBytes [2]byte
+	if _, err := io.ReadFull(reader, lenBytes[:]); err != nil {
+		return nil, err
+	}
+	metaLen := int(binary.BigEndian.Uint16(lenBytes[:]))
+	if metaLen < 4 || metaLen > 512 {
+		return nil, errors.New("Proxyman|Mux: Invalid metadata length: ", metaLen)
+	}
+	meta := make([]byte, metaLen)
+	if _, err := io.ReadFull(reader, meta); err != nil {
+		return nil, err
+	}
+
+	f := &FrameMetadata{
+		SessionID:     binary.BigEndian.Uint16(meta),
+		SessionStatus: SessionStatus(meta[2]),
+		Option:        Option(meta[3]),
+	}
+	if f.SessionStatus != SessionStatusNew {
+		return f, nil
+	}
+
+	meta = meta[4:]
+	if len(meta) < 4 {
+		return nil, errors.New("Proxyman|Mux: Target is missing in new session.")
+	}
+	network := net.Network_TCP
+	if meta[0] == networkUDP {
+		network = net.Network_UDP
+	}
+	port := net.PortFromBytes(meta[1:3])
+	var address net.Address
+	switch meta[3] {
+	case addressTypeIPv4:
+		if len(meta) < 4+4 {
+			return nil, errors.New("Proxyman|Mux: Invalid IPv4 address.")
+		}
+		address = net.IPAddress(meta[4 : 4+4])
+	case addressTypeIPv6:
+		if len(meta) < 4+16 {
+			return nil, errors.New("Proxyman|Mux: Invalid IPv6 address.")
+		}
+		address = net.IPAddress(meta[4 : 4+16])
+	case addressTypeDomain:
+		if len(meta) < 5 || len(meta) < 5+int(meta[4]) {
+			return nil, errors.New("Proxyman|Mux: Invalid domain address.")
+		}
+		address = net.DomainAddress(string(meta[5 : 5+int(meta[4])]))
+	default:
+		return nil, errors.New("Proxyman|Mux: Unknown address type: ", meta[3])
+	}
+	f.Target = net.Destination{
+		Network: network,
+		Address: address,
+		Port:    port,
+	}
+	return f, nil
+}
+
+// ReadFrameData reads the data of a frame from the given reader.
+func ReadFrameData(reader io.Reader) (*buf.Buffer, error) {
+	var lenBytes [2]byte
+	if _, err := io.ReadFull(reader, lenBytes[:]); err != nil {
+		return nil, err
+	}
+	dataLen := int(binary.BigEndian.Uint16(lenBytes[:]))
+	if dataLen > maxDataSize {
+		return nil, errors.New("Proxyman|Mux: Data too large: ", dataLen)
+	}
+	b := buf.New()
+	if err := b.AppendSupplier(buf.ReadFullFrom(reader, dataLen)); err != nil {
+		b.Release()
+		return nil, err
+	}
+	return b, nil
+}
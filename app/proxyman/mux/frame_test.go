@@ -0,0 +1,68 @@
+package mux_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "v2ray.com/core/app/proxyman/mux"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestFrameMetadata(t *testing.T) {
+	assert := assert.On(t)
+
+	frames := []*FrameMetadata{
+		{
+			SessionID:     1,
+			SessionStatus: SessionStatusNew,
+			Target:        net.TCPDestination(net.DomainAddress("www.v2ray.com"), 443),
+		},
+		{
+			SessionID:     2,
+			SessionStatus: SessionStatusNew,
+			Target:        net.UDPDestination(net.IPAddress([]byte{8, 8, 8, 8}), 53),
+		},
+		{
+			SessionID:     3,
+			SessionStatus: SessionStatusNew,
+			Target:        net.TCPDestination(net.ParseAddress("2001:4860:4860::8888"), 80),
+		},
+		{
+			SessionID:     65535,
+			SessionStatus: SessionStatusEnd,
+			Option:        OptionError,
+		},
+	}
+
+	for _, frame := range frames {
+		reader := bytes.NewReader(frame.Bytes(nil))
+		actual, err := ReadFrameMetadata(reader)
+		assert.Error(err).IsNil()
+		assert.Int(int(actual.SessionID)).Equals(int(frame.SessionID))
+		assert.Int(int(actual.SessionStatus)).Equals(int(frame.SessionStatus))
+		assert.Int(int(actual.Option)).Equals(int(frame.Option))
+		if frame.SessionStatus == SessionStatusNew {
+			assert.String(actual.Target.String()).Equals(frame.Target.String())
+		}
+		assert.Int(reader.Len()).Equals(0)
+	}
+}
+
+func TestFrameData(t *testing.T) {
+	assert := assert.On(t)
+
+	frame := &FrameMetadata{
+		SessionID:     7,
+		SessionStatus: SessionStatusKeep,
+		Option:        OptionData,
+	}
+	reader := bytes.NewReader(frame.Bytes([]byte("abcd")))
+	meta, err := ReadFrameMetadata(reader)
+	assert.Error(err).IsNil()
+	assert.Bool(meta.Option.Has(OptionData)).IsTrue()
+
+	data, err := ReadFrameData(reader)
+	assert.Error(err).IsNil()
+	assert.String(data.String()).Equals("abcd")
+}
@@ -0,0 +1,21 @@
+// Package mux carries multiple connections, both TCP and UDP, over a single outbound connection.
+//
+// A mux client sends a connection to the pseudo destination "v1.mux.cool" through an outbound proxy, and multiplexes
+// sessions in frames over it. On the other end, the dispatcher hands such connections to a mux server, which
+// demultiplexes the sessions back into the dispatcher. Each session has its own flow control window, so that a slow
+// session doesn't block others.
+package mux
+
+import (
+	"v2ray.com/core/common/net"
+)
+
+var (
+	muxCoolAddress = net.DomainAddress("v1.mux.cool")
+	muxCoolPort    = net.Port(9527)
+)
+
+// IsMuxDestination returns true if the given destination is the pseudo destination of mux connections.
+func IsMuxDestination(dest net.Destination) bool {
+	return dest.Address != nil && dest.Address.Family().IsDomain() && dest.Address.Domain() == muxCoolAddress.Domain()
+}
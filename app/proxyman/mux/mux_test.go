@@ -0,0 +1,131 @@
+package mux_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/proxyman/mux"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+type echoDispatcher struct {
+	sync.Mutex
+	targets []net.Destination
+}
+
+func (d *echoDispatcher) DispatchToOutbound(ctx context.Context) ray.InboundRay {
+	d.Lock()
+	d.targets = append(d.targets, proxy.DestinationFromContext(ctx))
+	d.Unlock()
+
+	link := ray.NewRay(ctx)
+	go func() {
+		buf.PipeUntilEOF(link.OutboundInput(), link.OutboundOutput())
+		link.OutboundOutput().Close()
+	}()
+	return link
+}
+
+func readN(reader buf.Reader, n int) []byte {
+	var data []byte
+	for len(data) < n {
+		b, err := reader.Read()
+		if err != nil {
+			break
+		}
+		data = append(data, b.Bytes()...)
+		b.Release()
+	}
+	return data
+}
+
+func TestMultiplexing(t *testing.T) {
+	assert := assert.On(t)
+
+	dispatcher := new(echoDispatcher)
+	server := NewServer(dispatcher)
+
+	var connections int32
+	manager := NewClientManager(context.Background(), &proxyman.MultiplexingConfig{Concurrency: 4}, func(ctx context.Context, link ray.OutboundRay) {
+		atomic.AddInt32(&connections, 1)
+		assert.Bool(IsMuxDestination(proxy.DestinationFromContext(ctx))).IsTrue()
+
+		serverLink := server.Dispatch(ctx)
+		go func() {
+			buf.PipeUntilEOF(link.OutboundInput(), serverLink.InboundInput())
+			serverLink.InboundInput().Close()
+		}()
+		buf.PipeUntilEOF(serverLink.InboundOutput(), link.OutboundOutput())
+		link.OutboundOutput().Close()
+	})
+	defer manager.Close()
+
+	const sessions = 10
+	const chunkSize = 4 * 1024
+	const chunks = 256
+
+	var started, finished sync.WaitGroup
+	started.Add(sessions)
+	finished.Add(sessions)
+	for i := 0; i < sessions; i++ {
+		go func(i int) {
+			defer finished.Done()
+
+			dest := net.TCPDestination(net.DomainAddress("www.v2ray.com"), net.Port(1000+i))
+			if i%2 == 1 {
+				dest = net.UDPDestination(net.IPAddress([]byte{8, 8, 8, 8}), net.Port(1000+i))
+			}
+			ctx := proxy.ContextWithDestination(context.Background(), dest)
+			link := ray.NewRay(ctx)
+			go func() {
+				assert.Error(manager.Dispatch(ctx, link)).IsNil()
+			}()
+
+			payload := make([]byte, chunkSize*chunks)
+			rand.Read(payload)
+
+			var received []byte
+			for c := 0; c < chunks; c++ {
+				b := buf.New()
+				b.Append(payload[c*chunkSize : (c+1)*chunkSize])
+				assert.Error(link.InboundInput().Write(b)).IsNil()
+				if c == 0 {
+					// Keeps all sessions open at the same time.
+					received = readN(link.InboundOutput(), chunkSize)
+					started.Done()
+					started.Wait()
+				}
+			}
+			link.InboundInput().Close()
+
+			received = append(received, readN(link.InboundOutput(), len(payload)-len(received))...)
+			assert.Bool(bytes.Equal(received, payload)).IsTrue()
+
+			_, err := link.InboundOutput().Read()
+			assert.Error(err).IsNotNil()
+		}(i)
+	}
+	finished.Wait()
+
+	assert.Int(int(atomic.LoadInt32(&connections))).Equals(3)
+
+	dispatcher.Lock()
+	defer dispatcher.Unlock()
+	assert.Int(len(dispatcher.targets)).Equals(sessions)
+	udp := 0
+	for _, target := range dispatcher.targets {
+		if target.Network == net.Network_UDP {
+			udp++
+		}
+	}
+	assert.Int(udp).Equals(sessions / 2)
+}
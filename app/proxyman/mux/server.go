@@ -0,0 +1,50 @@
+package mux
+
+import (
+	"context"
+
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/ray"
+)
+
+// Server demultiplexes sessions in mux connections into a dispatcher.
+type Server struct {
+	dispatcher dispatcher.Interface
+}
+
+func NewServer(dispatcher dispatcher.Interface) *Server {
+	return &Server{
+		dispatcher: dispatcher,
+	}
+}
+
+// Dispatch returns a ray for the mux connection in the given context. Each session in the connection is dispatched
+// with the context and the target of the session.
+func (s *Server) Dispatch(ctx context.Context) ray.InboundRay {
+	link := ray.NewRay(ctx)
+	conn := newConnection(link.OutboundOutput())
+
+	go func() {
+		err := conn.readLoop(buf.NewBytesReader(link.OutboundInput()), func(meta *FrameMetadata) *Session {
+			log.Info("Proxyman|Mux: Received session ", meta.SessionID, " to ", meta.Target)
+			inboundRay := s.dispatcher.DispatchToOutbound(proxy.ContextWithDestination(ctx, meta.Target))
+			session := conn.addSession(meta.SessionID, inboundRay.InboundOutput(), inboundRay.InboundInput())
+			if session == nil {
+				inboundRay.InboundInput().CloseError()
+				inboundRay.InboundOutput().CloseError()
+				return nil
+			}
+			go session.run()
+			return session
+		})
+		if err != nil {
+			log.Info("Proxyman|Mux: Connection closed: ", err)
+		}
+		conn.close()
+		link.OutboundOutput().Close()
+	}()
+	return link
+}
@@ -0,0 +1,231 @@
+package mux
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/transport/ray"
+)
+
+const (
+	// initialWindow is the number of bytes that a peer may send in a session before receiving any credit.
+	initialWindow = 256 * 1024
+)
+
+// Session is a logical connection over a mux connection. Data read from input is sent to the peer, and data from the
+// peer is written into output.
+type Session struct {
+	ID     uint16
+	conn   *connection
+	input  ray.InputStream
+	output ray.OutputStream
+
+	cond       *sync.Cond
+	sendWindow int
+	pending    []*buf.Buffer
+	recvBytes  int
+	remoteEnd  bool
+	remoteErr  bool
+	closed     bool
+
+	sendDone chan struct{}
+	recvDone chan struct{}
+	done     chan struct{}
+}
+
+func newSession(id uint16, conn *connection, input ray.InputStream, output ray.OutputStream) *Session {
+	return &Session{
+		ID:         id,
+		conn:       conn,
+		input:      input,
+		output:     output,
+		cond:       sync.NewCond(new(sync.Mutex)),
+		sendWindow: initialWindow,
+		sendDone:   make(chan struct{}),
+		recvDone:   make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// run pumps data in both directions, until both directions finish.
+func (s *Session) run() {
+	go s.send()
+	go s.receive()
+	<-s.sendDone
+	<-s.recvDone
+	s.conn.removeSession(s.ID)
+	close(s.done)
+}
+
+// Done returns a channel that is closed when both directions of this session finish.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// send reads data from input and sends it to the peer within the send window.
+func (s *Session) send() {
+	defer close(s.sendDone)
+
+	for {
+		b, err := s.input.Read()
+		if err != nil {
+			option := Option(0)
+			if errors.Cause(err) != io.EOF {
+				option = OptionError
+			}
+			s.conn.writeFrame(&FrameMetadata{
+				SessionID:     s.ID,
+				SessionStatus: SessionStatusEnd,
+				Option:        option,
+			}, nil)
+			return
+		}
+
+		data := b.Bytes()
+		for len(data) > 0 {
+			n := len(data)
+			if n > maxDataSize {
+				n = maxDataSize
+			}
+			if !s.acquireWindow(n) {
+				b.Release()
+				return
+			}
+			if err := s.conn.writeFrame(&FrameMetadata{
+				SessionID:     s.ID,
+				SessionStatus: SessionStatusKeep,
+				Option:        OptionData,
+			}, data[:n]); err != nil {
+				b.Release()
+				s.input.CloseError()
+				return
+			}
+			data = data[n:]
+		}
+		b.Release()
+	}
+}
+
+// acquireWindow waits until size bytes can be sent, and returns false if the session is closed.
+func (s *Session) acquireWindow(size int) bool {
+	s.cond.L.Lock()
+	defer s.cond.L.Unlock()
+
+	for s.sendWindow < size && !s.closed {
+		s.cond.Wait()
+	}
+	if s.closed {
+		return false
+	}
+	s.sendWindow -= size
+	return true
+}
+
+func (s *Session) addCredit(credit int) {
+	s.cond.L.Lock()
+	s.sendWindow += credit
+	s.cond.L.Unlock()
+	s.cond.Broadcast()
+}
+
+// deliver queues data from the peer. It never blocks, as the peer sends no more than the window allows.
+func (s *Session) deliver(b *buf.Buffer) {
+	s.cond.L.Lock()
+	defer s.cond.L.Unlock()
+
+	if s.remoteEnd || s.closed {
+		b.Release()
+		return
+	}
+	s.recvBytes += b.Len()
+	if s.recvBytes > initialWindow {
+		log.Warning("Proxyman|Mux: Session ", s.ID, " exceeds its receive window.")
+		b.Release()
+		s.closeLocked()
+		return
+	}
+	s.pending = append(s.pending, b)
+	s.cond.Broadcast()
+}
+
+// end marks the end of data from the peer.
+func (s *Session) end(hasError bool) {
+	s.cond.L.Lock()
+	s.remoteEnd = true
+	s.remoteErr = hasError
+	s.cond.L.Unlock()
+	s.cond.Broadcast()
+}
+
+// receive writes queued data from the peer into output, and grants credit to the peer for written data.
+func (s *Session) receive() {
+	defer close(s.recvDone)
+
+	written := 0
+	for {
+		s.cond.L.Lock()
+		for len(s.pending) == 0 && !s.remoteEnd && !s.closed {
+			s.cond.Wait()
+		}
+		if s.closed || (len(s.pending) == 0 && s.remoteEnd) {
+			hasError := s.closed || s.remoteErr
+			s.cond.L.Unlock()
+			if hasError {
+				s.output.CloseError()
+			} else {
+				s.output.Close()
+			}
+			return
+		}
+		b := s.pending[0]
+		s.pending = s.pending[1:]
+		s.cond.L.Unlock()
+
+		size := b.Len()
+		if err := s.output.Write(b); err != nil {
+			b.Release()
+			s.Close()
+			continue
+		}
+
+		s.cond.L.Lock()
+		s.recvBytes -= size
+		s.cond.L.Unlock()
+
+		written += size
+		if written >= initialWindow/4 {
+			credit := make([]byte, 4)
+			binary.BigEndian.PutUint32(credit, uint32(written))
+			s.conn.writeFrame(&FrameMetadata{
+				SessionID:     s.ID,
+				SessionStatus: SessionStatusCredit,
+				Option:        OptionData,
+			}, credit)
+			written = 0
+		}
+	}
+}
+
+// Close terminates both directions of this session with error.
+func (s *Session) Close() {
+	s.cond.L.Lock()
+	s.closeLocked()
+	s.cond.L.Unlock()
+}
+
+func (s *Session) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for _, b := range s.pending {
+		b.Release()
+	}
+	s.pending = nil
+	s.input.CloseError()
+	s.cond.Broadcast()
+}
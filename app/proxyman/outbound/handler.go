@@ -9,6 +9,7 @@ import (
 
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/proxyman/mux"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/log"
 	v2net "v2ray.com/core/common/net"
@@ -22,6 +23,8 @@ type Handler struct {
 	senderSettings  *proxyman.SenderConfig
 	proxy           proxy.Outbound
 	outboundManager proxyman.OutboundHandlerManager
+	mux             *mux.ClientManager
+	expireTimer     *time.Timer
 }
 
 func NewHandler(ctx context.Context, config *proxyman.OutboundHandlerConfig) (*Handler, error) {
@@ -60,14 +63,43 @@ func NewHandler(ctx context.Context, config *proxyman.OutboundHandlerConfig) (*H
 	}
 
 	h.proxy = proxyHandler
+
+	if h.senderSettings != nil && h.senderSettings.MultiplexSettings != nil && h.senderSettings.MultiplexSettings.Enabled {
+		h.mux = mux.NewClientManager(ctx, h.senderSettings.MultiplexSettings, h.dispatchToProxy)
+	}
 	return h, nil
 }
 
+func (h *Handler) Tag() string {
+	return h.config.Tag
+}
+
+// Dispatch sends the connection on the given ray through the proxy of this handler, multiplexed if enabled.
 func (h *Handler) Dispatch(ctx context.Context, outboundRay ray.OutboundRay) {
+	if h.mux != nil {
+		if err := h.mux.Dispatch(ctx, outboundRay); err != nil {
+			log.Warning("Proxyman|OutboundHandler: ", err)
+			outboundRay.OutboundInput().CloseError()
+			outboundRay.OutboundOutput().CloseError()
+		}
+		return
+	}
+	h.dispatchToProxy(ctx, outboundRay)
+}
+
+func (h *Handler) dispatchToProxy(ctx context.Context, outboundRay ray.OutboundRay) {
 	ctx = proxy.ContextWithDialer(ctx, h)
 	h.proxy.Process(ctx, outboundRay)
 }
 
+// Close closes the mux connections of this handler, together with all connections multiplexed in them. Connections
+// that are not multiplexed are not affected.
+func (h *Handler) Close() {
+	if h.mux != nil {
+		h.mux.Close()
+	}
+}
+
 func (h *Handler) Dial(ctx context.Context, dest v2net.Destination) (internet.Connection, error) {
 	if h.senderSettings != nil {
 		if h.senderSettings.ProxySettings.HasTag() {
@@ -3,14 +3,18 @@ package outbound
 import (
 	"context"
 	"sync"
+	"time"
 
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
 )
 
 type DefaultOutboundHandlerManager struct {
 	sync.RWMutex
 	defaultHandler *Handler
+	handlers       []*Handler
 	taggedHandler  map[string]*Handler
 }
 
@@ -20,7 +24,7 @@ func New(ctx context.Context, config *proxyman.OutboundConfig) (*DefaultOutbound
 	}, nil
 }
 
-func (DefaultOutboundHandlerManager) Interface() interface{} {
+func (*DefaultOutboundHandlerManager) Interface() interface{} {
 	return (*proxyman.OutboundHandlerManager)(nil)
 }
 
@@ -43,9 +47,17 @@ func (v *DefaultOutboundHandlerManager) GetHandler(tag string) proxyman.Outbound
 }
 
 func (v *DefaultOutboundHandlerManager) AddHandler(ctx context.Context, config *proxyman.OutboundHandlerConfig) error {
+	if config.HasExpired(time.Now()) {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Handler already expired: ", describe(config))
+	}
+
 	v.Lock()
 	defer v.Unlock()
 
+	if _, found := v.taggedHandler[config.Tag]; found && len(config.Tag) > 0 {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Handler already exists: ", config.Tag)
+	}
+
 	handler, err := NewHandler(ctx, config)
 	if err != nil {
 		return err
@@ -54,13 +66,161 @@ func (v *DefaultOutboundHandlerManager) AddHandler(ctx context.Context, config *
 		v.defaultHandler = handler
 	}
 
+	v.handlers = append(v.handlers, handler)
 	if len(config.Tag) > 0 {
 		v.taggedHandler[config.Tag] = handler
 	}
+	v.scheduleExpiration(handler)
+	log.Info("Proxyman|DefaultOutboundHandlerManager: Handler added: ", describe(config))
 
 	return nil
 }
 
+// describe returns the tag and comment of the given config, for logging.
+func describe(config *proxyman.OutboundHandlerConfig) string {
+	if len(config.Comment) == 0 {
+		return "[" + config.Tag + "]"
+	}
+	return "[" + config.Tag + "] (" + config.Comment + ")"
+}
+
+// scheduleExpiration retires the handler when its config expires. It must be called with the lock held.
+func (v *DefaultOutboundHandlerManager) scheduleExpiration(handler *Handler) {
+	expire := handler.config.ExpireTime()
+	if expire.IsZero() {
+		return
+	}
+	handler.expireTimer = time.AfterFunc(time.Until(expire), func() {
+		v.Lock()
+		defer v.Unlock()
+
+		if v.removeHandler(handler) {
+			log.Warning("Proxyman|DefaultOutboundHandlerManager: Handler expired: ", describe(handler.config))
+		}
+	})
+}
+
+// removeHandler removes the given handler from this manager, and returns false if the handler doesn't exist.
+// If the handler is the default one, the first remaining handler becomes the new default. It must be called with the
+// lock held.
+func (v *DefaultOutboundHandlerManager) removeHandler(handler *Handler) bool {
+	idx := -1
+	for i, h := range v.handlers {
+		if h == handler {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return false
+	}
+	v.handlers = append(v.handlers[:idx], v.handlers[idx+1:]...)
+
+	if tag := handler.config.Tag; len(tag) > 0 && v.taggedHandler[tag] == handler {
+		delete(v.taggedHandler, tag)
+	}
+	if handler.expireTimer != nil {
+		handler.expireTimer.Stop()
+	}
+	handler.Close()
+
+	if handler == v.defaultHandler {
+		v.defaultHandler = nil
+		if len(v.handlers) > 0 {
+			v.defaultHandler = v.handlers[0]
+			log.Info("Proxyman|DefaultOutboundHandlerManager: Default handler is now: ", describe(v.defaultHandler.config))
+		}
+	}
+	return true
+}
+
+// RemoveHandler removes the handler with the given tag. If the handler is the default one, the first remaining handler
+// becomes the new default. Connections already dispatched to the removed handler are not affected, unless they are
+// multiplexed.
+func (v *DefaultOutboundHandlerManager) RemoveHandler(ctx context.Context, tag string) error {
+	if len(tag) == 0 {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Empty tag.")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	handler, found := v.taggedHandler[tag]
+	if !found {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Handler not found: ", tag)
+	}
+	v.removeHandler(handler)
+	log.Info("Proxyman|DefaultOutboundHandlerManager: Handler removed: ", describe(handler.config))
+	return nil
+}
+
+// ReplaceHandler replaces the handler that has the same tag as the given config. The new handler takes the place of
+// the old one, including being the default handler. The old handler is closed.
+func (v *DefaultOutboundHandlerManager) ReplaceHandler(ctx context.Context, config *proxyman.OutboundHandlerConfig) error {
+	tag := config.Tag
+	if len(tag) == 0 {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Empty tag.")
+	}
+	if config.HasExpired(time.Now()) {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Handler already expired: ", describe(config))
+	}
+
+	handler, err := NewHandler(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	oldHandler, found := v.taggedHandler[tag]
+	if !found {
+		return errors.New("Proxyman|DefaultOutboundHandlerManager: Handler not found: ", tag)
+	}
+	if oldHandler.expireTimer != nil {
+		oldHandler.expireTimer.Stop()
+	}
+
+	v.taggedHandler[tag] = handler
+	for idx, h := range v.handlers {
+		if h == oldHandler {
+			v.handlers[idx] = handler
+			break
+		}
+	}
+	if oldHandler == v.defaultHandler {
+		v.defaultHandler = handler
+	}
+	oldHandler.Close()
+	v.scheduleExpiration(handler)
+	log.Info("Proxyman|DefaultOutboundHandlerManager: Handler replaced: ", describe(config))
+	return nil
+}
+
+func (v *DefaultOutboundHandlerManager) ListHandlers(ctx context.Context) []*proxyman.OutboundHandlerConfig {
+	v.RLock()
+	defer v.RUnlock()
+
+	configs := make([]*proxyman.OutboundHandlerConfig, len(v.handlers))
+	for idx, handler := range v.handlers {
+		configs[idx] = handler.config
+	}
+	return configs
+}
+
+func (v *DefaultOutboundHandlerManager) ListTags(ctx context.Context) []string {
+	v.RLock()
+	defer v.RUnlock()
+
+	tags := make([]string, 0, len(v.taggedHandler))
+	for _, handler := range v.handlers {
+		if len(handler.config.Tag) > 0 {
+			tags = append(tags, handler.config.Tag)
+		}
+	}
+	return tags
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*proxyman.OutboundConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*proxyman.OutboundConfig))
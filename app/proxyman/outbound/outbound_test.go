@@ -0,0 +1,120 @@
+package outbound_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+func TestRemoveDefaultHandler(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	ohm, err := New(ctx, new(proxyman.OutboundConfig))
+	assert.Error(err).IsNil()
+	assert.Error(space.AddApplication(ohm)).IsNil()
+
+	for _, tag := range []string{"a", "b", "c"} {
+		assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+		})).IsNil()
+	}
+	assert.Error(space.Initialize()).IsNil()
+
+	assert.Bool(ohm.GetDefaultHandler() == ohm.GetHandler("a")).IsTrue()
+	assert.Error(ohm.RemoveHandler(ctx, "a")).IsNil()
+	assert.Bool(ohm.GetHandler("a") == nil).IsTrue()
+	assert.Bool(ohm.GetDefaultHandler() == ohm.GetHandler("b")).IsTrue()
+
+	tags := ohm.ListTags(ctx)
+	assert.Int(len(tags)).Equals(2)
+	assert.String(tags[0]).Equals("b")
+	assert.String(tags[1]).Equals("c")
+
+	assert.Error(ohm.ReplaceHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "b",
+		ProxySettings: serial.ToTypedMessage(new(blackhole.Config)),
+	})).IsNil()
+	assert.Bool(ohm.GetDefaultHandler() == ohm.GetHandler("b")).IsTrue()
+	assert.Int(len(ohm.ListHandlers(ctx))).Equals(2)
+
+	assert.Error(ohm.RemoveHandler(ctx, "b")).IsNil()
+	assert.Error(ohm.RemoveHandler(ctx, "c")).IsNil()
+	assert.Bool(ohm.GetDefaultHandler() == nil).IsTrue()
+	assert.Error(ohm.RemoveHandler(ctx, "c")).IsNotNil()
+}
+
+func TestExpireHandler(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	ohm, err := New(ctx, new(proxyman.OutboundConfig))
+	assert.Error(err).IsNil()
+	assert.Error(space.AddApplication(ohm)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "expired",
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+		Expire:        time.Now().Unix() - 1,
+	})).IsNotNil()
+
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "a",
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+		Expire:        time.Now().Add(time.Second).Unix() + 1,
+		Comment:       "trial",
+	})).IsNil()
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "b",
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+	})).IsNil()
+	assert.Bool(ohm.GetDefaultHandler() == ohm.GetHandler("a")).IsTrue()
+
+	time.Sleep(3 * time.Second)
+	assert.Bool(ohm.GetHandler("a") == nil).IsTrue()
+	assert.Bool(ohm.GetDefaultHandler() == ohm.GetHandler("b")).IsTrue()
+	assert.Int(len(ohm.ListHandlers(ctx))).Equals(1)
+}
+
+func TestRemoveHandlerClosesMux(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	ohm, err := New(ctx, new(proxyman.OutboundConfig))
+	assert.Error(err).IsNil()
+	assert.Error(space.AddApplication(ohm)).IsNil()
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag: "mux",
+		SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+			MultiplexSettings: &proxyman.MultiplexingConfig{Enabled: true},
+		}),
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+	})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	handler := ohm.GetHandler("mux")
+	assert.Error(ohm.RemoveHandler(ctx, "mux")).IsNil()
+
+	ctx = proxy.ContextWithDestination(ctx, net.TCPDestination(net.LocalHostIP, 80))
+	link := ray.NewRay(ctx)
+	go handler.Dispatch(ctx, link)
+	_, err = link.InboundOutput().ReadTimeout(time.Second * 2)
+	assert.Error(err).Equals(io.ErrClosedPipe)
+}
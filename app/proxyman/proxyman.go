@@ -13,6 +13,15 @@ import (
 type InboundHandlerManager interface {
 	GetHandler(ctx context.Context, tag string) (InboundHandler, error)
 	AddHandler(ctx context.Context, config *InboundHandlerConfig) error
+	// RemoveHandler closes the handler with the given tag and removes it from this manager. Connections in progress
+	// on the handler are closed as well.
+	RemoveHandler(ctx context.Context, tag string) error
+	// ReplaceHandler closes the handler that has the same tag as the given config, and adds a new handler in its place.
+	ReplaceHandler(ctx context.Context, config *InboundHandlerConfig) error
+	// ListHandlers returns configurations of all handlers, in the order they were added.
+	ListHandlers(ctx context.Context) []*InboundHandlerConfig
+	// ListTags returns tags of all tagged handlers, in the order they were added.
+	ListTags(ctx context.Context) []string
 	Start() error
 	Close()
 }
@@ -29,9 +38,20 @@ type OutboundHandlerManager interface {
 	GetHandler(tag string) OutboundHandler
 	GetDefaultHandler() OutboundHandler
 	AddHandler(ctx context.Context, config *OutboundHandlerConfig) error
+	// RemoveHandler removes the handler with the given tag from this manager. If the default handler is removed,
+	// the first remaining handler becomes the default one.
+	RemoveHandler(ctx context.Context, tag string) error
+	// ReplaceHandler replaces the handler that has the same tag as the given config.
+	ReplaceHandler(ctx context.Context, config *OutboundHandlerConfig) error
+	// ListHandlers returns configurations of all handlers, in the order they were added.
+	ListHandlers(ctx context.Context) []*OutboundHandlerConfig
+	// ListTags returns tags of all tagged handlers, in the order they were added.
+	ListTags(ctx context.Context) []string
 }
 
 type OutboundHandler interface {
+	// Tag returns the tag of this handler, or empty if the handler is not tagged.
+	Tag() string
 	Dispatch(ctx context.Context, outboundRay ray.OutboundRay)
 }
 
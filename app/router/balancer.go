@@ -0,0 +1,181 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/observatory"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/errors"
+)
+
+type outboundState struct {
+	activeConn int
+	latency    time.Duration
+}
+
+// Balancer picks an outbound handler from a group of handlers for each connection.
+type Balancer struct {
+	sync.Mutex
+	// config is a copy of the rule this balancer is created for, to find out whether the rule changed on reload.
+	config    *BalancingRule
+	tag       string
+	selectors []string
+	strategy  BalancingRule_Strategy
+	ohm       proxyman.OutboundHandlerManager
+	observer  *observatory.Observatory
+	next      int
+	outbounds map[string]*outboundState
+}
+
+// NewBalancer creates a balancer for the given rule. If observer is not nil, handlers that failed their latest probes are
+// skipped, and probe results are used for latency.
+func NewBalancer(rule *BalancingRule, ohm proxyman.OutboundHandlerManager, observer *observatory.Observatory) *Balancer {
+	return &Balancer{
+		config:    proto.Clone(rule).(*BalancingRule),
+		tag:       rule.Tag,
+		selectors: rule.OutboundSelector,
+		strategy:  rule.Strategy,
+		ohm:       ohm,
+		observer:  observer,
+		outbounds: make(map[string]*outboundState),
+	}
+}
+
+func (b *Balancer) Tag() string {
+	return b.tag
+}
+
+// SelectOutbounds returns the tags of all live outbound handlers in this group, in the order of the handlers.
+func (b *Balancer) SelectOutbounds(ctx context.Context) []string {
+	var tags []string
+	for _, tag := range b.ohm.ListTags(ctx) {
+		if b.observer != nil && !b.observer.IsAlive(tag) {
+			continue
+		}
+		for _, selector := range b.selectors {
+			if strings.HasPrefix(tag, selector) {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// PickOutbound returns the tag of an outbound handler in this group, chosen by the strategy of this balancer.
+func (b *Balancer) PickOutbound(ctx context.Context) (string, error) {
+	return b.pick(ctx, true)
+}
+
+// PeekOutbound returns the tag that PickOutbound would return now, without changing the state of this balancer. With
+// the random strategy, the tag is only one of the possible choices.
+func (b *Balancer) PeekOutbound(ctx context.Context) (string, error) {
+	return b.pick(ctx, false)
+}
+
+// pick implements PickOutbound and PeekOutbound. The round robin position is advanced only if advance is true.
+func (b *Balancer) pick(ctx context.Context, advance bool) (string, error) {
+	candidates := b.SelectOutbounds(ctx)
+	if len(candidates) == 0 {
+		return "", errors.New("Router|Balancer: No outbound available in balancer: ", b.tag)
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.strategy {
+	case BalancingRule_RoundRobin:
+		tag := candidates[b.next%len(candidates)]
+		if advance {
+			b.next = (b.next + 1) % len(candidates)
+		}
+		return tag, nil
+	case BalancingRule_LeastConn:
+		picked := candidates[0]
+		for _, tag := range candidates[1:] {
+			if b.activeConn(tag) < b.activeConn(picked) {
+				picked = tag
+			}
+		}
+		return picked, nil
+	case BalancingRule_LeastLatency:
+		// Handlers without any observation have zero latency, so that they are tried first.
+		picked := candidates[0]
+		for _, tag := range candidates[1:] {
+			if b.latency(tag) < b.latency(picked) {
+				picked = tag
+			}
+		}
+		return picked, nil
+	default:
+		return candidates[dice.Roll(len(candidates))], nil
+	}
+}
+
+// state returns the state of the given outbound. It must be called with the lock held.
+func (b *Balancer) state(tag string) *outboundState {
+	s, found := b.outbounds[tag]
+	if !found {
+		s = new(outboundState)
+		b.outbounds[tag] = s
+	}
+	return s
+}
+
+// activeConn returns the number of active connections on the given outbound. It must be called with the lock held.
+func (b *Balancer) activeConn(tag string) int {
+	if s, found := b.outbounds[tag]; found {
+		return s.activeConn
+	}
+	return 0
+}
+
+// latency returns the latency of the given outbound, preferring the result of the latest probe. It must be called with
+// the lock held.
+func (b *Balancer) latency(tag string) time.Duration {
+	if b.observer != nil {
+		if status := b.observer.GetStatus(tag); status != nil && status.Alive {
+			return time.Duration(status.Delay) * time.Millisecond
+		}
+	}
+	if s, found := b.outbounds[tag]; found {
+		return s.latency
+	}
+	return 0
+}
+
+// Acquire records a new connection on the given outbound. The returned function must be called when the connection
+// finishes.
+func (b *Balancer) Acquire(tag string) func() {
+	b.Lock()
+	b.state(tag).activeConn++
+	b.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.Lock()
+			b.state(tag).activeConn--
+			b.Unlock()
+		})
+	}
+}
+
+// ReportLatency records an observed latency of the given outbound. The latency used for picking is a moving average
+// of all observations.
+func (b *Balancer) ReportLatency(tag string, latency time.Duration) {
+	b.Lock()
+	defer b.Unlock()
+
+	s := b.state(tag)
+	if s.latency == 0 {
+		s.latency = latency
+	} else {
+		s.latency = (s.latency*3 + latency) / 4
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"v2ray.com/core/common/errors"
 	v2net "v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/proxy"
@@ -71,7 +72,7 @@ type PlainDomainMatcher struct {
 
 func NewPlainDomainMatcher(pattern string) *PlainDomainMatcher {
 	return &PlainDomainMatcher{
-		pattern: pattern,
+		pattern: strings.ToLower(pattern),
 	}
 }
 
@@ -81,7 +82,7 @@ func (v *PlainDomainMatcher) Apply(ctx context.Context) bool {
 		return false
 	}
 	domain := dest.Address.Domain()
-	return strings.Contains(domain, v.pattern)
+	return strings.Contains(strings.ToLower(domain), v.pattern)
 }
 
 type RegexpDomainMatcher struct {
@@ -107,6 +108,131 @@ func (v *RegexpDomainMatcher) Apply(ctx context.Context) bool {
 	return v.pattern.MatchString(strings.ToLower(domain))
 }
 
+// domainTrie is a trie of domain labels, from the top-level label down.
+type domainTrie struct {
+	children map[string]*domainTrie
+	terminal bool
+}
+
+func newDomainTrie() *domainTrie {
+	return &domainTrie{
+		children: make(map[string]*domainTrie),
+	}
+}
+
+// Add inserts the given domain, so that the domain and all its subdomains match.
+func (t *domainTrie) Add(domain string) {
+	node := t
+	for domain != "" {
+		idx := strings.LastIndexByte(domain, '.')
+		label := domain[idx+1:]
+		child, found := node.children[label]
+		if !found {
+			child = newDomainTrie()
+			node.children[label] = child
+		}
+		node = child
+		if idx < 0 {
+			break
+		}
+		domain = domain[:idx]
+	}
+	node.terminal = true
+}
+
+// Match returns true if the given domain or any of its parent domains was added.
+func (t *domainTrie) Match(domain string) bool {
+	node := t
+	for domain != "" {
+		idx := strings.LastIndexByte(domain, '.')
+		child, found := node.children[domain[idx+1:]]
+		if !found {
+			return false
+		}
+		if child.terminal {
+			return true
+		}
+		node = child
+		if idx < 0 {
+			break
+		}
+		domain = domain[:idx]
+	}
+	return false
+}
+
+// DomainMatcher matches the destination domain against a list of domains of all types at once. Full and subdomain
+// values are looked up in a hash set and a trie, so the cost does not grow with the length of the list.
+type DomainMatcher struct {
+	full      map[string]bool
+	subdomain *domainTrie
+	keywords  []string
+	regexps   []*regexp.Regexp
+}
+
+func NewDomainMatcher(domains []*Domain) (*DomainMatcher, error) {
+	m := &DomainMatcher{
+		full:      make(map[string]bool),
+		subdomain: newDomainTrie(),
+	}
+	for _, domain := range domains {
+		switch domain.Type {
+		case Domain_Plain:
+			m.keywords = append(m.keywords, strings.ToLower(domain.Value))
+		case Domain_Regex:
+			r, err := regexp.Compile(domain.Value)
+			if err != nil {
+				return nil, err
+			}
+			m.regexps = append(m.regexps, r)
+		case Domain_Subdomain:
+			m.subdomain.Add(normalizeDomain(domain.Value))
+		case Domain_Full:
+			m.full[normalizeDomain(domain.Value)] = true
+		default:
+			return nil, errors.New("Router: Unknown domain type: ", domain.Type)
+		}
+	}
+	return m, nil
+}
+
+// normalizeDomain returns the given domain in lower case, without the trailing dot of a fully qualified domain.
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(domain), ".")
+}
+
+// MatchDomain returns true if the given domain matches any of the domains in this matcher. Domains are matched case
+// insensitively.
+func (m *DomainMatcher) MatchDomain(domain string) bool {
+	domain = strings.ToLower(domain)
+	for _, keyword := range m.keywords {
+		if strings.Contains(domain, keyword) {
+			return true
+		}
+	}
+
+	domain = normalizeDomain(domain)
+	if m.full[domain] || m.subdomain.Match(domain) {
+		return true
+	}
+	for _, r := range m.regexps {
+		if r.MatchString(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *DomainMatcher) Apply(ctx context.Context) bool {
+	dest := proxy.DestinationFromContext(ctx)
+	if !dest.Address.Family().IsDomain() {
+		return false
+	}
+	return m.MatchDomain(dest.Address.Domain())
+}
+
+// CIDRMatcher matches IPv6 addresses of the connection against a single network. Rules use CIDRListMatcher instead,
+// and CIDRMatcher is kept as the baseline of the IPv6 benchmarks of CIDRListMatcher.
 type CIDRMatcher struct {
 	cidr     *net.IPNet
 	onSource bool
@@ -153,6 +279,8 @@ func (v *CIDRMatcher) Apply(ctx context.Context) bool {
 	return false
 }
 
+// IPv4Matcher matches IPv4 addresses of the connection against a v2net.IPNet. Rules use CIDRListMatcher instead,
+// and IPv4Matcher is kept as the baseline of the IPv4 benchmarks of CIDRListMatcher.
 type IPv4Matcher struct {
 	ipv4net  *v2net.IPNet
 	onSource bool
@@ -195,6 +323,131 @@ func (v *IPv4Matcher) Apply(ctx context.Context) bool {
 	return false
 }
 
+type ipTrieNode struct {
+	// children are indices of child nodes for bit 0 and 1. Index 0 is the root, which is never a child.
+	children [2]uint32
+	terminal bool
+}
+
+// ipTrie is a binary prefix tree of IP networks. Nodes are kept in a slice, so that large lists stay compact.
+type ipTrie struct {
+	nodes []ipTrieNode
+}
+
+func newIPTrie() *ipTrie {
+	return &ipTrie{
+		nodes: make([]ipTrieNode, 1, 1024),
+	}
+}
+
+func ipBit(ip []byte, idx uint32) uint32 {
+	return uint32(ip[idx/8]>>(7-idx%8)) & 1
+}
+
+// Add inserts the network of the given IP and prefix length.
+func (t *ipTrie) Add(ip []byte, prefix uint32) {
+	node := uint32(0)
+	for idx := uint32(0); idx < prefix; idx++ {
+		if t.nodes[node].terminal {
+			return
+		}
+		bit := ipBit(ip, idx)
+		child := t.nodes[node].children[bit]
+		if child == 0 {
+			t.nodes = append(t.nodes, ipTrieNode{})
+			child = uint32(len(t.nodes) - 1)
+			t.nodes[node].children[bit] = child
+		}
+		node = child
+	}
+	t.nodes[node].terminal = true
+	t.nodes[node].children = [2]uint32{}
+}
+
+// Contains returns true if the given IP is in any of the networks. It takes at most one step per bit of the IP.
+func (t *ipTrie) Contains(ip []byte) bool {
+	node := uint32(0)
+	for idx := uint32(0); ; idx++ {
+		if t.nodes[node].terminal {
+			return true
+		}
+		if idx == uint32(len(ip))*8 {
+			return false
+		}
+		node = t.nodes[node].children[ipBit(ip, idx)]
+		if node == 0 {
+			return false
+		}
+	}
+}
+
+// CIDRListMatcher matches IPs of the connection against a list of IPv4 and IPv6 networks, in time proportional to the
+// length of the IPs instead of the size of the list.
+type CIDRListMatcher struct {
+	ipv4     *ipTrie
+	ipv6     *ipTrie
+	onSource bool
+}
+
+// NewCIDRListMatcher creates a matcher for the given networks. If onSource is true, the source of the connection is
+// matched. Otherwise the destination and its resolved IPs are matched.
+func NewCIDRListMatcher(cidrs []*CIDR, onSource bool) (*CIDRListMatcher, error) {
+	m := &CIDRListMatcher{
+		ipv4:     newIPTrie(),
+		ipv6:     newIPTrie(),
+		onSource: onSource,
+	}
+	for _, cidr := range cidrs {
+		switch len(cidr.Ip) {
+		case net.IPv4len:
+			if cidr.Prefix > 32 {
+				return nil, errors.New("Router: Invalid network prefix: ", cidr.Prefix)
+			}
+			m.ipv4.Add(cidr.Ip, cidr.Prefix)
+		case net.IPv6len:
+			if cidr.Prefix > 128 {
+				return nil, errors.New("Router: Invalid network prefix: ", cidr.Prefix)
+			}
+			m.ipv6.Add(cidr.Ip, cidr.Prefix)
+		default:
+			return nil, errors.New("Router: Invalid IP length.")
+		}
+	}
+	return m, nil
+}
+
+// Contains returns true if the given IP is in any of the networks of this matcher.
+func (m *CIDRListMatcher) Contains(ip net.IP) bool {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return m.ipv4.Contains(ipv4)
+	}
+	if len(ip) == net.IPv6len {
+		return m.ipv6.Contains(ip)
+	}
+	return false
+}
+
+func (m *CIDRListMatcher) Apply(ctx context.Context) bool {
+	var dest v2net.Destination
+	if m.onSource {
+		dest = proxy.SourceFromContext(ctx)
+	} else {
+		dest = proxy.DestinationFromContext(ctx)
+		if resolvedIPs, ok := proxy.ResolvedIPsFromContext(ctx); ok {
+			for _, ip := range resolvedIPs {
+				if m.Contains(ip.IP()) {
+					return true
+				}
+			}
+		}
+	}
+
+	if dest.IsValid() && (dest.Address.Family().IsIPv4() || dest.Address.Family().IsIPv6()) {
+		return m.Contains(dest.Address.IP())
+	}
+	return false
+}
+
 type PortMatcher struct {
 	port v2net.PortRange
 }
@@ -248,6 +501,83 @@ func (v *UserMatcher) Apply(ctx context.Context) bool {
 	return false
 }
 
+type UserLevelMatcher struct {
+	ranges []*UserLevelRange
+}
+
+func NewUserLevelMatcher(ranges []*UserLevelRange) *UserLevelMatcher {
+	return &UserLevelMatcher{
+		ranges: ranges,
+	}
+}
+
+func (v *UserLevelMatcher) Apply(ctx context.Context) bool {
+	user := protocol.UserFromContext(ctx)
+	if user == nil {
+		return false
+	}
+	for _, r := range v.ranges {
+		if user.Level >= r.From && user.Level <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+type attributeCondition struct {
+	key    string
+	value  string
+	regexp *regexp.Regexp
+}
+
+// AttributeMatcher matches attributes of the connection set by inbound handlers. All attributes must match.
+type AttributeMatcher struct {
+	conditions []attributeCondition
+}
+
+func NewAttributeMatcher(attributes []*Attribute) (*AttributeMatcher, error) {
+	m := &AttributeMatcher{
+		conditions: make([]attributeCondition, 0, len(attributes)),
+	}
+	for _, attribute := range attributes {
+		cond := attributeCondition{
+			key:   attribute.Key,
+			value: attribute.Value,
+		}
+		switch attribute.Type {
+		case Attribute_Equal:
+		case Attribute_Regex:
+			r, err := regexp.Compile(attribute.Value)
+			if err != nil {
+				return nil, err
+			}
+			cond.regexp = r
+		default:
+			return nil, errors.New("Router: Unknown attribute matching type: ", attribute.Type)
+		}
+		m.conditions = append(m.conditions, cond)
+	}
+	return m, nil
+}
+
+func (v *AttributeMatcher) Apply(ctx context.Context) bool {
+	attributes := proxy.AttributesFromContext(ctx)
+	for _, cond := range v.conditions {
+		value, found := attributes[cond.key]
+		if !found {
+			return false
+		}
+		if cond.regexp != nil {
+			if !cond.regexp.MatchString(value) {
+				return false
+			}
+		} else if value != cond.value {
+			return false
+		}
+	}
+	return true
+}
+
 type InboundTagMatcher struct {
 	tags []string
 }
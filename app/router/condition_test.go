@@ -0,0 +1,235 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/tools/geoip"
+)
+
+func TestDomainMatcher(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewDomainMatcher([]*Domain{
+		{Type: Domain_Subdomain, Value: "v2ray.com"},
+		{Type: Domain_Subdomain, Value: "cn"},
+		{Type: Domain_Full, Value: "www.Google.com"},
+		{Type: Domain_Plain, Value: "facebook"},
+		{Type: Domain_Plain, Value: "YouTube"},
+		{Type: Domain_Regex, Value: "^ad[0-9]+\\."},
+	})
+	assert.Error(err).IsNil()
+
+	testCases := []struct {
+		domain string
+		match  bool
+	}{
+		{"v2ray.com", true},
+		{"www.v2ray.com", true},
+		{"WWW.V2Ray.com.", true},
+		{"notv2ray.com", false},
+		{"v2ray.com.evil", false},
+		{"com", false},
+		{"www.12306.cn", true},
+		{"cn.com", false},
+		{"www.google.com", true},
+		{"mail.google.com", false},
+		{"google.com", false},
+		{"www.facebook.net", true},
+		{"www.FaceBook.net", true},
+		{"m.youtube.com", true},
+		{"ad1.example.com", true},
+		{"bad1.example.com", false},
+		{"", false},
+	}
+	for _, testCase := range testCases {
+		if matcher.MatchDomain(testCase.domain) != testCase.match {
+			t.Error("unexpected result for ", testCase.domain, ": expected ", testCase.match)
+		}
+	}
+}
+
+func TestDomainMatcherInvalidRegex(t *testing.T) {
+	assert := assert.On(t)
+
+	_, err := NewDomainMatcher([]*Domain{
+		{Type: Domain_Regex, Value: "("},
+	})
+	assert.Error(err).IsNotNil()
+}
+
+func TestCIDRListMatcher(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewCIDRListMatcher([]*CIDR{
+		{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+		{Ip: []byte{10, 1, 0, 0}, Prefix: 16},
+		{Ip: []byte{192, 168, 1, 1}, Prefix: 32},
+		{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 32},
+	}, false)
+	assert.Error(err).IsNil()
+
+	testCases := []struct {
+		ip    string
+		match bool
+	}{
+		{"10.0.0.1", true},
+		{"10.1.2.3", true},
+		{"11.0.0.1", false},
+		{"192.168.1.1", true},
+		{"192.168.1.2", false},
+		{"2001:db8:1::1", true},
+		{"2001:db9::1", false},
+		{"::ffff:10.0.0.1", true},
+	}
+	for _, testCase := range testCases {
+		ctx := proxy.ContextWithDestination(context.Background(), net.TCPDestination(net.ParseAddress(testCase.ip), 80))
+		if matcher.Apply(ctx) != testCase.match {
+			t.Error("unexpected result for ", testCase.ip, ": expected ", testCase.match)
+		}
+	}
+
+	ctx := proxy.ContextWithDestination(context.Background(), net.TCPDestination(net.DomainAddress("v2ray.com"), 80))
+	assert.Bool(matcher.Apply(ctx)).IsFalse()
+	assert.Bool(matcher.Apply(proxy.ContextWithResolveIPs(ctx, []net.Address{net.ParseAddress("10.0.0.1")}))).IsTrue()
+}
+
+func TestCIDRListMatcherOnSource(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewCIDRListMatcher([]*CIDR{
+		{Ip: []byte{127, 0, 0, 0}, Prefix: 8},
+	}, true)
+	assert.Error(err).IsNil()
+
+	ctx := proxy.ContextWithDestination(context.Background(), net.TCPDestination(net.ParseAddress("127.0.0.1"), 80))
+	assert.Bool(matcher.Apply(ctx)).IsFalse()
+	ctx = proxy.ContextWithSource(ctx, net.TCPDestination(net.ParseAddress("127.0.0.2"), 10000))
+	assert.Bool(matcher.Apply(ctx)).IsTrue()
+}
+
+func TestCIDRListMatcherInvalidPrefix(t *testing.T) {
+	assert := assert.On(t)
+
+	_, err := NewCIDRListMatcher([]*CIDR{
+		{Ip: []byte{127, 0, 0, 0}, Prefix: 33},
+	}, false)
+	assert.Error(err).IsNotNil()
+}
+
+func loadChinaIPs(b *testing.B) []*CIDR {
+	var chinaIPs geoip.CountryIPRange
+	if err := proto.Unmarshal(geoip.ChinaIPs, &chinaIPs); err != nil {
+		b.Fatal(err)
+	}
+	return chinaIPs.Ips
+}
+
+// generateIPv6CIDRs returns count networks of /48 in 2001::/16.
+func generateIPv6CIDRs(count int) []*CIDR {
+	cidrs := make([]*CIDR, count)
+	for idx := range cidrs {
+		ip := make([]byte, 16)
+		ip[0], ip[1] = 0x20, 0x01
+		ip[2], ip[3] = byte(idx>>8), byte(idx)
+		ip[4] = byte(idx * 7)
+		cidrs[idx] = &CIDR{Ip: ip, Prefix: 48}
+	}
+	return cidrs
+}
+
+func benchmarkCondition(b *testing.B, cond Condition, ip string) {
+	ctx := proxy.ContextWithDestination(context.Background(), net.TCPDestination(net.ParseAddress(ip), 80))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cond.Apply(ctx)
+	}
+}
+
+func BenchmarkCIDRListMatcherIPv4(b *testing.B) {
+	matcher, err := NewCIDRListMatcher(loadChinaIPs(b), false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkCondition(b, matcher, "8.8.8.8")
+}
+
+func BenchmarkIPv4Matcher(b *testing.B) {
+	ipNet := net.NewIPNet()
+	for _, cidr := range loadChinaIPs(b) {
+		ipNet.AddIP(cidr.Ip, byte(cidr.Prefix))
+	}
+	benchmarkCondition(b, NewIPv4Matcher(ipNet, false), "8.8.8.8")
+}
+
+func BenchmarkCIDRListMatcherIPv6(b *testing.B) {
+	matcher, err := NewCIDRListMatcher(generateIPv6CIDRs(4096), false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkCondition(b, matcher, "2400:cb00::1")
+}
+
+func BenchmarkCIDRMatcherIPv6(b *testing.B) {
+	cond := NewAnyCondition()
+	for _, cidr := range generateIPv6CIDRs(4096) {
+		matcher, err := NewCIDRMatcher(cidr.Ip, cidr.Prefix, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		cond.Add(matcher)
+	}
+	benchmarkCondition(b, cond, "2400:cb00::1")
+}
+
+func TestUserLevelMatcher(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher := NewUserLevelMatcher([]*UserLevelRange{
+		{From: 1, To: 3},
+		{From: 10, To: 10},
+	})
+
+	assert.Bool(matcher.Apply(context.Background())).IsFalse()
+	for level, match := range map[uint32]bool{0: false, 1: true, 3: true, 4: false, 10: true, 11: false} {
+		ctx := protocol.ContextWithUser(context.Background(), &protocol.User{Level: level})
+		if matcher.Apply(ctx) != match {
+			t.Error("unexpected result for level ", level, ": expected ", match)
+		}
+	}
+}
+
+func TestAttributeMatcher(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewAttributeMatcher([]*Attribute{
+		{Key: "http.method", Type: Attribute_Equal, Value: "GET"},
+		{Key: "http.path", Type: Attribute_Regex, Value: "^/api/"},
+	})
+	assert.Error(err).IsNil()
+
+	assert.Bool(matcher.Apply(context.Background())).IsFalse()
+	ctx := proxy.ContextWithAttributes(context.Background(), map[string]string{
+		"http.method": "GET",
+	})
+	assert.Bool(matcher.Apply(ctx)).IsFalse()
+	ctx = proxy.ContextWithAttributes(ctx, map[string]string{
+		"http.path": "/api/v1",
+	})
+	assert.Bool(matcher.Apply(ctx)).IsTrue()
+	ctx = proxy.ContextWithAttributes(ctx, map[string]string{
+		"http.method": "POST",
+	})
+	assert.Bool(matcher.Apply(ctx)).IsFalse()
+
+	_, err = NewAttributeMatcher([]*Attribute{
+		{Key: "http.path", Type: Attribute_Regex, Value: "("},
+	})
+	assert.Error(err).IsNotNil()
+}
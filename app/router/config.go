@@ -2,71 +2,48 @@ package router
 
 import (
 	"context"
-	"net"
+	"sync/atomic"
 
 	"v2ray.com/core/common/errors"
-	v2net "v2ray.com/core/common/net"
 )
 
 type Rule struct {
-	Tag       string
-	Condition Condition
+	Tag         string
+	BalancerTag string
+	RuleTag     string
+	Condition   Condition
+
+	// config is the rule config before GeoIP and GeoSite entries are expanded.
+	config *RoutingRule
+	hits   *int64
 }
 
 func (v *Rule) Apply(ctx context.Context) bool {
 	return v.Condition.Apply(ctx)
 }
 
+// Hits returns the number of connections routed by this rule.
+func (v *Rule) Hits() int64 {
+	return atomic.LoadInt64(v.hits)
+}
+
 func (v *RoutingRule) BuildCondition() (Condition, error) {
 	conds := NewConditionChan()
 
 	if len(v.Domain) > 0 {
-		anyCond := NewAnyCondition()
-		for _, domain := range v.Domain {
-			if domain.Type == Domain_Plain {
-				anyCond.Add(NewPlainDomainMatcher(domain.Value))
-			} else {
-				matcher, err := NewRegexpDomainMatcher(domain.Value)
-				if err != nil {
-					return nil, err
-				}
-				anyCond.Add(matcher)
-			}
+		matcher, err := NewDomainMatcher(v.Domain)
+		if err != nil {
+			return nil, err
 		}
-		conds.Add(anyCond)
+		conds.Add(matcher)
 	}
 
 	if len(v.Cidr) > 0 {
-		ipv4Net := v2net.NewIPNet()
-		ipv6Cond := NewAnyCondition()
-		hasIpv6 := false
-
-		for _, ip := range v.Cidr {
-			switch len(ip.Ip) {
-			case net.IPv4len:
-				ipv4Net.AddIP(ip.Ip, byte(ip.Prefix))
-			case net.IPv6len:
-				hasIpv6 = true
-				matcher, err := NewCIDRMatcher(ip.Ip, ip.Prefix, false)
-				if err != nil {
-					return nil, err
-				}
-				ipv6Cond.Add(matcher)
-			default:
-				return nil, errors.New("Router: Invalid IP length.")
-			}
-		}
-
-		if !ipv4Net.IsEmpty() && hasIpv6 {
-			cond := NewAnyCondition()
-			cond.Add(NewIPv4Matcher(ipv4Net, false))
-			cond.Add(ipv6Cond)
-			conds.Add(cond)
-		} else if !ipv4Net.IsEmpty() {
-			conds.Add(NewIPv4Matcher(ipv4Net, false))
-		} else if hasIpv6 {
-			conds.Add(ipv6Cond)
+		matcher, err := NewCIDRListMatcher(v.Cidr, false)
+		if err != nil {
+			return nil, err
 		}
+		conds.Add(matcher)
 	}
 
 	if v.PortRange != nil {
@@ -78,36 +55,11 @@ func (v *RoutingRule) BuildCondition() (Condition, error) {
 	}
 
 	if len(v.SourceCidr) > 0 {
-		ipv4Net := v2net.NewIPNet()
-		ipv6Cond := NewAnyCondition()
-		hasIpv6 := false
-
-		for _, ip := range v.SourceCidr {
-			switch len(ip.Ip) {
-			case net.IPv4len:
-				ipv4Net.AddIP(ip.Ip, byte(ip.Prefix))
-			case net.IPv6len:
-				hasIpv6 = true
-				matcher, err := NewCIDRMatcher(ip.Ip, ip.Prefix, true)
-				if err != nil {
-					return nil, err
-				}
-				ipv6Cond.Add(matcher)
-			default:
-				return nil, errors.New("Router: Invalid IP length.")
-			}
-		}
-
-		if !ipv4Net.IsEmpty() && hasIpv6 {
-			cond := NewAnyCondition()
-			cond.Add(NewIPv4Matcher(ipv4Net, true))
-			cond.Add(ipv6Cond)
-			conds.Add(cond)
-		} else if !ipv4Net.IsEmpty() {
-			conds.Add(NewIPv4Matcher(ipv4Net, true))
-		} else if hasIpv6 {
-			conds.Add(ipv6Cond)
+		matcher, err := NewCIDRListMatcher(v.SourceCidr, true)
+		if err != nil {
+			return nil, err
 		}
+		conds.Add(matcher)
 	}
 
 	if len(v.UserEmail) > 0 {
@@ -118,6 +70,18 @@ func (v *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(NewInboundTagMatcher(v.InboundTag))
 	}
 
+	if len(v.UserLevel) > 0 {
+		conds.Add(NewUserLevelMatcher(v.UserLevel))
+	}
+
+	if len(v.Attribute) > 0 {
+		matcher, err := NewAttributeMatcher(v.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(matcher)
+	}
+
 	if conds.Len() == 0 {
 		return nil, errors.New("Router: This rule has no effective fields.")
 	}
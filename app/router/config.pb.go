@@ -5,6 +5,8 @@ import fmt "fmt"
 import math "math"
 import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_net1 "v2ray.com/core/common/net"
+import v2ray_core_common_net2 "v2ray.com/core/common/net"
+import v2ray_core_common_net3 "v2ray.com/core/common/net"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -21,19 +23,27 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type Domain_Type int32
 
 const (
-	// The value is used as is.
+	// The value is used as is. A domain matches if it contains the value.
 	Domain_Plain Domain_Type = 0
 	// The value is used as a regular expression.
 	Domain_Regex Domain_Type = 1
+	// The value is a domain. A domain matches if it is the value or a subdomain of the value.
+	Domain_Subdomain Domain_Type = 2
+	// The value is a domain. A domain matches only if it is exactly the value.
+	Domain_Full Domain_Type = 3
 )
 
 var Domain_Type_name = map[int32]string{
 	0: "Plain",
 	1: "Regex",
+	2: "Subdomain",
+	3: "Full",
 }
 var Domain_Type_value = map[string]int32{
-	"Plain": 0,
-	"Regex": 1,
+	"Plain":     0,
+	"Regex":     1,
+	"Subdomain": 2,
+	"Full":      3,
 }
 
 func (x Domain_Type) String() string {
@@ -41,6 +51,60 @@ func (x Domain_Type) String() string {
 }
 func (Domain_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
 
+type Attribute_Type int32
+
+const (
+	// The attribute equals the value.
+	Attribute_Equal Attribute_Type = 0
+	// The attribute matches the value as a regular expression.
+	Attribute_Regex Attribute_Type = 1
+)
+
+var Attribute_Type_name = map[int32]string{
+	0: "Equal",
+	1: "Regex",
+}
+var Attribute_Type_value = map[string]int32{
+	"Equal": 0,
+	"Regex": 1,
+}
+
+func (x Attribute_Type) String() string {
+	return proto.EnumName(Attribute_Type_name, int32(x))
+}
+func (Attribute_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{4, 0} }
+
+type BalancingRule_Strategy int32
+
+const (
+	// Picks a random handler.
+	BalancingRule_Random BalancingRule_Strategy = 0
+	// Picks handlers in turn.
+	BalancingRule_RoundRobin BalancingRule_Strategy = 1
+	// Picks the handler with the least active connections.
+	BalancingRule_LeastConn BalancingRule_Strategy = 2
+	// Picks the handler with the lowest observed latency.
+	BalancingRule_LeastLatency BalancingRule_Strategy = 3
+)
+
+var BalancingRule_Strategy_name = map[int32]string{
+	0: "Random",
+	1: "RoundRobin",
+	2: "LeastConn",
+	3: "LeastLatency",
+}
+var BalancingRule_Strategy_value = map[string]int32{
+	"Random":       0,
+	"RoundRobin":   1,
+	"LeastConn":    2,
+	"LeastLatency": 3,
+}
+
+func (x BalancingRule_Strategy) String() string {
+	return proto.EnumName(BalancingRule_Strategy_name, int32(x))
+}
+func (BalancingRule_Strategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{9, 0} }
+
 type Config_DomainStrategy int32
 
 const (
@@ -66,7 +130,7 @@ var Config_DomainStrategy_value = map[string]int32{
 func (x Config_DomainStrategy) String() string {
 	return proto.EnumName(Config_DomainStrategy_name, int32(x))
 }
-func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3, 0} }
+func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{10, 0} }
 
 // Domain for routing decision.
 type Domain struct {
@@ -123,6 +187,7 @@ func (m *CIDR) GetPrefix() uint32 {
 }
 
 type RoutingRule struct {
+	// Tag of the outbound handler for matching connections. Either this or balancing_tag must be set.
 	Tag         string                              `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
 	Domain      []*Domain                           `protobuf:"bytes,2,rep,name=domain" json:"domain,omitempty"`
 	Cidr        []*CIDR                             `protobuf:"bytes,3,rep,name=cidr" json:"cidr,omitempty"`
@@ -131,6 +196,18 @@ type RoutingRule struct {
 	SourceCidr  []*CIDR                             `protobuf:"bytes,6,rep,name=source_cidr,json=sourceCidr" json:"source_cidr,omitempty"`
 	UserEmail   []string                            `protobuf:"bytes,7,rep,name=user_email,json=userEmail" json:"user_email,omitempty"`
 	InboundTag  []string                            `protobuf:"bytes,8,rep,name=inbound_tag,json=inboundTag" json:"inbound_tag,omitempty"`
+	// Tag of the balancer that picks an outbound handler for matching connections.
+	BalancingTag string `protobuf:"bytes,9,opt,name=balancing_tag,json=balancingTag" json:"balancing_tag,omitempty"`
+	// Country codes of GeoIP entries. IP ranges of the entries are loaded from the GeoIP file and added to cidr.
+	Geoip []string `protobuf:"bytes,10,rep,name=geoip" json:"geoip,omitempty"`
+	// Names of GeoSite entries. Domains of the entries are loaded from the GeoSite file and added to domain.
+	Geosite []string `protobuf:"bytes,11,rep,name=geosite" json:"geosite,omitempty"`
+	// Tag of this rule, for referring to it at runtime. Tags of rules in a config must be unique if not empty.
+	RuleTag string `protobuf:"bytes,12,opt,name=rule_tag,json=ruleTag" json:"rule_tag,omitempty"`
+	// Connections of users whose level is in any of the ranges match.
+	UserLevel []*UserLevelRange `protobuf:"bytes,13,rep,name=user_level,json=userLevel" json:"user_level,omitempty"`
+	// Connections match if all of the attribute matchers match.
+	Attribute []*Attribute `protobuf:"bytes,14,rep,name=attribute" json:"attribute,omitempty"`
 }
 
 func (m *RoutingRule) Reset()                    { *m = RoutingRule{} }
@@ -194,15 +271,261 @@ func (m *RoutingRule) GetInboundTag() []string {
 	return nil
 }
 
+func (m *RoutingRule) GetBalancingTag() string {
+	if m != nil {
+		return m.BalancingTag
+	}
+	return ""
+}
+
+func (m *RoutingRule) GetGeoip() []string {
+	if m != nil {
+		return m.Geoip
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetGeosite() []string {
+	if m != nil {
+		return m.Geosite
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+func (m *RoutingRule) GetUserLevel() []*UserLevelRange {
+	if m != nil {
+		return m.UserLevel
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetAttribute() []*Attribute {
+	if m != nil {
+		return m.Attribute
+	}
+	return nil
+}
+
+// UserLevelRange is an inclusive range of user levels.
+type UserLevelRange struct {
+	From uint32 `protobuf:"varint,1,opt,name=from" json:"from,omitempty"`
+	To   uint32 `protobuf:"varint,2,opt,name=to" json:"to,omitempty"`
+}
+
+func (m *UserLevelRange) Reset()                    { *m = UserLevelRange{} }
+func (m *UserLevelRange) String() string            { return proto.CompactTextString(m) }
+func (*UserLevelRange) ProtoMessage()               {}
+func (*UserLevelRange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *UserLevelRange) GetFrom() uint32 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *UserLevelRange) GetTo() uint32 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+// Attribute matches an attribute of the connection, which is set by the inbound handler.
+type Attribute struct {
+	// Name of the attribute, such as "http.method", "http.path" or "socks.username".
+	Key   string         `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Type  Attribute_Type `protobuf:"varint,2,opt,name=type,enum=v2ray.core.app.router.Attribute_Type" json:"type,omitempty"`
+	Value string         `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Attribute) Reset()                    { *m = Attribute{} }
+func (m *Attribute) String() string            { return proto.CompactTextString(m) }
+func (*Attribute) ProtoMessage()               {}
+func (*Attribute) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *Attribute) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Attribute) GetType() Attribute_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Attribute_Equal
+}
+
+func (m *Attribute) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// GeoIP is the list of IP ranges of a country.
+type GeoIP struct {
+	// Country code, such as "cn". Case-insensitive.
+	CountryCode string  `protobuf:"bytes,1,opt,name=country_code,json=countryCode" json:"country_code,omitempty"`
+	Cidr        []*CIDR `protobuf:"bytes,2,rep,name=cidr" json:"cidr,omitempty"`
+}
+
+func (m *GeoIP) Reset()                    { *m = GeoIP{} }
+func (m *GeoIP) String() string            { return proto.CompactTextString(m) }
+func (*GeoIP) ProtoMessage()               {}
+func (*GeoIP) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *GeoIP) GetCountryCode() string {
+	if m != nil {
+		return m.CountryCode
+	}
+	return ""
+}
+
+func (m *GeoIP) GetCidr() []*CIDR {
+	if m != nil {
+		return m.Cidr
+	}
+	return nil
+}
+
+// GeoIPList is the content of a GeoIP file.
+type GeoIPList struct {
+	Entry []*GeoIP `protobuf:"bytes,1,rep,name=entry" json:"entry,omitempty"`
+	// Version of the data, such as the date it was generated. Informational only.
+	Version string `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *GeoIPList) Reset()                    { *m = GeoIPList{} }
+func (m *GeoIPList) String() string            { return proto.CompactTextString(m) }
+func (*GeoIPList) ProtoMessage()               {}
+func (*GeoIPList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *GeoIPList) GetEntry() []*GeoIP {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *GeoIPList) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+// GeoSite is a named list of domains, such as all domains of a site or of sites in a country.
+type GeoSite struct {
+	// Name of the list, such as "cn" or "google". Case-insensitive.
+	Name   string    `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Domain []*Domain `protobuf:"bytes,2,rep,name=domain" json:"domain,omitempty"`
+}
+
+func (m *GeoSite) Reset()                    { *m = GeoSite{} }
+func (m *GeoSite) String() string            { return proto.CompactTextString(m) }
+func (*GeoSite) ProtoMessage()               {}
+func (*GeoSite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *GeoSite) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GeoSite) GetDomain() []*Domain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+// GeoSiteList is the content of a GeoSite file.
+type GeoSiteList struct {
+	Entry []*GeoSite `protobuf:"bytes,1,rep,name=entry" json:"entry,omitempty"`
+	// Version of the data, such as the date it was generated. Informational only.
+	Version string `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *GeoSiteList) Reset()                    { *m = GeoSiteList{} }
+func (m *GeoSiteList) String() string            { return proto.CompactTextString(m) }
+func (*GeoSiteList) ProtoMessage()               {}
+func (*GeoSiteList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *GeoSiteList) GetEntry() []*GeoSite {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *GeoSiteList) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+// BalancingRule defines a group of outbound handlers, from which one is picked for each connection.
+type BalancingRule struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+	// Outbound handlers whose tags start with any of the selectors are in this group.
+	OutboundSelector []string               `protobuf:"bytes,2,rep,name=outbound_selector,json=outboundSelector" json:"outbound_selector,omitempty"`
+	Strategy         BalancingRule_Strategy `protobuf:"varint,3,opt,name=strategy,enum=v2ray.core.app.router.BalancingRule_Strategy" json:"strategy,omitempty"`
+}
+
+func (m *BalancingRule) Reset()                    { *m = BalancingRule{} }
+func (m *BalancingRule) String() string            { return proto.CompactTextString(m) }
+func (*BalancingRule) ProtoMessage()               {}
+func (*BalancingRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *BalancingRule) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *BalancingRule) GetOutboundSelector() []string {
+	if m != nil {
+		return m.OutboundSelector
+	}
+	return nil
+}
+
+func (m *BalancingRule) GetStrategy() BalancingRule_Strategy {
+	if m != nil {
+		return m.Strategy
+	}
+	return BalancingRule_Random
+}
+
 type Config struct {
 	DomainStrategy Config_DomainStrategy `protobuf:"varint,1,opt,name=domain_strategy,json=domainStrategy,enum=v2ray.core.app.router.Config_DomainStrategy" json:"domain_strategy,omitempty"`
 	Rule           []*RoutingRule        `protobuf:"bytes,2,rep,name=rule" json:"rule,omitempty"`
+	BalancingRule  []*BalancingRule      `protobuf:"bytes,3,rep,name=balancing_rule,json=balancingRule" json:"balancing_rule,omitempty"`
+	// Path of the GeoIP file, which is a serialized GeoIPList. Defaults to "geoip.dat" in the directory of the
+	// executable. The file is read only if any rule refers to GeoIP entries.
+	GeoipFile string `protobuf:"bytes,4,opt,name=geoip_file,json=geoipFile" json:"geoip_file,omitempty"`
+	// Path of the GeoSite file, which is a serialized GeoSiteList. Defaults to "geosite.dat" in the directory of the
+	// executable. The file is read only if any rule refers to GeoSite entries.
+	GeositeFile string `protobuf:"bytes,5,opt,name=geosite_file,json=geositeFile" json:"geosite_file,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *Config) GetDomainStrategy() Config_DomainStrategy {
 	if m != nil {
@@ -218,51 +541,296 @@ func (m *Config) GetRule() []*RoutingRule {
 	return nil
 }
 
+func (m *Config) GetBalancingRule() []*BalancingRule {
+	if m != nil {
+		return m.BalancingRule
+	}
+	return nil
+}
+
+func (m *Config) GetGeoipFile() string {
+	if m != nil {
+		return m.GeoipFile
+	}
+	return ""
+}
+
+func (m *Config) GetGeositeFile() string {
+	if m != nil {
+		return m.GeositeFile
+	}
+	return ""
+}
+
+// RouteRequest describes a synthetic connection for testing routing decisions.
+type RouteRequest struct {
+	// Destination of the connection. Network defaults to TCP.
+	Destination *v2ray_core_common_net3.Endpoint `protobuf:"bytes,1,opt,name=destination" json:"destination,omitempty"`
+	Source      *v2ray_core_common_net3.Endpoint `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
+	InboundTag  string                           `protobuf:"bytes,3,opt,name=inbound_tag,json=inboundTag" json:"inbound_tag,omitempty"`
+	UserEmail   string                           `protobuf:"bytes,4,opt,name=user_email,json=userEmail" json:"user_email,omitempty"`
+	UserLevel   uint32                           `protobuf:"varint,5,opt,name=user_level,json=userLevel" json:"user_level,omitempty"`
+	// Attributes of the connection, as set by inbound handlers.
+	Attribute map[string]string `protobuf:"bytes,6,rep,name=attribute" json:"attribute,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Whether the connection has a user. It is implied if user_email or user_level is set, and allows a user with an
+	// empty email and level 0.
+	HasUser bool `protobuf:"varint,7,opt,name=has_user,json=hasUser" json:"has_user,omitempty"`
+}
+
+func (m *RouteRequest) Reset()                    { *m = RouteRequest{} }
+func (m *RouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*RouteRequest) ProtoMessage()               {}
+func (*RouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *RouteRequest) GetDestination() *v2ray_core_common_net3.Endpoint {
+	if m != nil {
+		return m.Destination
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetSource() *v2ray_core_common_net3.Endpoint {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetInboundTag() string {
+	if m != nil {
+		return m.InboundTag
+	}
+	return ""
+}
+
+func (m *RouteRequest) GetUserEmail() string {
+	if m != nil {
+		return m.UserEmail
+	}
+	return ""
+}
+
+func (m *RouteRequest) GetUserLevel() uint32 {
+	if m != nil {
+		return m.UserLevel
+	}
+	return 0
+}
+
+func (m *RouteRequest) GetAttribute() map[string]string {
+	if m != nil {
+		return m.Attribute
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetHasUser() bool {
+	if m != nil {
+		return m.HasUser
+	}
+	return false
+}
+
+// RouteExplanation describes how the router made a routing decision.
+type RouteExplanation struct {
+	// Index of the matched rule in the config, or -1 if no rule matched.
+	RuleIndex int32 `protobuf:"varint,1,opt,name=rule_index,json=ruleIndex" json:"rule_index,omitempty"`
+	// Fields of the matched rule that the connection satisfied, such as "domain" or "port".
+	Condition []string `protobuf:"bytes,2,rep,name=condition" json:"condition,omitempty"`
+	// Tag of the chosen outbound handler.
+	OutboundTag string `protobuf:"bytes,3,opt,name=outbound_tag,json=outboundTag" json:"outbound_tag,omitempty"`
+	// Tag of the balancer that chose the outbound handler, if the matched rule targets a balancer.
+	BalancingTag string `protobuf:"bytes,4,opt,name=balancing_tag,json=balancingTag" json:"balancing_tag,omitempty"`
+	// Indices of rules skipped because their outbound handlers failed the latest probes.
+	SkippedRule []int32 `protobuf:"varint,5,rep,packed,name=skipped_rule,json=skippedRule" json:"skipped_rule,omitempty"`
+	// Whether the destination domain was resolved to IPs, because no rule matched the domain under IpIfNonMatch.
+	Resolved   bool                                 `protobuf:"varint,6,opt,name=resolved" json:"resolved,omitempty"`
+	ResolvedIp []*v2ray_core_common_net2.IPOrDomain `protobuf:"bytes,7,rep,name=resolved_ip,json=resolvedIp" json:"resolved_ip,omitempty"`
+}
+
+func (m *RouteExplanation) Reset()                    { *m = RouteExplanation{} }
+func (m *RouteExplanation) String() string            { return proto.CompactTextString(m) }
+func (*RouteExplanation) ProtoMessage()               {}
+func (*RouteExplanation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *RouteExplanation) GetRuleIndex() int32 {
+	if m != nil {
+		return m.RuleIndex
+	}
+	return 0
+}
+
+func (m *RouteExplanation) GetCondition() []string {
+	if m != nil {
+		return m.Condition
+	}
+	return nil
+}
+
+func (m *RouteExplanation) GetOutboundTag() string {
+	if m != nil {
+		return m.OutboundTag
+	}
+	return ""
+}
+
+func (m *RouteExplanation) GetBalancingTag() string {
+	if m != nil {
+		return m.BalancingTag
+	}
+	return ""
+}
+
+func (m *RouteExplanation) GetSkippedRule() []int32 {
+	if m != nil {
+		return m.SkippedRule
+	}
+	return nil
+}
+
+func (m *RouteExplanation) GetResolved() bool {
+	if m != nil {
+		return m.Resolved
+	}
+	return false
+}
+
+func (m *RouteExplanation) GetResolvedIp() []*v2ray_core_common_net2.IPOrDomain {
+	if m != nil {
+		return m.ResolvedIp
+	}
+	return nil
+}
+
+// RuleStats is the runtime status of a routing rule.
+type RuleStats struct {
+	Rule *RoutingRule `protobuf:"bytes,1,opt,name=rule" json:"rule,omitempty"`
+	// Number of connections routed by this rule.
+	Hits int64 `protobuf:"varint,2,opt,name=hits" json:"hits,omitempty"`
+}
+
+func (m *RuleStats) Reset()                    { *m = RuleStats{} }
+func (m *RuleStats) String() string            { return proto.CompactTextString(m) }
+func (*RuleStats) ProtoMessage()               {}
+func (*RuleStats) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *RuleStats) GetRule() *RoutingRule {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+func (m *RuleStats) GetHits() int64 {
+	if m != nil {
+		return m.Hits
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Domain)(nil), "v2ray.core.app.router.Domain")
 	proto.RegisterType((*CIDR)(nil), "v2ray.core.app.router.CIDR")
 	proto.RegisterType((*RoutingRule)(nil), "v2ray.core.app.router.RoutingRule")
+	proto.RegisterType((*UserLevelRange)(nil), "v2ray.core.app.router.UserLevelRange")
+	proto.RegisterType((*Attribute)(nil), "v2ray.core.app.router.Attribute")
+	proto.RegisterType((*GeoIP)(nil), "v2ray.core.app.router.GeoIP")
+	proto.RegisterType((*GeoIPList)(nil), "v2ray.core.app.router.GeoIPList")
+	proto.RegisterType((*GeoSite)(nil), "v2ray.core.app.router.GeoSite")
+	proto.RegisterType((*GeoSiteList)(nil), "v2ray.core.app.router.GeoSiteList")
+	proto.RegisterType((*BalancingRule)(nil), "v2ray.core.app.router.BalancingRule")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.router.Config")
+	proto.RegisterType((*RouteRequest)(nil), "v2ray.core.app.router.RouteRequest")
+	proto.RegisterType((*RouteExplanation)(nil), "v2ray.core.app.router.RouteExplanation")
+	proto.RegisterType((*RuleStats)(nil), "v2ray.core.app.router.RuleStats")
 	proto.RegisterEnum("v2ray.core.app.router.Domain_Type", Domain_Type_name, Domain_Type_value)
+	proto.RegisterEnum("v2ray.core.app.router.Attribute_Type", Attribute_Type_name, Attribute_Type_value)
+	proto.RegisterEnum("v2ray.core.app.router.BalancingRule_Strategy", BalancingRule_Strategy_name, BalancingRule_Strategy_value)
 	proto.RegisterEnum("v2ray.core.app.router.Config_DomainStrategy", Config_DomainStrategy_name, Config_DomainStrategy_value)
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/app/router/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 538 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x93, 0xd1, 0x6e, 0xd3, 0x3e,
-	0x14, 0xc6, 0xff, 0x69, 0xb3, 0xfc, 0x97, 0x93, 0x51, 0x22, 0x8b, 0xa1, 0x30, 0x98, 0x88, 0x22,
-	0x04, 0xbd, 0x40, 0x89, 0x54, 0x04, 0xdc, 0x20, 0xa1, 0xad, 0x9b, 0x44, 0x25, 0x98, 0x26, 0xb3,
-	0x71, 0xc1, 0x4d, 0xe4, 0xa5, 0x5e, 0xb0, 0x48, 0x6c, 0xcb, 0x71, 0xc6, 0xfa, 0x10, 0xbc, 0x08,
-	0x4f, 0xc3, 0x23, 0x21, 0xdb, 0x99, 0xd8, 0xd0, 0x0a, 0x77, 0x3e, 0xa7, 0xbf, 0xef, 0xf8, 0xeb,
-	0xf1, 0x17, 0x78, 0x7a, 0x31, 0x53, 0x64, 0x95, 0x57, 0xa2, 0x2d, 0x2a, 0xa1, 0x68, 0x41, 0xa4,
-	0x2c, 0x94, 0xe8, 0x35, 0x55, 0x45, 0x25, 0xf8, 0x39, 0xab, 0x73, 0xa9, 0x84, 0x16, 0x68, 0xfb,
-	0x8a, 0x53, 0x34, 0x27, 0x52, 0xe6, 0x8e, 0xd9, 0x79, 0xf2, 0x87, 0xbc, 0x12, 0x6d, 0x2b, 0x78,
-	0xc1, 0xa9, 0x2e, 0xa4, 0x50, 0xda, 0x89, 0x77, 0x9e, 0xad, 0xa7, 0x38, 0xd5, 0xdf, 0x84, 0xfa,
-	0xea, 0xc0, 0x4c, 0x43, 0x70, 0x20, 0x5a, 0xc2, 0x38, 0x7a, 0x05, 0xbe, 0x5e, 0x49, 0x9a, 0x78,
-	0xa9, 0x37, 0x9d, 0xcc, 0xb2, 0xfc, 0xd6, 0xeb, 0x73, 0x07, 0xe7, 0x27, 0x2b, 0x49, 0xb1, 0xe5,
-	0xd1, 0x3d, 0xd8, 0xb8, 0x20, 0x4d, 0x4f, 0x93, 0x51, 0xea, 0x4d, 0x43, 0xec, 0x8a, 0xec, 0x11,
-	0xf8, 0x86, 0x41, 0x21, 0x6c, 0x1c, 0x37, 0x84, 0xf1, 0xf8, 0x3f, 0x73, 0xc4, 0xb4, 0xa6, 0x97,
-	0xb1, 0x97, 0xe5, 0xe0, 0xcf, 0x17, 0x07, 0x18, 0x4d, 0x60, 0xc4, 0xa4, 0xbd, 0x71, 0x0b, 0x8f,
-	0x98, 0x44, 0xf7, 0x21, 0x90, 0x8a, 0x9e, 0xb3, 0x4b, 0x3b, 0xec, 0x0e, 0x1e, 0xaa, 0xec, 0xfb,
-	0x18, 0x22, 0x2c, 0x7a, 0xcd, 0x78, 0x8d, 0xfb, 0x86, 0xa2, 0x18, 0xc6, 0x9a, 0xd4, 0x56, 0x18,
-	0x62, 0x73, 0x44, 0x2f, 0x21, 0x58, 0x5a, 0x6b, 0xc9, 0x28, 0x1d, 0x4f, 0xa3, 0xd9, 0xee, 0x5f,
-	0xfd, 0xe3, 0x01, 0x46, 0x05, 0xf8, 0x15, 0x5b, 0xaa, 0x64, 0x6c, 0x45, 0x0f, 0xd7, 0x88, 0x8c,
-	0x57, 0x6c, 0x41, 0xf4, 0x16, 0xc0, 0xac, 0xb9, 0x54, 0x84, 0xd7, 0x34, 0xf1, 0x53, 0x6f, 0x1a,
-	0xcd, 0xd2, 0xeb, 0x32, 0xb7, 0xe9, 0x9c, 0x53, 0x9d, 0x1f, 0x0b, 0xa5, 0xb1, 0xe1, 0x70, 0x28,
-	0xaf, 0x8e, 0xe8, 0x10, 0xb6, 0x86, 0x17, 0x28, 0x1b, 0xd6, 0xe9, 0x64, 0xc3, 0x8e, 0xc8, 0xd6,
-	0x8c, 0x38, 0x72, 0xe8, 0x7b, 0xd6, 0x69, 0x1c, 0xf1, 0xdf, 0x05, 0x7a, 0x03, 0x51, 0x27, 0x7a,
-	0x55, 0xd1, 0xd2, 0xfa, 0x0f, 0xfe, 0xed, 0x1f, 0x1c, 0x3f, 0x37, 0xff, 0x62, 0x17, 0xa0, 0xef,
-	0xa8, 0x2a, 0x69, 0x4b, 0x58, 0x93, 0xfc, 0x9f, 0x8e, 0xa7, 0x21, 0x0e, 0x4d, 0xe7, 0xd0, 0x34,
-	0xd0, 0x63, 0x88, 0x18, 0x3f, 0x13, 0x3d, 0x5f, 0x96, 0x66, 0xcd, 0x9b, 0xf6, 0x77, 0x18, 0x5a,
-	0x27, 0xa4, 0xce, 0x7e, 0x7a, 0x10, 0xcc, 0x6d, 0x58, 0xd1, 0x29, 0xdc, 0x75, 0xbb, 0x2c, 0x3b,
-	0xad, 0x88, 0xa6, 0xf5, 0x6a, 0x48, 0xd0, 0xf3, 0x75, 0x66, 0x5c, 0xc8, 0xdd, 0x43, 0x7c, 0x1c,
-	0x34, 0x78, 0xb2, 0xbc, 0x51, 0x9b, 0x34, 0xaa, 0xbe, 0xa1, 0xc3, 0x6b, 0xae, 0x4b, 0xe3, 0xb5,
-	0x4c, 0x60, 0xcb, 0x67, 0xaf, 0x61, 0x72, 0x73, 0x32, 0xda, 0x04, 0x7f, 0xaf, 0x5b, 0x74, 0x2e,
-	0x80, 0xa7, 0x1d, 0x5d, 0xc8, 0xd8, 0x43, 0x31, 0x6c, 0x2d, 0xe4, 0xe2, 0xfc, 0x48, 0xf0, 0x0f,
-	0x44, 0x57, 0x5f, 0xe2, 0xd1, 0xfe, 0x3b, 0x78, 0x50, 0x89, 0xf6, 0xf6, 0x7b, 0xf6, 0x23, 0x67,
-	0xfa, 0xd8, 0x7c, 0x32, 0x9f, 0x03, 0xd7, 0xfc, 0x31, 0xda, 0xfe, 0x34, 0xc3, 0x64, 0x95, 0xcf,
-	0x0d, 0xbc, 0x27, 0xa5, 0x75, 0x43, 0xd5, 0x59, 0x60, 0xbf, 0xac, 0x17, 0xbf, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0x27, 0x56, 0x8a, 0x08, 0xe9, 0x03, 0x00, 0x00,
+	// 1271 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0x5d, 0x6f, 0x1b, 0x45,
+	0x17, 0xae, 0xd7, 0x1f, 0xf1, 0x9e, 0xb5, 0xfd, 0xfa, 0x1d, 0xb5, 0x68, 0x1b, 0x1a, 0xea, 0x2e,
+	0x2d, 0x58, 0x2a, 0xd8, 0x92, 0x69, 0x29, 0xa0, 0x0a, 0x94, 0xa4, 0x69, 0xb1, 0x08, 0x25, 0x9a,
+	0xb4, 0x20, 0x40, 0xc8, 0x1a, 0xef, 0x4e, 0x9c, 0x51, 0xd6, 0x33, 0xdb, 0xd9, 0xd9, 0x10, 0x5f,
+	0x73, 0x0b, 0x77, 0x5c, 0xf1, 0x13, 0xf8, 0x53, 0x48, 0xfc, 0x12, 0x34, 0x33, 0xbb, 0xfe, 0x28,
+	0x76, 0x13, 0x71, 0x37, 0xe7, 0xec, 0x73, 0xce, 0x9c, 0x39, 0x1f, 0xcf, 0x59, 0x78, 0xef, 0x7c,
+	0x20, 0xc9, 0xac, 0x17, 0x8a, 0x69, 0x3f, 0x14, 0x92, 0xf6, 0x49, 0x92, 0xf4, 0xa5, 0xc8, 0x14,
+	0x95, 0xfd, 0x50, 0xf0, 0x13, 0x36, 0xe9, 0x25, 0x52, 0x28, 0x81, 0x6e, 0x14, 0x38, 0x49, 0x7b,
+	0x24, 0x49, 0x7a, 0x16, 0xb3, 0x7d, 0xf7, 0x35, 0xf3, 0x50, 0x4c, 0xa7, 0x82, 0xf7, 0x39, 0x55,
+	0xfd, 0x44, 0x48, 0x65, 0x8d, 0xb7, 0xdf, 0xdf, 0x8c, 0xe2, 0x54, 0xfd, 0x2c, 0xe4, 0xd9, 0xe5,
+	0x40, 0x12, 0x45, 0x92, 0xa6, 0x69, 0x0e, 0xbc, 0xbf, 0x19, 0x18, 0xd1, 0x54, 0x31, 0x4e, 0x14,
+	0x13, 0xdc, 0x82, 0x83, 0xdf, 0x4a, 0x50, 0x7b, 0x22, 0xa6, 0x84, 0x71, 0xf4, 0x31, 0x54, 0xd4,
+	0x2c, 0xa1, 0x7e, 0xa9, 0x53, 0xea, 0xb6, 0x06, 0x41, 0x6f, 0xed, 0xab, 0x7a, 0x16, 0xdc, 0x7b,
+	0x31, 0x4b, 0x28, 0x36, 0x78, 0x74, 0x1d, 0xaa, 0xe7, 0x24, 0xce, 0xa8, 0xef, 0x74, 0x4a, 0x5d,
+	0x17, 0x5b, 0x21, 0x78, 0x08, 0x15, 0x8d, 0x41, 0x2e, 0x54, 0x8f, 0x62, 0xc2, 0x78, 0xfb, 0x9a,
+	0x3e, 0x62, 0x3a, 0xa1, 0x17, 0xed, 0x12, 0x6a, 0x82, 0x7b, 0x9c, 0x8d, 0x23, 0xe3, 0xab, 0xed,
+	0xa0, 0x3a, 0x54, 0x9e, 0x66, 0x71, 0xdc, 0x2e, 0x07, 0x3d, 0xa8, 0xec, 0x0f, 0x9f, 0x60, 0xd4,
+	0x02, 0x87, 0x25, 0x26, 0x94, 0x06, 0x76, 0x58, 0x82, 0xde, 0x82, 0x5a, 0x22, 0xe9, 0x09, 0xbb,
+	0x30, 0xb7, 0x34, 0x71, 0x2e, 0x05, 0xbf, 0x54, 0xc1, 0xc3, 0x22, 0x53, 0x8c, 0x4f, 0x70, 0x16,
+	0x53, 0xd4, 0x86, 0xb2, 0x22, 0x13, 0x63, 0xe8, 0x62, 0x7d, 0x44, 0x0f, 0xa1, 0x66, 0xef, 0xf1,
+	0x9d, 0x4e, 0xb9, 0xeb, 0x0d, 0x76, 0xde, 0xf8, 0x30, 0x9c, 0x83, 0x51, 0x1f, 0x2a, 0x21, 0x8b,
+	0xa4, 0x5f, 0x36, 0x46, 0x6f, 0x6f, 0x30, 0xd2, 0xb1, 0x62, 0x03, 0x44, 0x5f, 0x00, 0xe8, 0xb2,
+	0x8e, 0x24, 0xe1, 0x13, 0xea, 0x57, 0x3a, 0xa5, 0xae, 0x37, 0xe8, 0x2c, 0x9b, 0xd9, 0x3a, 0xf4,
+	0x38, 0x55, 0xbd, 0x23, 0x21, 0x15, 0xd6, 0x38, 0xec, 0x26, 0xc5, 0x11, 0x1d, 0x40, 0x23, 0xaf,
+	0xf8, 0x28, 0x66, 0xa9, 0xf2, 0xab, 0xc6, 0x45, 0xb0, 0xc1, 0xc5, 0x73, 0x0b, 0x3d, 0x64, 0xa9,
+	0xc2, 0x1e, 0x5f, 0x08, 0xe8, 0x31, 0x78, 0xa9, 0xc8, 0x64, 0x48, 0x47, 0x26, 0xfe, 0xda, 0xe5,
+	0xf1, 0x83, 0xc5, 0xef, 0xeb, 0x57, 0xec, 0x00, 0x64, 0x29, 0x95, 0x23, 0x3a, 0x25, 0x2c, 0xf6,
+	0xb7, 0x3a, 0xe5, 0xae, 0x8b, 0x5d, 0xad, 0x39, 0xd0, 0x0a, 0x74, 0x1b, 0x3c, 0xc6, 0xc7, 0x22,
+	0xe3, 0xd1, 0x48, 0xa7, 0xb9, 0x6e, 0xbe, 0x43, 0xae, 0x7a, 0x41, 0x26, 0xe8, 0x5d, 0x68, 0x8e,
+	0x49, 0x4c, 0x78, 0xc8, 0xf8, 0xc4, 0x40, 0x5c, 0x53, 0x89, 0xc6, 0x5c, 0xa9, 0x41, 0xd7, 0xa1,
+	0x3a, 0xa1, 0x82, 0x25, 0x3e, 0x18, 0x7b, 0x2b, 0x20, 0x1f, 0xb6, 0x26, 0x54, 0xa4, 0x4c, 0x51,
+	0xdf, 0x33, 0xfa, 0x42, 0x44, 0x37, 0xa1, 0x2e, 0xb3, 0x98, 0x1a, 0x7f, 0x0d, 0xe3, 0x6f, 0x4b,
+	0xcb, 0xda, 0xd5, 0x93, 0x3c, 0xde, 0x98, 0x9e, 0xd3, 0xd8, 0x6f, 0x9a, 0xc7, 0xde, 0xdb, 0xf0,
+	0xd8, 0x97, 0x29, 0x95, 0x87, 0x1a, 0x97, 0xa7, 0x3e, 0x2b, 0x64, 0xf4, 0x39, 0xb8, 0x44, 0x29,
+	0xc9, 0xc6, 0x99, 0xa2, 0x7e, 0xab, 0x53, 0x7e, 0xbd, 0x74, 0x4b, 0x4e, 0x76, 0x0b, 0x1c, 0x5e,
+	0x98, 0x04, 0x0f, 0xa0, 0xb5, 0xea, 0x1c, 0x21, 0xa8, 0x9c, 0x48, 0x31, 0x35, 0x8d, 0xd8, 0xc4,
+	0xe6, 0xac, 0x7b, 0x5a, 0x89, 0xbc, 0x7f, 0x1d, 0x25, 0x82, 0x5f, 0x4b, 0xe0, 0xce, 0xdd, 0xe9,
+	0xce, 0x3d, 0xa3, 0xb3, 0xa2, 0x73, 0xcf, 0xe8, 0x0c, 0x7d, 0x9a, 0x0f, 0xa4, 0x63, 0x06, 0xf2,
+	0xde, 0x65, 0x01, 0xad, 0x9d, 0xc9, 0xf2, 0xf2, 0x4c, 0xde, 0x5a, 0xcc, 0xe4, 0xc1, 0xab, 0x8c,
+	0xc4, 0x2b, 0x33, 0x19, 0xfc, 0x08, 0xd5, 0x67, 0x54, 0x0c, 0x8f, 0xd0, 0x1d, 0x68, 0x84, 0x22,
+	0xe3, 0x4a, 0xce, 0x46, 0xa1, 0x88, 0x68, 0x1e, 0x92, 0x97, 0xeb, 0xf6, 0x45, 0x44, 0xe7, 0xd3,
+	0xe1, 0x5c, 0x71, 0x3a, 0x82, 0xef, 0xc1, 0x35, 0xce, 0x4d, 0x8b, 0x0e, 0xa0, 0x4a, 0xb5, 0x2b,
+	0xbf, 0x64, 0xcc, 0x6f, 0x6d, 0x30, 0x37, 0x06, 0xd8, 0x42, 0x75, 0x77, 0x9c, 0x53, 0x99, 0x32,
+	0xc1, 0x73, 0x9e, 0x29, 0xc4, 0xe0, 0x05, 0x6c, 0x3d, 0xa3, 0xe2, 0x58, 0x37, 0x0a, 0x82, 0x0a,
+	0x27, 0xd3, 0x22, 0x62, 0x73, 0xfe, 0x8f, 0xf3, 0x1f, 0xfc, 0x04, 0x5e, 0xee, 0xd5, 0x84, 0xfc,
+	0x60, 0x35, 0xe4, 0x77, 0x36, 0x87, 0xac, 0x4d, 0x2e, 0x0f, 0xfa, 0xef, 0x12, 0x34, 0xf7, 0x8a,
+	0x99, 0xd8, 0xc0, 0x5c, 0xf7, 0xe1, 0xff, 0x22, 0x53, 0x76, 0xda, 0x52, 0x1a, 0xd3, 0x50, 0x09,
+	0x9b, 0x71, 0x17, 0xb7, 0x8b, 0x0f, 0xc7, 0xb9, 0x1e, 0x0d, 0xa1, 0x9e, 0x2a, 0x49, 0x14, 0x9d,
+	0xcc, 0x4c, 0xd1, 0x5b, 0x83, 0x0f, 0x37, 0xc4, 0xb8, 0x72, 0x6d, 0xef, 0x38, 0x37, 0xc2, 0x73,
+	0xf3, 0xe0, 0x19, 0xd4, 0x0b, 0x2d, 0x02, 0xa8, 0x61, 0xc2, 0x23, 0x31, 0x6d, 0x5f, 0x43, 0x2d,
+	0x00, 0xac, 0xef, 0xc4, 0x62, 0xcc, 0xb8, 0x25, 0xf1, 0x43, 0x4a, 0x52, 0xb5, 0x2f, 0xb8, 0x26,
+	0xf1, 0x36, 0x34, 0x8c, 0x78, 0x48, 0x14, 0xe5, 0xe1, 0xac, 0x5d, 0x0e, 0xfe, 0x72, 0xa0, 0xb6,
+	0x6f, 0x36, 0x25, 0x7a, 0x09, 0xff, 0xb3, 0x89, 0x1d, 0xcd, 0xa3, 0xb4, 0x7b, 0xe6, 0x83, 0x4d,
+	0xbd, 0x63, 0xec, 0xf2, 0xaa, 0xcc, 0x83, 0x6c, 0x45, 0x2b, 0xb2, 0xde, 0x59, 0x9a, 0x09, 0xf2,
+	0xd2, 0x6e, 0xda, 0x59, 0x4b, 0x0b, 0x02, 0x1b, 0x3c, 0xfa, 0x0a, 0x5a, 0x0b, 0x9a, 0x32, 0x1e,
+	0x2c, 0xcf, 0xdf, 0xbd, 0x4a, 0xce, 0x70, 0x73, 0xbc, 0x2c, 0x6a, 0xce, 0x34, 0x0c, 0x36, 0x3a,
+	0x61, 0xb1, 0x65, 0x7e, 0x17, 0xbb, 0x46, 0xf3, 0x94, 0xc5, 0x54, 0x8f, 0x53, 0x4e, 0x64, 0x16,
+	0x50, 0xb5, 0xe3, 0x94, 0xeb, 0x34, 0x24, 0x78, 0x04, 0xad, 0xd5, 0x87, 0xea, 0x8d, 0xb8, 0x9b,
+	0x0e, 0x53, 0x3b, 0xa1, 0x2f, 0x53, 0x3a, 0x4c, 0xda, 0x25, 0x9d, 0xe1, 0x61, 0x32, 0x3c, 0x79,
+	0x2e, 0xf8, 0xd7, 0x44, 0x85, 0xa7, 0x6d, 0x27, 0xf8, 0xbd, 0x0c, 0x0d, 0xfd, 0x3a, 0x8a, 0xe9,
+	0xab, 0x8c, 0xa6, 0x0a, 0xed, 0x82, 0xb7, 0xb4, 0xe4, 0x4d, 0x8e, 0xbd, 0xc1, 0xed, 0x0d, 0x3b,
+	0xe4, 0x80, 0x47, 0x89, 0x60, 0x5c, 0xe1, 0x65, 0x1b, 0xf4, 0x08, 0x6a, 0x76, 0x21, 0xf8, 0xce,
+	0xd5, 0xac, 0x73, 0xf8, 0xeb, 0xcb, 0xc1, 0x52, 0xcf, 0xf2, 0x72, 0x58, 0x5d, 0x2e, 0x79, 0xa2,
+	0x16, 0xcb, 0x65, 0x67, 0x85, 0xcb, 0xab, 0x86, 0x27, 0x97, 0x48, 0xfa, 0x68, 0x99, 0xa4, 0xed,
+	0x5a, 0x1b, 0xbc, 0xa1, 0xe0, 0x45, 0x4a, 0x16, 0x04, 0x79, 0xa0, 0x67, 0x72, 0x89, 0xb6, 0xf5,
+	0x5e, 0x39, 0x25, 0xe9, 0x48, 0x5f, 0xe1, 0x6f, 0x75, 0x4a, 0xdd, 0x3a, 0xde, 0x3a, 0x25, 0xa9,
+	0x66, 0xf2, 0xed, 0xc7, 0xd0, 0x5a, 0xb5, 0x5b, 0xc3, 0xcf, 0x6b, 0x7f, 0x7c, 0x3e, 0x73, 0x3e,
+	0x29, 0x05, 0x7f, 0x38, 0xd0, 0x36, 0x31, 0x1c, 0x5c, 0x24, 0x31, 0xc9, 0xf3, 0xba, 0x03, 0x60,
+	0xb6, 0x18, 0xe3, 0x11, 0xbd, 0x30, 0x7e, 0xaa, 0xd8, 0xd5, 0x9a, 0xa1, 0x56, 0xa0, 0x5b, 0xe0,
+	0x86, 0x82, 0x47, 0x4c, 0x59, 0xb6, 0x30, 0x8b, 0x77, 0xae, 0xd0, 0x4d, 0x34, 0xe7, 0x82, 0x45,
+	0x72, 0xbd, 0x42, 0xb7, 0x76, 0xf5, 0x56, 0xd6, 0xac, 0xde, 0x3b, 0xd0, 0x48, 0xcf, 0x58, 0x92,
+	0xd0, 0xc8, 0xb6, 0x7d, 0xb5, 0x53, 0xee, 0x56, 0xb1, 0x97, 0xeb, 0x4c, 0x3b, 0x6f, 0x43, 0x5d,
+	0xd2, 0x54, 0xc4, 0xe7, 0x34, 0xf2, 0x6b, 0x26, 0x2b, 0x73, 0x19, 0xed, 0x81, 0x57, 0x9c, 0x47,
+	0x2c, 0x31, 0xff, 0x07, 0xde, 0xe0, 0xce, 0x86, 0x06, 0x19, 0x1e, 0x7d, 0x23, 0x73, 0x56, 0x85,
+	0xc2, 0x6a, 0x98, 0x04, 0xdf, 0x81, 0xab, 0xef, 0x39, 0x56, 0x44, 0xa5, 0xf3, 0x01, 0x2e, 0xfd,
+	0xfb, 0x67, 0xe7, 0x8d, 0x03, 0x8c, 0xa0, 0x72, 0xca, 0x54, 0x6a, 0x52, 0x5f, 0xc6, 0xe6, 0xbc,
+	0xf7, 0x25, 0xdc, 0x0c, 0xc5, 0x74, 0xbd, 0x8b, 0x3d, 0xcf, 0x12, 0xca, 0x91, 0x14, 0x4a, 0xfc,
+	0x50, 0xb3, 0xca, 0x3f, 0x9d, 0x1b, 0xdf, 0x0e, 0x30, 0x99, 0xf5, 0xf6, 0x35, 0x78, 0x37, 0x49,
+	0x6c, 0xe3, 0xc8, 0x71, 0xcd, 0xfc, 0x1c, 0x7f, 0xf4, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4e,
+	0xf9, 0xbe, 0xb4, 0x02, 0x0c, 0x00, 0x00,
 }
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"context"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/proxy"
+)
+
+func endpointToDestination(endpoint *net.Endpoint) net.Destination {
+	dest := endpoint.AsDestination()
+	if dest.Network == net.Network_Unknown || dest.Network == net.Network_RawTCP {
+		dest.Network = net.Network_TCP
+	}
+	return dest
+}
+
+// ContextWithRouteRequest returns a context describing the connection in the given request, in the same way as
+// inbound handlers do for real connections. The user is attached whenever the request has one, with level 0 included.
+func ContextWithRouteRequest(ctx context.Context, request *RouteRequest) context.Context {
+	if request.Destination != nil {
+		ctx = proxy.ContextWithDestination(ctx, endpointToDestination(request.Destination))
+	}
+	if request.Source != nil {
+		ctx = proxy.ContextWithSource(ctx, endpointToDestination(request.Source))
+	}
+	if len(request.InboundTag) > 0 {
+		ctx = proxy.ContextWithInboundTag(ctx, request.InboundTag)
+	}
+	if request.HasUser || len(request.UserEmail) > 0 || request.UserLevel > 0 {
+		ctx = protocol.ContextWithUser(ctx, &protocol.User{
+			Email: request.UserEmail,
+			Level: request.UserLevel,
+		})
+	}
+	if len(request.Attribute) > 0 {
+		ctx = proxy.ContextWithAttributes(ctx, request.Attribute)
+	}
+	return ctx
+}
+
+// describeCondition returns the names of the rule fields that the given condition checks.
+func describeCondition(cond Condition) []string {
+	conds, ok := cond.(*ConditionChan)
+	if !ok {
+		return []string{describeMatcher(cond)}
+	}
+	names := make([]string, 0, conds.Len())
+	for _, c := range *conds {
+		names = append(names, describeMatcher(c))
+	}
+	return names
+}
+
+func describeMatcher(cond Condition) string {
+	switch c := cond.(type) {
+	case *DomainMatcher, *PlainDomainMatcher, *RegexpDomainMatcher:
+		return "domain"
+	case *CIDRListMatcher:
+		if c.onSource {
+			return "source"
+		}
+		return "ip"
+	case *PortMatcher:
+		return "port"
+	case *NetworkMatcher:
+		return "network"
+	case *UserMatcher:
+		return "user"
+	case *InboundTagMatcher:
+		return "inboundTag"
+	case *UserLevelMatcher:
+		return "userLevel"
+	case *AttributeMatcher:
+		return "attrs"
+	default:
+		return "unknown"
+	}
+}
+
+// addSkippedRule records that the rule at the given index is skipped, unless it is recorded already.
+func (e *RouteExplanation) addSkippedRule(idx int) {
+	for _, skipped := range e.SkippedRule {
+		if skipped == int32(idx) {
+			return
+		}
+	}
+	e.SkippedRule = append(e.SkippedRule, int32(idx))
+}
@@ -0,0 +1,154 @@
+package router
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+)
+
+const (
+	defaultGeoIPFile   = "geoip.dat"
+	defaultGeoSiteFile = "geosite.dat"
+)
+
+// geoDataFile is a data file loaded from disk, with the modification time at loading.
+type geoDataFile struct {
+	path    string
+	modTime time.Time
+}
+
+func (f *geoDataFile) read() ([]byte, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// The modification time is taken from the opened file, so that it matches the content even if the file is
+	// replaced meanwhile.
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	f.modTime = info.ModTime()
+	return data, nil
+}
+
+// changed returns true if the file was modified or removed after it was read.
+func (f *geoDataFile) changed() bool {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return true
+	}
+	return !info.ModTime().Equal(f.modTime)
+}
+
+// geoData holds GeoIP and GeoSite entries referred by routing rules.
+type geoData struct {
+	files []*geoDataFile
+	ips   map[string][]*CIDR
+	sites map[string][]*Domain
+}
+
+func resolveGeoDataFile(path string, defaultName string) string {
+	if len(path) > 0 {
+		return path
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return defaultName
+	}
+	return filepath.Join(filepath.Dir(executable), defaultName)
+}
+
+// loadGeoData loads the data files referred by rules in the given config. Files that are not referred are not read.
+func loadGeoData(config *Config) (*geoData, error) {
+	var needIP, needSite bool
+	for _, rule := range config.Rule {
+		needIP = needIP || len(rule.Geoip) > 0
+		needSite = needSite || len(rule.Geosite) > 0
+	}
+
+	d := &geoData{
+		ips:   make(map[string][]*CIDR),
+		sites: make(map[string][]*Domain),
+	}
+	if needIP {
+		file := &geoDataFile{path: resolveGeoDataFile(config.GeoipFile, defaultGeoIPFile)}
+		data, err := file.read()
+		if err != nil {
+			return nil, errors.Base(err).Message("Router: Failed to read GeoIP file.")
+		}
+		list := new(GeoIPList)
+		if err := proto.Unmarshal(data, list); err != nil {
+			return nil, errors.Base(err).Message("Router: Invalid GeoIP file: ", file.path)
+		}
+		for _, entry := range list.Entry {
+			code := strings.ToLower(entry.CountryCode)
+			d.ips[code] = append(d.ips[code], entry.Cidr...)
+		}
+		d.files = append(d.files, file)
+		log.Info("Router: Loaded ", len(list.Entry), " GeoIP entries of version ", list.Version, " from ", file.path)
+	}
+	if needSite {
+		file := &geoDataFile{path: resolveGeoDataFile(config.GeositeFile, defaultGeoSiteFile)}
+		data, err := file.read()
+		if err != nil {
+			return nil, errors.Base(err).Message("Router: Failed to read GeoSite file.")
+		}
+		list := new(GeoSiteList)
+		if err := proto.Unmarshal(data, list); err != nil {
+			return nil, errors.Base(err).Message("Router: Invalid GeoSite file: ", file.path)
+		}
+		for _, entry := range list.Entry {
+			name := strings.ToLower(entry.Name)
+			d.sites[name] = append(d.sites[name], entry.Domain...)
+		}
+		d.files = append(d.files, file)
+		log.Info("Router: Loaded ", len(list.Entry), " GeoSite entries of version ", list.Version, " from ", file.path)
+	}
+	return d, nil
+}
+
+// changed returns true if any of the loaded files changed on disk.
+func (d *geoData) changed() bool {
+	for _, file := range d.files {
+		if file.changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// expand returns a copy of the given rule, with IP ranges and domains of its GeoIP and GeoSite entries added.
+func (d *geoData) expand(rule *RoutingRule) (*RoutingRule, error) {
+	if len(rule.Geoip) == 0 && len(rule.Geosite) == 0 {
+		return rule, nil
+	}
+	expanded := proto.Clone(rule).(*RoutingRule)
+	for _, code := range rule.Geoip {
+		cidrs, found := d.ips[strings.ToLower(code)]
+		if !found {
+			return nil, errors.New("Router: GeoIP entry not found: ", code)
+		}
+		expanded.Cidr = append(expanded.Cidr, cidrs...)
+	}
+	for _, name := range rule.Geosite {
+		domains, found := d.sites[strings.ToLower(name)]
+		if !found {
+			return nil, errors.New("Router: GeoSite entry not found: ", name)
+		}
+		expanded.Domain = append(expanded.Domain, domains...)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,127 @@
+package router_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+)
+
+func writeGeoSite(t *testing.T, file string, domain string, modTime time.Time) {
+	data, err := proto.Marshal(&GeoSiteList{
+		Entry: []*GeoSite{
+			{
+				Name: "TEST",
+				Domain: []*Domain{
+					{Type: Domain_Subdomain, Value: domain},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGeoData(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-router")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	geoipFile := filepath.Join(dir, "geoip.dat")
+	geoip, err := proto.Marshal(&GeoIPList{
+		Entry: []*GeoIP{
+			{
+				CountryCode: "xx",
+				Cidr: []*CIDR{
+					{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+				},
+			},
+		},
+		Version: "test",
+	})
+	assert.Error(err).IsNil()
+	assert.Error(ioutil.WriteFile(geoipFile, geoip, 0644)).IsNil()
+
+	geositeFile := filepath.Join(dir, "geosite.dat")
+	writeGeoSite(t, geositeFile, "v2ray.com", time.Now().Add(-time.Hour))
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Tag:     "site",
+				Geosite: []string{"test"},
+			},
+			{
+				Tag:   "ip",
+				Geoip: []string{"XX"},
+			},
+		},
+		GeoipFile:   geoipFile,
+		GeositeFile: geositeFile,
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+
+	tag, err := r.TakeDetour(proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("www.v2ray.com"), 80)))
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("site")
+
+	tag, err = r.TakeDetour(proxy.ContextWithDestination(ctx, net.TCPDestination(net.IPAddress([]byte{10, 1, 2, 3}), 80)))
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("ip")
+
+	reloaded, err := r.ReloadGeoData()
+	assert.Error(err).IsNil()
+	assert.Bool(reloaded).IsFalse()
+
+	writeGeoSite(t, geositeFile, "example.com", time.Now())
+	reloaded, err = r.ReloadGeoData()
+	assert.Error(err).IsNil()
+	assert.Bool(reloaded).IsTrue()
+
+	_, err = r.TakeDetour(proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("www.v2ray.com"), 80)))
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+
+	tag, err = r.TakeDetour(proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("example.com"), 80)))
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("site")
+
+	assert.Error(r.Reload(&Config{
+		Rule: []*RoutingRule{
+			{
+				Tag:     "site",
+				Geosite: []string{"unknown"},
+			},
+		},
+		GeositeFile: geositeFile,
+	})).IsNotNil()
+}
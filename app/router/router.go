@@ -2,9 +2,14 @@ package router
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
+	"github.com/golang/protobuf/proto"
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/observatory"
+	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
@@ -17,9 +22,20 @@ var (
 )
 
 type Router struct {
+	// defaultHits is accessed atomically, and is kept first for 64-bit alignment.
+	defaultHits int64
+
+	sync.RWMutex
+	// updating serializes changes to the rules, which are built without holding the RWMutex.
+	updating       sync.Mutex
 	domainStrategy Config_DomainStrategy
 	rules          []Rule
+	balancers      map[string]*Balancer
 	dnsServer      dns.Server
+	ohm            proxyman.OutboundHandlerManager
+	observer       *observatory.Observatory
+	config         *Config
+	geoData        *geoData
 }
 
 func NewRouter(ctx context.Context, config *Config) (*Router, error) {
@@ -29,17 +45,17 @@ func NewRouter(ctx context.Context, config *Config) (*Router, error) {
 	}
 	r := &Router{
 		domainStrategy: config.DomainStrategy,
-		rules:          make([]Rule, len(config.Rule)),
 	}
 
 	space.OnInitialize(func() error {
-		for idx, rule := range config.Rule {
-			r.rules[idx].Tag = rule.Tag
-			cond, err := rule.BuildCondition()
-			if err != nil {
-				return err
-			}
-			r.rules[idx].Condition = cond
+		r.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
+		if r.ohm == nil && len(config.BalancingRule) > 0 {
+			return errors.New("Router: OutboundHandlerManager is not found in the space.")
+		}
+		r.observer = observatory.FromSpace(space)
+
+		if err := r.apply(config, r.buildBalancers(config, nil)); err != nil {
+			return err
 		}
 
 		r.dnsServer = dns.FromSpace(space)
@@ -51,8 +67,222 @@ func NewRouter(ctx context.Context, config *Config) (*Router, error) {
 	return r, nil
 }
 
-func (v *Router) resolveIP(dest net.Destination) []net.Address {
-	ips := v.dnsServer.Get(dest.Address.Domain())
+// buildBalancers returns the balancers in the given config. Balancers in current with the same config are reused, so
+// that they keep their round robin positions and connection counts.
+func (v *Router) buildBalancers(config *Config, current map[string]*Balancer) map[string]*Balancer {
+	balancers := make(map[string]*Balancer, len(config.BalancingRule))
+	for _, rule := range config.BalancingRule {
+		if balancer, found := current[rule.Tag]; found && proto.Equal(balancer.config, rule) {
+			balancers[rule.Tag] = balancer
+			continue
+		}
+		balancers[rule.Tag] = NewBalancer(rule, v.ohm, v.observer)
+	}
+	return balancers
+}
+
+func buildRules(config *Config, balancers map[string]*Balancer, geo *geoData) ([]Rule, error) {
+	rules := make([]Rule, len(config.Rule))
+	ruleTags := make(map[string]bool)
+	for idx, rule := range config.Rule {
+		if len(rule.RuleTag) > 0 {
+			if ruleTags[rule.RuleTag] {
+				return nil, errors.New("Router: Duplicate rule tag: ", rule.RuleTag)
+			}
+			ruleTags[rule.RuleTag] = true
+		}
+		rules[idx].config = rule
+		rules[idx].hits = new(int64)
+
+		rule, err := geo.expand(rule)
+		if err != nil {
+			return nil, err
+		}
+		if len(rule.BalancingTag) > 0 {
+			if _, found := balancers[rule.BalancingTag]; !found {
+				return nil, errors.New("Router: Balancer not found: ", rule.BalancingTag)
+			}
+		}
+		rules[idx].Tag = rule.Tag
+		rules[idx].BalancerTag = rule.BalancingTag
+		rules[idx].RuleTag = rule.RuleTag
+		cond, err := rule.BuildCondition()
+		if err != nil {
+			return nil, err
+		}
+		rules[idx].Condition = cond
+	}
+	return rules, nil
+}
+
+// hitsKey returns the key to match the hit counters of the given rule across rebuilds. Tagged rules are matched by
+// their tags, and others by their content.
+func hitsKey(rule *RoutingRule) string {
+	if len(rule.RuleTag) > 0 {
+		return "tag:" + rule.RuleTag
+	}
+	return "rule:" + proto.CompactTextString(rule)
+}
+
+// apply builds rules from the given config and balancers, and swaps them in. Rules kept from the current config, with
+// the same tag or the same content, keep their hit counters. It must be called with the updating lock held.
+func (v *Router) apply(config *Config, balancers map[string]*Balancer) error {
+	geo, err := loadGeoData(config)
+	if err != nil {
+		return err
+	}
+	rules, err := buildRules(config, balancers, geo)
+	if err != nil {
+		return err
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	// Untagged rules with the same content take the counters in order.
+	hits := make(map[string][]*int64, len(v.rules))
+	for _, rule := range v.rules {
+		key := hitsKey(rule.config)
+		hits[key] = append(hits[key], rule.hits)
+	}
+	for idx := range rules {
+		key := hitsKey(rules[idx].config)
+		if counters := hits[key]; len(counters) > 0 {
+			rules[idx].hits = counters[0]
+			hits[key] = counters[1:]
+		}
+	}
+
+	v.domainStrategy = config.DomainStrategy
+	v.rules = rules
+	v.balancers = balancers
+	v.config = config
+	v.geoData = geo
+	return nil
+}
+
+// Reload replaces the domain strategy and all rules of this router with the ones in the given config. GeoIP and
+// GeoSite files are read again. Routing decisions in progress are not affected. Balancers with unchanged configs keep
+// their states. Rules added, removed or replaced by
+// AddRule, RemoveRule or ReplaceRules are discarded, as the given config is the only source of rules.
+func (v *Router) Reload(config *Config) error {
+	if v.ohm == nil && len(config.BalancingRule) > 0 {
+		return errors.New("Router: OutboundHandlerManager is not found in the space.")
+	}
+
+	v.updating.Lock()
+	defer v.updating.Unlock()
+
+	v.RLock()
+	current := v.balancers
+	v.RUnlock()
+
+	return v.apply(config, v.buildBalancers(config, current))
+}
+
+// ReloadGeoData rebuilds all rules with the current config, if any GeoIP or GeoSite file in use changed on disk since
+// it was loaded. It returns false if no file changed.
+func (v *Router) ReloadGeoData() (bool, error) {
+	v.updating.Lock()
+	defer v.updating.Unlock()
+
+	if v.geoData == nil || !v.geoData.changed() {
+		return false, nil
+	}
+	log.Info("Router: Reloading GeoIP and GeoSite files.")
+	if err := v.apply(v.config, v.balancers); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// updateRules replaces the rules with the result of the given function on the current rule configs. The function
+// must not modify the given slice. Balancers and the domain strategy are kept.
+func (v *Router) updateRules(update func(current []*RoutingRule) ([]*RoutingRule, error)) error {
+	v.updating.Lock()
+	defer v.updating.Unlock()
+
+	if v.config == nil {
+		return errors.New("Router: Router is not initialized.")
+	}
+	rules, err := update(v.config.Rule)
+	if err != nil {
+		return err
+	}
+	config := *v.config
+	config.Rule = rules
+	return v.apply(&config, v.balancers)
+}
+
+// ReplaceRules atomically replaces all rules of this router. Balancers and the domain strategy are kept.
+func (v *Router) ReplaceRules(rules []*RoutingRule) error {
+	return v.updateRules(func([]*RoutingRule) ([]*RoutingRule, error) {
+		return rules, nil
+	})
+}
+
+// AddRule inserts the given rule before the rule at index. The rule is appended if index is negative or not less than
+// the number of rules. The rule must have a tag, so that it can be removed later. The rule is lost on Reload.
+func (v *Router) AddRule(rule *RoutingRule, index int) error {
+	if len(rule.RuleTag) == 0 {
+		return errors.New("Router: Rule tag is not specified.")
+	}
+	return v.updateRules(func(current []*RoutingRule) ([]*RoutingRule, error) {
+		if index < 0 || index > len(current) {
+			index = len(current)
+		}
+		rules := make([]*RoutingRule, 0, len(current)+1)
+		rules = append(rules, current[:index]...)
+		rules = append(rules, rule)
+		rules = append(rules, current[index:]...)
+		return rules, nil
+	})
+}
+
+// RemoveRule removes the rule with the given tag.
+func (v *Router) RemoveRule(ruleTag string) error {
+	return v.updateRules(func(current []*RoutingRule) ([]*RoutingRule, error) {
+		rules := make([]*RoutingRule, 0, len(current))
+		for _, rule := range current {
+			if rule.RuleTag != ruleTag {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) == len(current) {
+			return nil, errors.New("Router: Rule not found: ", ruleTag)
+		}
+		return rules, nil
+	})
+}
+
+// ListRules returns all rules with their hit counts, and the number of connections that matched no rule. If reset is
+// true, all counters are reset to zero.
+func (v *Router) ListRules(reset bool) ([]*RuleStats, int64) {
+	v.RLock()
+	rules := v.rules
+	v.RUnlock()
+
+	list := make([]*RuleStats, len(rules))
+	for idx := range rules {
+		var hits int64
+		if reset {
+			hits = atomic.SwapInt64(rules[idx].hits, 0)
+		} else {
+			hits = rules[idx].Hits()
+		}
+		list[idx] = &RuleStats{
+			Rule: rules[idx].config,
+			Hits: hits,
+		}
+	}
+	if reset {
+		return list, atomic.SwapInt64(&v.defaultHits, 0)
+	}
+	return list, atomic.LoadInt64(&v.defaultHits)
+}
+
+func (v *Router) resolveIP(ctx context.Context, dest net.Destination) []net.Address {
+	ips, _ := dns.GetContext(ctx, v.dnsServer, dest.Address.Domain())
 	if len(ips) == 0 {
 		return nil
 	}
@@ -63,31 +293,111 @@ func (v *Router) resolveIP(dest net.Destination) []net.Address {
 	return dests
 }
 
+// TakeDetour returns the outbound tag for the connection in the given context. If the matching rule targets a
+// balancer, an outbound is picked from the balancer.
 func (v *Router) TakeDetour(ctx context.Context) (string, error) {
-	for _, rule := range v.rules {
-		if rule.Apply(ctx) {
-			return rule.Tag, nil
+	tag, balancer, err := v.PickRoute(ctx)
+	if err != nil {
+		return "", err
+	}
+	if balancer != nil {
+		return balancer.PickOutbound(ctx)
+	}
+	return tag, nil
+}
+
+// PickRoute returns either the outbound tag or the balancer of the first rule that matches the connection in the
+// given context. Rules targeting an outbound that failed its latest probe are skipped. The decision is counted in the
+// hits of the matched rule, or of the default route if no rule matches.
+func (v *Router) PickRoute(ctx context.Context) (string, *Balancer, error) {
+	return v.pickRoute(ctx, nil)
+}
+
+// Explain makes a routing decision for the connection in the given context the same way as TakeDetour, and returns
+// how the decision was made. It neither counts hits nor advances balancers.
+func (v *Router) Explain(ctx context.Context) (*RouteExplanation, error) {
+	explanation := &RouteExplanation{
+		RuleIndex: -1,
+	}
+	tag, balancer, err := v.pickRoute(ctx, explanation)
+	if err != nil {
+		return explanation, err
+	}
+	if balancer != nil {
+		explanation.BalancingTag = balancer.Tag()
+		tag, err = balancer.PeekOutbound(ctx)
+		if err != nil {
+			return explanation, err
 		}
 	}
+	explanation.OutboundTag = tag
+	return explanation, nil
+}
+
+// pickRoute implements PickRoute. If explanation is not nil, details of the decision are recorded into it.
+func (v *Router) pickRoute(ctx context.Context, explanation *RouteExplanation) (string, *Balancer, error) {
+	v.RLock()
+	rules := v.rules
+	balancers := v.balancers
+	domainStrategy := v.domainStrategy
+	v.RUnlock()
+
+	idx := v.pickRule(ctx, rules, explanation)
 
 	dest := proxy.DestinationFromContext(ctx)
-	if v.domainStrategy == Config_IpIfNonMatch && dest.Address.Family().IsDomain() {
+	if idx < 0 && domainStrategy == Config_IpIfNonMatch && dest.Address.Family().IsDomain() {
 		log.Info("Router: Looking up IP for ", dest)
-		ipDests := v.resolveIP(dest)
+		ipDests := v.resolveIP(ctx, dest)
 		if ipDests != nil {
 			ctx = proxy.ContextWithResolveIPs(ctx, ipDests)
-			for _, rule := range v.rules {
-				if rule.Apply(ctx) {
-					return rule.Tag, nil
+			if explanation != nil {
+				explanation.Resolved = true
+				for _, ip := range ipDests {
+					explanation.ResolvedIp = append(explanation.ResolvedIp, net.NewIPOrDomain(ip))
 				}
 			}
+			idx = v.pickRule(ctx, rules, explanation)
 		}
 	}
 
-	return "", ErrNoRuleApplicable
+	if idx < 0 {
+		if explanation == nil {
+			atomic.AddInt64(&v.defaultHits, 1)
+		}
+		return "", nil, ErrNoRuleApplicable
+	}
+	rule := &rules[idx]
+	if explanation != nil {
+		explanation.RuleIndex = int32(idx)
+		explanation.Condition = describeCondition(rule.Condition)
+	} else {
+		atomic.AddInt64(rule.hits, 1)
+	}
+	if len(rule.BalancerTag) > 0 {
+		return "", balancers[rule.BalancerTag], nil
+	}
+	return rule.Tag, nil, nil
+}
+
+// pickRule returns the index of the first matching rule, or -1 if no rule matches. Rules skipped for their outbound
+// handlers being down are recorded into explanation if it is not nil.
+func (v *Router) pickRule(ctx context.Context, rules []Rule, explanation *RouteExplanation) int {
+	for idx := range rules {
+		if len(rules[idx].Tag) > 0 && v.observer != nil && !v.observer.IsAlive(rules[idx].Tag) {
+			log.Debug("Router: Skipping rule ", idx, " as outbound [", rules[idx].Tag, "] is down.")
+			if explanation != nil {
+				explanation.addSkippedRule(idx)
+			}
+			continue
+		}
+		if rules[idx].Apply(ctx) {
+			return idx
+		}
+	}
+	return -1
 }
 
-func (Router) Interface() interface{} {
+func (*Router) Interface() interface{} {
 	return (*Router)(nil)
 }
 
@@ -3,6 +3,7 @@ package router_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
@@ -13,7 +14,9 @@ import (
 	_ "v2ray.com/core/app/proxyman/outbound"
 	. "v2ray.com/core/app/router"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
 	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/freedom"
 	"v2ray.com/core/testing/assert"
 )
 
@@ -46,3 +49,376 @@ func TestSimpleRouter(t *testing.T) {
 	assert.Error(err).IsNil()
 	assert.String(tag).Equals("test")
 }
+
+func TestReloadRouter(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Tag: "test",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_TCP},
+				},
+			},
+		},
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+	assert.Error(r.Reload(&Config{
+		Rule: []*RoutingRule{
+			{
+				Tag: "udp",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_UDP},
+				},
+			},
+		},
+	})).IsNil()
+
+	tcpCtx := proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80))
+	_, err := r.TakeDetour(tcpCtx)
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+
+	udpCtx := proxy.ContextWithDestination(ctx, net.UDPDestination(net.DomainAddress("v2ray.com"), 53))
+	tag, err := r.TakeDetour(udpCtx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("udp")
+}
+
+func TestBalancer(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				BalancingTag: "pool",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_TCP},
+				},
+			},
+		},
+		BalancingRule: []*BalancingRule{
+			{
+				Tag:              "pool",
+				OutboundSelector: []string{"vmess-"},
+				Strategy:         BalancingRule_RoundRobin,
+			},
+		},
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(space)
+	for _, tag := range []string{"direct", "vmess-a", "vmess-b"} {
+		assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+		})).IsNil()
+	}
+
+	r := FromSpace(space)
+	ctx = proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80))
+
+	tag, balancer, err := r.PickRoute(ctx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("")
+	assert.String(balancer.Tag()).Equals("pool")
+
+	for i := 0; i < 2; i++ {
+		tag, err := balancer.PeekOutbound(ctx)
+		assert.Error(err).IsNil()
+		assert.String(tag).Equals("vmess-a")
+	}
+
+	for _, expected := range []string{"vmess-a", "vmess-b", "vmess-a"} {
+		tag, err := r.TakeDetour(ctx)
+		assert.Error(err).IsNil()
+		assert.String(tag).Equals(expected)
+	}
+
+	// The balancer is kept as its config is not changed.
+	assert.Error(r.Reload(config)).IsNil()
+	tag, err = r.TakeDetour(ctx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("vmess-b")
+
+	config.BalancingRule[0].Strategy = BalancingRule_LeastConn
+	assert.Error(r.Reload(config)).IsNil()
+	_, balancer, err = r.PickRoute(ctx)
+	assert.Error(err).IsNil()
+	release := balancer.Acquire("vmess-a")
+	tag, err = balancer.PickOutbound(ctx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("vmess-b")
+	release()
+
+	config.BalancingRule[0].Strategy = BalancingRule_LeastLatency
+	assert.Error(r.Reload(config)).IsNil()
+	_, balancer, err = r.PickRoute(ctx)
+	assert.Error(err).IsNil()
+	balancer.ReportLatency("vmess-a", 200*time.Millisecond)
+	balancer.ReportLatency("vmess-b", 100*time.Millisecond)
+	tag, err = balancer.PickOutbound(ctx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("vmess-b")
+
+	config.Rule[0].BalancingTag = "unknown"
+	assert.Error(r.Reload(config)).IsNotNil()
+}
+
+func TestExplainRoute(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Tag: "udp",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_UDP},
+				},
+			},
+			{
+				Tag: "v2ray",
+				Domain: []*Domain{
+					{Type: Domain_Subdomain, Value: "v2ray.com"},
+				},
+				PortRange: &net.PortRange{From: 443, To: 443},
+			},
+		},
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+
+	explanation, err := r.Explain(ContextWithRouteRequest(ctx, &RouteRequest{
+		Destination: &net.Endpoint{
+			Address: net.NewIPOrDomain(net.DomainAddress("www.v2ray.com")),
+			Port:    443,
+		},
+	}))
+	assert.Error(err).IsNil()
+	assert.Int(int(explanation.RuleIndex)).Equals(1)
+	assert.String(explanation.OutboundTag).Equals("v2ray")
+	assert.Int(len(explanation.Condition)).Equals(2)
+	assert.String(explanation.Condition[0]).Equals("domain")
+	assert.String(explanation.Condition[1]).Equals("port")
+	assert.Bool(explanation.Resolved).IsFalse()
+
+	explanation, err = r.Explain(ContextWithRouteRequest(ctx, &RouteRequest{
+		Destination: &net.Endpoint{
+			Address: net.NewIPOrDomain(net.DomainAddress("www.v2ray.com")),
+			Port:    80,
+		},
+	}))
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+	assert.Int(int(explanation.RuleIndex)).Equals(-1)
+}
+
+func TestRuleHitsAndUpdates(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Tag:     "udp",
+				RuleTag: "udp",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_UDP},
+				},
+			},
+			{
+				Tag:       "https",
+				PortRange: &net.PortRange{From: 443, To: 443},
+			},
+		},
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+
+	udpCtx := proxy.ContextWithDestination(ctx, net.UDPDestination(net.DomainAddress("v2ray.com"), 53))
+	httpsCtx := proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 443))
+	httpCtx := proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80))
+
+	for i := 0; i < 3; i++ {
+		r.TakeDetour(udpCtx)
+	}
+	r.TakeDetour(httpsCtx)
+	r.TakeDetour(httpCtx)
+	r.TakeDetour(httpCtx)
+
+	rules, defaultHits := r.ListRules(false)
+	assert.Int(len(rules)).Equals(2)
+	assert.Int64(rules[0].Hits).Equals(3)
+	assert.Int64(rules[1].Hits).Equals(1)
+	assert.Int64(defaultHits).Equals(2)
+
+	assert.Error(r.AddRule(&RoutingRule{
+		Tag:       "http",
+		PortRange: &net.PortRange{From: 80, To: 80},
+	}, 0)).IsNotNil()
+	assert.Error(r.AddRule(&RoutingRule{
+		Tag:       "http",
+		RuleTag:   "udp",
+		PortRange: &net.PortRange{From: 80, To: 80},
+	}, 0)).IsNotNil()
+	assert.Error(r.AddRule(&RoutingRule{
+		Tag:       "http",
+		RuleTag:   "http",
+		PortRange: &net.PortRange{From: 80, To: 80},
+	}, 0)).IsNil()
+
+	tag, err := r.TakeDetour(httpCtx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("http")
+
+	rules, defaultHits = r.ListRules(true)
+	assert.Int(len(rules)).Equals(3)
+	assert.String(rules[0].Rule.RuleTag).Equals("http")
+	assert.Int64(rules[0].Hits).Equals(1)
+	assert.Int64(rules[1].Hits).Equals(3)
+	assert.Int64(rules[2].Hits).Equals(1)
+	assert.Int64(defaultHits).Equals(2)
+
+	rules, defaultHits = r.ListRules(false)
+	assert.Int64(rules[1].Hits).Equals(0)
+	assert.Int64(defaultHits).Equals(0)
+
+	assert.Error(r.RemoveRule("http")).IsNil()
+	assert.Error(r.RemoveRule("http")).IsNotNil()
+	_, err = r.TakeDetour(httpCtx)
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+
+	assert.Error(r.ReplaceRules([]*RoutingRule{
+		{
+			Tag:       "any",
+			PortRange: &net.PortRange{From: 1, To: 65535},
+		},
+	})).IsNil()
+	tag, err = r.TakeDetour(udpCtx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("any")
+}
+
+func TestRuleHitsAcrossReload(t *testing.T) {
+	assert := assert.On(t)
+
+	newConfig := func() *Config {
+		return &Config{
+			Rule: []*RoutingRule{
+				{
+					Tag:     "udp",
+					RuleTag: "udp",
+					NetworkList: &net.NetworkList{
+						Network: []net.Network{net.Network_UDP},
+					},
+				},
+				{
+					Tag:       "https",
+					PortRange: &net.PortRange{From: 443, To: 443},
+				},
+			},
+		}
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, newConfig())).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+
+	udpCtx := proxy.ContextWithDestination(ctx, net.UDPDestination(net.DomainAddress("v2ray.com"), 53))
+	httpsCtx := proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 443))
+	httpCtx := proxy.ContextWithDestination(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80))
+
+	r.TakeDetour(udpCtx)
+	r.TakeDetour(httpsCtx)
+	r.TakeDetour(httpsCtx)
+	assert.Error(r.AddRule(&RoutingRule{
+		Tag:       "http",
+		RuleTag:   "http",
+		PortRange: &net.PortRange{From: 80, To: 80},
+	}, -1)).IsNil()
+
+	config := newConfig()
+	config.Rule[0].Tag = "udp2"
+	assert.Error(r.Reload(config)).IsNil()
+
+	rules, _ := r.ListRules(false)
+	assert.Int(len(rules)).Equals(2)
+	assert.Int64(rules[0].Hits).Equals(1)
+	assert.Int64(rules[1].Hits).Equals(2)
+
+	// The rule added by AddRule is discarded.
+	_, err := r.TakeDetour(httpCtx)
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+}
+
+func TestExplainRouteUserLevelZero(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Tag:       "free",
+				UserLevel: []*UserLevelRange{{From: 0, To: 0}},
+			},
+		},
+	}
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+	request := &RouteRequest{
+		Destination: &net.Endpoint{
+			Address: net.NewIPOrDomain(net.DomainAddress("v2ray.com")),
+			Port:    443,
+		},
+	}
+	_, err := r.Explain(ContextWithRouteRequest(ctx, request))
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+
+	request.HasUser = true
+	explanation, err := r.Explain(ContextWithRouteRequest(ctx, request))
+	assert.Error(err).IsNil()
+	assert.String(explanation.OutboundTag).Equals("free")
+}
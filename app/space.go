@@ -13,6 +13,12 @@ type Application interface {
 	Interface() interface{}
 }
 
+// Runnable is an Application that runs in background, such as a server, between the start and the close of the space.
+type Runnable interface {
+	Start() error
+	Close()
+}
+
 type InitializationCallback func() error
 
 func CreateAppFromConfig(ctx context.Context, config interface{}) (Application, error) {
@@ -35,11 +41,17 @@ type Space interface {
 	AddApplication(application Application) error
 	Initialize() error
 	OnInitialize(InitializationCallback)
+	// Start starts all Runnable applications in the order they are added. If one of them fails to start, the ones
+	// started are closed in the reverse order, and the error is returned.
+	Start() error
+	// Close closes all Runnable applications in the reverse order they are added.
+	Close()
 }
 
 type spaceImpl struct {
 	initialized bool
 	cache       map[reflect.Type]Application
+	apps        []Application
 	appInit     []InitializationCallback
 }
 
@@ -84,10 +96,43 @@ func (v *spaceImpl) AddApplication(app Application) error {
 		return errors.New("App: Nil space.")
 	}
 	appType := reflect.TypeOf(app.Interface())
+	if old, found := v.cache[appType]; found {
+		for idx := range v.apps {
+			if v.apps[idx] == old {
+				v.apps = append(v.apps[:idx], v.apps[idx+1:]...)
+				break
+			}
+		}
+	}
 	v.cache[appType] = app
+	v.apps = append(v.apps, app)
 	return nil
 }
 
+func (v *spaceImpl) Start() error {
+	started := make([]Runnable, 0, len(v.apps))
+	for _, app := range v.apps {
+		if runnable, ok := app.(Runnable); ok {
+			if err := runnable.Start(); err != nil {
+				for idx := len(started) - 1; idx >= 0; idx-- {
+					started[idx].Close()
+				}
+				return err
+			}
+			started = append(started, runnable)
+		}
+	}
+	return nil
+}
+
+func (v *spaceImpl) Close() {
+	for idx := len(v.apps) - 1; idx >= 0; idx-- {
+		if runnable, ok := v.apps[idx].(Runnable); ok {
+			runnable.Close()
+		}
+	}
+}
+
 type contextKey int
 
 const (
@@ -0,0 +1,56 @@
+package app_test
+
+import (
+	"errors"
+	"testing"
+
+	. "v2ray.com/core/app"
+	"v2ray.com/core/testing/assert"
+)
+
+type firstApp interface{}
+type secondApp interface{}
+type thirdApp interface{}
+
+// runnableApp is a Runnable recording its starts and closes into a shared log.
+type runnableApp struct {
+	name  string
+	iface interface{}
+	err   error
+	log   *[]string
+}
+
+func (a *runnableApp) Interface() interface{} {
+	return a.iface
+}
+
+func (a *runnableApp) Start() error {
+	if a.err != nil {
+		return a.err
+	}
+	*a.log = append(*a.log, "start "+a.name)
+	return nil
+}
+
+func (a *runnableApp) Close() {
+	*a.log = append(*a.log, "close "+a.name)
+}
+
+func TestSpaceStartFailure(t *testing.T) {
+	assert := assert.On(t)
+
+	var log []string
+	space := NewSpace()
+	assert.Error(space.AddApplication(&runnableApp{name: "first", iface: (*firstApp)(nil), log: &log})).IsNil()
+	assert.Error(space.AddApplication(&runnableApp{name: "second", iface: (*secondApp)(nil), log: &log})).IsNil()
+	failure := errors.New("failed")
+	assert.Error(space.AddApplication(&runnableApp{name: "third", iface: (*thirdApp)(nil), err: failure, log: &log})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	assert.Error(space.Start()).Equals(failure)
+	assert.Int(len(log)).Equals(4)
+	assert.String(log[0]).Equals("start first")
+	assert.String(log[1]).Equals("start second")
+	assert.String(log[2]).Equals("close second")
+	assert.String(log[3]).Equals("close first")
+}
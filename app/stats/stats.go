@@ -0,0 +1,165 @@
+// Package stats provides traffic counters for users, inbounds and outbounds.
+//
+// Counters are named in the form of "<type>>>><id>>>>traffic>>><direction>", where type is one of "user", "inbound"
+// and "outbound", id is the user email or the handler tag, and direction is either "uplink" or "downlink".
+package stats
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+)
+
+const (
+	nameSeparator = ">>>"
+)
+
+var (
+	// ErrCounterNotFound is returned when a requested counter doesn't exist.
+	ErrCounterNotFound = errors.New("Stats: Counter not found.")
+)
+
+// Counter is a named counter of bytes. It is safe for concurrent use.
+type Counter struct {
+	value int64
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+// Set sets the counter to the given value, and returns the previous value.
+func (c *Counter) Set(value int64) int64 {
+	return atomic.SwapInt64(&c.value, value)
+}
+
+// Add adds delta to the counter, and returns the new value.
+func (c *Counter) Add(delta int64) int64 {
+	return atomic.AddInt64(&c.value, delta)
+}
+
+// Input implements ray.Inspector. It counts the length of the given buffer.
+func (c *Counter) Input(b *buf.Buffer) {
+	c.InputLength(b.Len())
+}
+
+// InputLength implements ray.LengthInspector. It counts the length of each buffer passed through a ray.
+func (c *Counter) InputLength(length int) {
+	c.Add(int64(length))
+}
+
+// UserUplinkName returns the name of the counter for uplink traffic of the user with the given email.
+func UserUplinkName(email string) string {
+	return counterName("user", email, "uplink")
+}
+
+// UserDownlinkName returns the name of the counter for downlink traffic of the user with the given email.
+func UserDownlinkName(email string) string {
+	return counterName("user", email, "downlink")
+}
+
+// InboundUplinkName returns the name of the counter for uplink traffic of the inbound with the given tag.
+func InboundUplinkName(tag string) string {
+	return counterName("inbound", tag, "uplink")
+}
+
+// InboundDownlinkName returns the name of the counter for downlink traffic of the inbound with the given tag.
+func InboundDownlinkName(tag string) string {
+	return counterName("inbound", tag, "downlink")
+}
+
+// OutboundUplinkName returns the name of the counter for uplink traffic of the outbound with the given tag.
+func OutboundUplinkName(tag string) string {
+	return counterName("outbound", tag, "uplink")
+}
+
+// OutboundDownlinkName returns the name of the counter for downlink traffic of the outbound with the given tag.
+func OutboundDownlinkName(tag string) string {
+	return counterName("outbound", tag, "downlink")
+}
+
+func counterName(kind string, id string, direction string) string {
+	return strings.Join([]string{kind, id, "traffic", direction}, nameSeparator)
+}
+
+// Manager keeps all counters in a V2Ray instance.
+type Manager struct {
+	sync.RWMutex
+	counters map[string]*Counter
+}
+
+func NewManager(ctx context.Context, config *Config) (*Manager, error) {
+	return &Manager{
+		counters: make(map[string]*Counter),
+	}, nil
+}
+
+func (*Manager) Interface() interface{} {
+	return (*Manager)(nil)
+}
+
+// GetCounter returns the counter with the given name, or nil if the counter doesn't exist.
+func (m *Manager) GetCounter(name string) *Counter {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.counters[name]
+}
+
+// GetOrCreateCounter returns the counter with the given name. A new counter is created if it doesn't exist.
+func (m *Manager) GetOrCreateCounter(name string) *Counter {
+	if c := m.GetCounter(name); c != nil {
+		return c
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if c, found := m.counters[name]; found {
+		return c
+	}
+	c := new(Counter)
+	m.counters[name] = c
+	return c
+}
+
+// Query returns values of all counters whose name contains the given pattern. An empty pattern matches all counters.
+// If reset is true, the returned counters are set to zero atomically while being read.
+func (m *Manager) Query(pattern string, reset bool) map[string]int64 {
+	m.RLock()
+	defer m.RUnlock()
+
+	result := make(map[string]int64)
+	for name, c := range m.counters {
+		if !strings.Contains(name, pattern) {
+			continue
+		}
+		if reset {
+			result[name] = c.Set(0)
+		} else {
+			result[name] = c.Value()
+		}
+	}
+	return result
+}
+
+func FromSpace(space app.Space) *Manager {
+	app := space.GetApplication((*Manager)(nil))
+	if app == nil {
+		return nil
+	}
+	return app.(*Manager)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewManager(ctx, config.(*Config))
+	}))
+}
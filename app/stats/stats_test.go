@@ -0,0 +1,46 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+
+	. "v2ray.com/core/app/stats"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestCounterInspector(t *testing.T) {
+	assert := assert.On(t)
+
+	counter := new(Counter)
+	b := buf.New()
+	b.AppendBytes('a', 'b', 'c')
+	counter.Input(b)
+	counter.Input(b)
+	assert.Int64(counter.Value()).Equals(6)
+	assert.Int64(counter.Set(0)).Equals(6)
+	assert.Int64(counter.Value()).Equals(0)
+}
+
+func TestQueryAndReset(t *testing.T) {
+	assert := assert.On(t)
+
+	m, err := NewManager(context.Background(), new(Config))
+	assert.Error(err).IsNil()
+
+	assert.Bool(m.GetCounter(UserUplinkName("a@v2ray.com")) == nil).IsTrue()
+	m.GetOrCreateCounter(UserUplinkName("a@v2ray.com")).Add(10)
+	m.GetOrCreateCounter(UserDownlinkName("a@v2ray.com")).Add(20)
+	m.GetOrCreateCounter(InboundUplinkName("in")).Add(30)
+	assert.Bool(m.GetOrCreateCounter(UserUplinkName("a@v2ray.com")) == m.GetCounter(UserUplinkName("a@v2ray.com"))).IsTrue()
+
+	result := m.Query("user>>>a@v2ray.com>>>", true)
+	assert.Int(len(result)).Equals(2)
+	assert.Int64(result["user>>>a@v2ray.com>>>traffic>>>uplink"]).Equals(10)
+	assert.Int64(result["user>>>a@v2ray.com>>>traffic>>>downlink"]).Equals(20)
+
+	result = m.Query("", false)
+	assert.Int(len(result)).Equals(3)
+	assert.Int64(result[UserUplinkName("a@v2ray.com")]).Equals(0)
+	assert.Int64(result[InboundUplinkName("in")]).Equals(30)
+}
@@ -3,6 +3,7 @@ package web
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_serial "v2ray.com/core/common/serial"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -154,6 +155,7 @@ func _FileServer_Entry_OneofSizer(msg proto.Message) (n int) {
 }
 
 type Server struct {
+	// Domains served by this server. The first server with empty domain list serves requests to all other domains.
 	Domain   []string                               `protobuf:"bytes,1,rep,name=domain" json:"domain,omitempty"`
 	Settings *v2ray_core_common_serial.TypedMessage `protobuf:"bytes,2,opt,name=settings" json:"settings,omitempty"`
 }
@@ -179,6 +181,10 @@ func (m *Server) GetSettings() *v2ray_core_common_serial.TypedMessage {
 
 type Config struct {
 	Server []*Server `protobuf:"bytes,1,rep,name=server" json:"server,omitempty"`
+	// Port to listen on.
+	Port uint32 `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+	// Address to listen on. Listens on all interfaces if not specified.
+	Listen *v2ray_core_common_net.IPOrDomain `protobuf:"bytes,3,opt,name=listen" json:"listen,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
@@ -193,6 +199,20 @@ func (m *Config) GetServer() []*Server {
 	return nil
 }
 
+func (m *Config) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Config) GetListen() *v2ray_core_common_net.IPOrDomain {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*FileServer)(nil), "v2ray.core.app.web.FileServer")
 	proto.RegisterType((*FileServer_Entry)(nil), "v2ray.core.app.web.FileServer.Entry")
@@ -203,26 +223,29 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/app/web/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 328 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0x4a, 0xc3, 0x40,
-	0x10, 0x87, 0x4d, 0xff, 0x04, 0x33, 0xb9, 0x2d, 0x52, 0x42, 0x0f, 0x52, 0xaa, 0x48, 0x4f, 0x1b,
-	0x89, 0x37, 0x11, 0xc4, 0xb4, 0x8a, 0x17, 0x51, 0xa2, 0x28, 0x78, 0x50, 0x36, 0xe9, 0x58, 0x17,
-	0x9a, 0xec, 0xb2, 0x59, 0x5a, 0xf2, 0x46, 0xe2, 0x53, 0xca, 0xee, 0x46, 0x2b, 0xda, 0x5b, 0x26,
-	0xf3, 0xfd, 0x66, 0x26, 0x5f, 0xe0, 0x60, 0x95, 0x28, 0xd6, 0xd0, 0x42, 0x94, 0x71, 0x21, 0x14,
-	0xc6, 0x4c, 0xca, 0x78, 0x8d, 0x79, 0x5c, 0x88, 0xea, 0x8d, 0x2f, 0xa8, 0x54, 0x42, 0x0b, 0x42,
-	0xbe, 0x21, 0x85, 0x94, 0x49, 0x49, 0xd7, 0x98, 0x0f, 0x8f, 0xff, 0x04, 0x0b, 0x51, 0x96, 0xa2,
-	0x8a, 0x6b, 0x54, 0x9c, 0x2d, 0x63, 0xdd, 0x48, 0x9c, 0xbf, 0x96, 0x58, 0xd7, 0x6c, 0x81, 0x6e,
-	0xca, 0xf8, 0xc3, 0x03, 0xb8, 0xe2, 0x4b, 0xbc, 0x47, 0xb5, 0x42, 0x45, 0x4e, 0xa1, 0x8f, 0x95,
-	0x56, 0x4d, 0xe4, 0x8d, 0xba, 0x93, 0x30, 0x39, 0xa4, 0xff, 0x97, 0xd0, 0x0d, 0x4e, 0x2f, 0x0d,
-	0x9b, 0xb9, 0xc8, 0xf0, 0x05, 0xfa, 0xb6, 0x26, 0x7b, 0xd0, 0x33, 0x4c, 0xe4, 0x8d, 0xbc, 0x49,
-	0x70, 0xbd, 0x93, 0xd9, 0x8a, 0xec, 0x43, 0x30, 0xe3, 0x0a, 0x0b, 0x2d, 0x54, 0x13, 0x75, 0xda,
-	0xd6, 0xe6, 0x15, 0x21, 0xd0, 0x93, 0x4c, 0xbf, 0x47, 0x5d, 0xd3, 0xca, 0xec, 0x73, 0x1a, 0x42,
-	0x60, 0xb2, 0xb7, 0x6a, 0xc6, 0xd5, 0x78, 0x0e, 0x7e, 0x7b, 0xe5, 0x00, 0xfc, 0xb9, 0x28, 0x19,
-	0xaf, 0xec, 0x99, 0x41, 0xd6, 0x56, 0x24, 0x85, 0xdd, 0x1a, 0xb5, 0xe6, 0xd5, 0xa2, 0xb6, 0x1b,
-	0xc2, 0xe4, 0xe8, 0xf7, 0x07, 0x38, 0x1b, 0xd4, 0xd9, 0xa0, 0x0f, 0xc6, 0xc6, 0x8d, 0x93, 0x91,
-	0xfd, 0xe4, 0xc6, 0x67, 0xe0, 0x4f, 0xad, 0x66, 0x92, 0x80, 0x5f, 0xdb, 0x7d, 0xad, 0x8c, 0xe1,
-	0x36, 0x19, 0xee, 0xa2, 0xac, 0x25, 0xd3, 0x73, 0x18, 0x14, 0xa2, 0xdc, 0x02, 0xa6, 0xa1, 0x9b,
-	0x7a, 0x67, 0xac, 0x3f, 0x77, 0xd7, 0x98, 0x7f, 0x76, 0xc8, 0x63, 0x92, 0xb1, 0x86, 0x4e, 0x0d,
-	0x76, 0x21, 0x25, 0x7d, 0xc2, 0x3c, 0xf7, 0xed, 0x6f, 0x39, 0xf9, 0x0a, 0x00, 0x00, 0xff, 0xff,
-	0xc9, 0x59, 0x48, 0x03, 0x03, 0x02, 0x00, 0x00,
+	// 380 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x51, 0x5d, 0xcb, 0xd3, 0x30,
+	0x14, 0xb6, 0xef, 0xde, 0x15, 0x7b, 0x8a, 0x37, 0x41, 0x46, 0xe9, 0x85, 0xcc, 0x29, 0xba, 0xab,
+	0x54, 0xea, 0x95, 0xde, 0x88, 0xdd, 0x14, 0xbd, 0x90, 0x8d, 0x28, 0x0a, 0x5e, 0x28, 0x69, 0x7b,
+	0x9c, 0x81, 0x35, 0x09, 0x49, 0xd8, 0xe8, 0x6f, 0xf0, 0x8f, 0x88, 0xbf, 0x52, 0x9a, 0x54, 0x27,
+	0xbe, 0xbd, 0xcb, 0xc9, 0x79, 0x1e, 0x9e, 0x8f, 0x03, 0x0f, 0x4e, 0xa5, 0xe1, 0x3d, 0x6d, 0x54,
+	0x57, 0x34, 0xca, 0x60, 0xc1, 0xb5, 0x2e, 0xce, 0x58, 0x17, 0x8d, 0x92, 0xdf, 0xc4, 0x81, 0x6a,
+	0xa3, 0x9c, 0x22, 0xe4, 0x0f, 0xc8, 0x20, 0xe5, 0x5a, 0xd3, 0x33, 0xd6, 0xf9, 0xe3, 0xff, 0x88,
+	0x8d, 0xea, 0x3a, 0x25, 0x0b, 0x89, 0xae, 0xe0, 0x6d, 0x6b, 0xd0, 0xda, 0x40, 0xce, 0x9f, 0x4c,
+	0x03, 0x2d, 0x1a, 0xc1, 0x8f, 0x85, 0xeb, 0x35, 0xb6, 0x5f, 0x3b, 0xb4, 0x96, 0x1f, 0x30, 0x30,
+	0x56, 0x3f, 0x23, 0x80, 0xd7, 0xe2, 0x88, 0xef, 0xd1, 0x9c, 0xd0, 0x90, 0xe7, 0x30, 0x47, 0xe9,
+	0x4c, 0x9f, 0x45, 0xcb, 0xd9, 0x3a, 0x2d, 0x1f, 0xd2, 0x9b, 0x6e, 0xe8, 0x05, 0x4e, 0x5f, 0x0d,
+	0x58, 0x16, 0x28, 0xf9, 0x17, 0x98, 0xfb, 0x99, 0xdc, 0x85, 0xeb, 0x01, 0x93, 0x45, 0xcb, 0x68,
+	0x9d, 0xbc, 0xb9, 0xc5, 0xfc, 0x44, 0xee, 0x41, 0xb2, 0x15, 0x06, 0x1b, 0xa7, 0x4c, 0x9f, 0x5d,
+	0x8d, 0xab, 0xcb, 0x17, 0x21, 0x70, 0xad, 0xb9, 0xfb, 0x9e, 0xcd, 0x86, 0x15, 0xf3, 0xef, 0x2a,
+	0x85, 0x64, 0xe0, 0xee, 0xcc, 0x56, 0x98, 0x55, 0x0b, 0xf1, 0xe8, 0x72, 0x01, 0x71, 0xab, 0x3a,
+	0x2e, 0xa4, 0xb7, 0x99, 0xb0, 0x71, 0x22, 0x15, 0xdc, 0xb6, 0xe8, 0x9c, 0x90, 0x07, 0xeb, 0x15,
+	0xd2, 0xf2, 0xd1, 0xbf, 0x01, 0x42, 0x1b, 0x34, 0xb4, 0x41, 0x3f, 0x0c, 0x6d, 0xbc, 0x0b, 0x65,
+	0xb0, 0xbf, 0xbc, 0xd5, 0x8f, 0x08, 0xe2, 0x8d, 0x3f, 0x08, 0x29, 0x21, 0xb6, 0x5e, 0x70, 0x6c,
+	0x23, 0x9f, 0x6a, 0x23, 0x58, 0x62, 0x23, 0xd2, 0xa7, 0x50, 0xc6, 0x79, 0xf9, 0x3b, 0xcc, 0xbf,
+	0xc9, 0x33, 0x88, 0x8f, 0xc2, 0x3a, 0x94, 0x3e, 0x5b, 0x5a, 0xde, 0x9f, 0x30, 0x25, 0xd1, 0xd1,
+	0xb7, 0xfb, 0x9d, 0xd9, 0xfa, 0x24, 0x6c, 0x24, 0x54, 0x2f, 0x60, 0xd1, 0xa8, 0x6e, 0x42, 0xb7,
+	0x4a, 0x83, 0xc9, 0xfd, 0x70, 0xc5, 0xcf, 0xb3, 0x33, 0xd6, 0xbf, 0xae, 0xc8, 0xc7, 0x92, 0xf1,
+	0x9e, 0x6e, 0x06, 0xd8, 0x4b, 0xad, 0xe9, 0x27, 0xac, 0xeb, 0xd8, 0x9f, 0xf9, 0xe9, 0xef, 0x00,
+	0x00, 0x00, 0xff, 0xff, 0xfc, 0x7d, 0xb1, 0xc6, 0x7c, 0x02, 0x00, 0x00,
 }
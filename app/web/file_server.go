@@ -1 +1,52 @@
 package web
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
+)
+
+// NewFileServer creates an http.Handler that serves files and directories in the given config by request path.
+// A file entry serves the file on exactly its path, while a directory entry serves all files under its path.
+func NewFileServer(ctx context.Context, config *FileServer) (http.Handler, error) {
+	mux := http.NewServeMux()
+	patterns := make(map[string]bool)
+	for _, entry := range config.Entry {
+		path := entry.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		var handler http.Handler
+		switch fd := entry.FileOrDir.(type) {
+		case *FileServer_Entry_File:
+			file := fd.File
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeFile(w, r, file)
+			})
+		case *FileServer_Entry_Directory:
+			if !strings.HasSuffix(path, "/") {
+				path += "/"
+			}
+			handler = http.StripPrefix(path, http.FileServer(http.Dir(fd.Directory)))
+		default:
+			return nil, errors.New("Web|FileServer: Neither file nor directory is specified for path: ", path)
+		}
+
+		if patterns[path] {
+			return nil, errors.New("Web|FileServer: Duplicated path: ", path)
+		}
+		patterns[path] = true
+		mux.Handle(path, handler)
+	}
+	return mux, nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*FileServer)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewFileServer(ctx, config.(*FileServer))
+	}))
+}
@@ -1,5 +1,130 @@
+// Package web provides a simple web server, with virtual hosts selected by domain.
 package web
 
-type WebServer interface {
-	Handle()
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	v2net "v2ray.com/core/common/net"
+)
+
+// WebServer serves HTTP requests with handlers selected by the Host header of each request.
+type WebServer struct {
+	config         *Config
+	hosts          map[string]http.Handler
+	defaultHandler http.Handler
+	server         *http.Server
+}
+
+func NewWebServer(ctx context.Context, config *Config) (*WebServer, error) {
+	s := &WebServer{
+		config: config,
+		hosts:  make(map[string]http.Handler),
+	}
+	for _, server := range config.Server {
+		if server.Settings == nil {
+			return nil, errors.New("Web: Server settings are not specified.")
+		}
+		settings, err := server.Settings.GetInstance()
+		if err != nil {
+			return nil, err
+		}
+		rawHandler, err := common.CreateObject(ctx, settings)
+		if err != nil {
+			return nil, err
+		}
+		handler, ok := rawHandler.(http.Handler)
+		if !ok {
+			return nil, errors.New("Web: Not a HTTP handler.")
+		}
+
+		if len(server.Domain) == 0 {
+			if s.defaultHandler == nil {
+				s.defaultHandler = handler
+			}
+			continue
+		}
+		for _, domain := range server.Domain {
+			domain = strings.ToLower(domain)
+			if _, found := s.hosts[domain]; found {
+				return nil, errors.New("Web: Duplicated domain: ", domain)
+			}
+			s.hosts[domain] = handler
+		}
+	}
+	return s, nil
+}
+
+func (*WebServer) Interface() interface{} {
+	return (*WebServer)(nil)
+}
+
+// ServeHTTP implements http.Handler. It dispatches the request to the handler of the requested domain.
+func (s *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	handler, found := s.hosts[strings.ToLower(host)]
+	if !found {
+		handler = s.defaultHandler
+	}
+	if handler == nil {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// Start starts listening on the configured port. It does nothing if the port is not set.
+func (s *WebServer) Start() error {
+	if s.config.Port == 0 {
+		return nil
+	}
+	address := s.config.Listen.AsAddress()
+	if address == nil {
+		address = v2net.AnyIP
+	}
+	dest := v2net.TCPDestination(address, v2net.Port(s.config.Port))
+	listener, err := net.Listen("tcp", dest.NetAddr())
+	if err != nil {
+		return errors.Base(err).Message("Web: Failed to listen on ", dest)
+	}
+
+	s.server = &http.Server{
+		Handler: s,
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Warning("Web: Server stopped: ", err)
+		}
+	}()
+	log.Info("Web: Listening on ", dest)
+	return nil
+}
+
+func (s *WebServer) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+func FromSpace(space app.Space) *WebServer {
+	app := space.GetApplication((*WebServer)(nil))
+	if app == nil {
+		return nil
+	}
+	return app.(*WebServer)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewWebServer(ctx, config.(*Config))
+	}))
 }
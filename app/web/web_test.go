@@ -0,0 +1,74 @@
+package web_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "v2ray.com/core/app/web"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/testing/assert"
+)
+
+func get(handler http.Handler, host string, path string) (int, string) {
+	request := httptest.NewRequest("GET", "http://"+host+path, nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestVirtualHosts(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-web")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	assert.Error(ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("index"), 0644)).IsNil()
+	assert.Error(ioutil.WriteFile(filepath.Join(dir, "status.txt"), []byte("ok"), 0644)).IsNil()
+
+	server, err := NewWebServer(context.Background(), &Config{
+		Server: []*Server{
+			{
+				Domain: []string{"v2ray.com"},
+				Settings: serial.ToTypedMessage(&FileServer{
+					Entry: []*FileServer_Entry{
+						{
+							FileOrDir: &FileServer_Entry_Directory{Directory: dir},
+							Path:      "/static",
+						},
+					},
+				}),
+			},
+			{
+				Settings: serial.ToTypedMessage(&FileServer{
+					Entry: []*FileServer_Entry{
+						{
+							FileOrDir: &FileServer_Entry_File{File: filepath.Join(dir, "status.txt")},
+							Path:      "/status",
+						},
+					},
+				}),
+			},
+		},
+	})
+	assert.Error(err).IsNil()
+
+	code, _ := get(server, "V2Ray.com:8080", "/static/index.html")
+	assert.Int(code).Equals(http.StatusMovedPermanently)
+
+	code, body := get(server, "v2ray.com", "/static/")
+	assert.Int(code).Equals(http.StatusOK)
+	assert.String(body).Equals("index")
+
+	code, _ = get(server, "v2ray.com", "/status")
+	assert.Int(code).Equals(http.StatusNotFound)
+
+	code, body = get(server, "www.v2ray.com", "/status")
+	assert.Int(code).Equals(http.StatusOK)
+	assert.String(body).Equals("ok")
+}
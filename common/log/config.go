@@ -9,6 +9,11 @@ func (v *Config) Apply() error {
 			return err
 		}
 	}
+	if v.DnsLogType == LogType_File {
+		if err := InitDNSLogger(v.DnsLogPath); err != nil {
+			return err
+		}
+	}
 
 	if v.ErrorLogType == LogType_None {
 		SetLogLevel(LogLevel_Disabled)
@@ -78,6 +78,9 @@ type Config struct {
 	ErrorLogPath  string   `protobuf:"bytes,3,opt,name=error_log_path,json=errorLogPath" json:"error_log_path,omitempty"`
 	AccessLogType LogType  `protobuf:"varint,4,opt,name=access_log_type,json=accessLogType,enum=v2ray.core.common.log.LogType" json:"access_log_type,omitempty"`
 	AccessLogPath string   `protobuf:"bytes,5,opt,name=access_log_path,json=accessLogPath" json:"access_log_path,omitempty"`
+	// Log of domains resolved by DNS, with the inbound and the user asking for them, answers and name servers.
+	DnsLogType LogType `protobuf:"varint,6,opt,name=dns_log_type,json=dnsLogType,enum=v2ray.core.common.log.LogType" json:"dns_log_type,omitempty"`
+	DnsLogPath string  `protobuf:"bytes,7,opt,name=dns_log_path,json=dnsLogPath" json:"dns_log_path,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
@@ -120,6 +123,20 @@ func (m *Config) GetAccessLogPath() string {
 	return ""
 }
 
+func (m *Config) GetDnsLogType() LogType {
+	if m != nil {
+		return m.DnsLogType
+	}
+	return LogType_None
+}
+
+func (m *Config) GetDnsLogPath() string {
+	if m != nil {
+		return m.DnsLogPath
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.common.log.Config")
 	proto.RegisterEnum("v2ray.core.common.log.LogType", LogType_name, LogType_value)
@@ -129,26 +146,27 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/common/log/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 322 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x91, 0x6d, 0x4b, 0xf3, 0x30,
-	0x14, 0x86, 0xd7, 0x76, 0xaf, 0xd9, 0x5b, 0x08, 0x3c, 0xb0, 0xe7, 0x8b, 0x0e, 0x91, 0x31, 0x06,
-	0xb6, 0x30, 0xf1, 0x0f, 0xec, 0x4d, 0x84, 0x21, 0x63, 0x08, 0x82, 0x5f, 0x46, 0x17, 0xcf, 0xb2,
-	0x42, 0x9a, 0x53, 0xd2, 0x3a, 0xd8, 0x8f, 0xf2, 0x3f, 0x4a, 0x32, 0x6b, 0x15, 0x26, 0xf8, 0x31,
-	0xe1, 0x3a, 0xd7, 0x7d, 0x1f, 0x0e, 0x19, 0x1c, 0xc6, 0x3a, 0x3c, 0xfa, 0x1c, 0xe3, 0x80, 0xa3,
-	0x86, 0x80, 0x63, 0x1c, 0xa3, 0x0a, 0x24, 0x8a, 0x80, 0xa3, 0xda, 0x45, 0xc2, 0x4f, 0x34, 0x66,
-	0xc8, 0xfe, 0xe5, 0x9c, 0x06, 0xff, 0xc4, 0xf8, 0x12, 0xc5, 0xd5, 0xbb, 0x4b, 0xaa, 0x53, 0xcb,
-	0xb1, 0x19, 0xe9, 0x80, 0xd6, 0xa8, 0x37, 0x12, 0xc5, 0x26, 0x3b, 0x26, 0xd0, 0x73, 0xfa, 0xce,
-	0xb0, 0x33, 0xbe, 0xf0, 0xcf, 0x8e, 0xfa, 0x4b, 0x14, 0x4f, 0xc7, 0x04, 0xd6, 0x2d, 0x3b, 0xf5,
-	0xf9, 0x62, 0xf7, 0xa4, 0x5b, 0x58, 0x24, 0x1c, 0x40, 0xf6, 0x5c, 0xab, 0xb9, 0xfc, 0x5d, 0xb3,
-	0x34, 0xd8, 0xba, 0x9d, 0x7b, 0xec, 0x93, 0x5d, 0x7f, 0xaf, 0x93, 0x84, 0xd9, 0xbe, 0xe7, 0xf5,
-	0x9d, 0x61, 0xa3, 0x88, 0x5b, 0x85, 0xd9, 0x9e, 0x2d, 0x48, 0x37, 0xe4, 0x1c, 0xd2, 0xb4, 0x68,
-	0x5d, 0xfe, 0x53, 0xeb, 0xf6, 0x69, 0x2c, 0xaf, 0x3d, 0xf8, 0xe1, 0xb1, 0x71, 0x15, 0x1b, 0x57,
-	0x70, 0x26, 0x6f, 0x74, 0x47, 0x6a, 0xf9, 0x48, 0x9d, 0x94, 0x1f, 0x51, 0x01, 0x2d, 0xb1, 0x26,
-	0xa9, 0x4d, 0x51, 0xa5, 0x28, 0x81, 0x3a, 0xe6, 0x7b, 0x11, 0x49, 0xa0, 0x2e, 0x6b, 0x90, 0xca,
-	0xfc, 0x00, 0x2a, 0xa3, 0xde, 0x68, 0x4e, 0xea, 0x5f, 0x8b, 0xb5, 0x48, 0x7d, 0x16, 0xa5, 0xe1,
-	0x56, 0xc2, 0x2b, 0x2d, 0x59, 0xc8, 0x2c, 0x44, 0x1d, 0xa3, 0x79, 0x0e, 0xb5, 0x8a, 0x94, 0xa0,
-	0xae, 0xd1, 0x3c, 0xa8, 0x1d, 0x52, 0xcf, 0x10, 0x33, 0xd8, 0xbe, 0x09, 0x5a, 0x9e, 0xdc, 0x90,
-	0xff, 0x1c, 0xe3, 0xf3, 0x9b, 0x4d, 0x9a, 0xa7, 0x3b, 0xae, 0xcc, 0xb9, 0x5f, 0x3c, 0x89, 0x62,
-	0x5b, 0xb5, 0xa7, 0xbf, 0xfd, 0x08, 0x00, 0x00, 0xff, 0xff, 0x11, 0x55, 0x7e, 0x04, 0x24, 0x02,
-	0x00, 0x00,
+	// 346 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x92, 0x5b, 0x6b, 0xf2, 0x40,
+	0x10, 0x86, 0x8d, 0xf1, 0xb8, 0x9e, 0x96, 0xc0, 0x07, 0x7e, 0x37, 0xad, 0x94, 0x22, 0x22, 0x34,
+	0x01, 0x4b, 0xef, 0x8b, 0xa7, 0x52, 0x90, 0x22, 0x52, 0x28, 0xf4, 0x46, 0xe2, 0x3a, 0xae, 0x81,
+	0xcd, 0x4e, 0xd8, 0xa4, 0x82, 0x3f, 0xa3, 0xff, 0xb8, 0xec, 0x6a, 0x8c, 0x05, 0x0b, 0x5e, 0x6e,
+	0x78, 0xe6, 0x7d, 0xde, 0x0c, 0x43, 0xba, 0xbb, 0x81, 0xf2, 0xf7, 0x2e, 0xc3, 0xd0, 0x63, 0xa8,
+	0xc0, 0x63, 0x18, 0x86, 0x28, 0x3d, 0x81, 0xdc, 0x63, 0x28, 0x37, 0x01, 0x77, 0x23, 0x85, 0x09,
+	0x3a, 0xff, 0x52, 0x4e, 0x81, 0x7b, 0x60, 0x5c, 0x81, 0xfc, 0xee, 0xdb, 0x26, 0xa5, 0x91, 0xe1,
+	0x9c, 0x31, 0x69, 0x82, 0x52, 0xa8, 0x96, 0x02, 0xf9, 0x32, 0xd9, 0x47, 0xd0, 0xb6, 0x3a, 0x56,
+	0xaf, 0x39, 0xb8, 0x71, 0x2f, 0x8e, 0xba, 0x33, 0xe4, 0xef, 0xfb, 0x08, 0x16, 0x75, 0x33, 0x75,
+	0x7c, 0x39, 0x2f, 0xa4, 0x95, 0xa5, 0x08, 0xd8, 0x81, 0x68, 0xe7, 0x4d, 0xcc, 0xed, 0xdf, 0x31,
+	0x33, 0x8d, 0x2d, 0x1a, 0x69, 0x8e, 0x79, 0x3a, 0xf7, 0xe7, 0x75, 0x22, 0x3f, 0xd9, 0xb6, 0xed,
+	0x8e, 0xd5, 0xab, 0x66, 0xba, 0xb9, 0x9f, 0x6c, 0x9d, 0x29, 0x69, 0xf9, 0x8c, 0x41, 0x1c, 0x67,
+	0xad, 0x0b, 0x57, 0xb5, 0x6e, 0x1c, 0xc6, 0xd2, 0xda, 0xdd, 0x5f, 0x39, 0x46, 0x57, 0x34, 0xba,
+	0x8c, 0x33, 0xbe, 0x67, 0x52, 0x5f, 0xcb, 0x33, 0x59, 0xe9, 0x2a, 0x19, 0x59, 0xcb, 0x93, 0xa9,
+	0x93, 0x25, 0x18, 0x4d, 0xd9, 0x68, 0x8e, 0x84, 0x76, 0xf4, 0x9f, 0x48, 0x39, 0x85, 0x2b, 0xa4,
+	0xf0, 0x86, 0x12, 0x68, 0xce, 0xa9, 0x91, 0xf2, 0x08, 0x65, 0x8c, 0x02, 0xa8, 0xa5, 0x3f, 0x4f,
+	0x03, 0x01, 0x34, 0xef, 0x54, 0x49, 0x71, 0xb2, 0x03, 0x99, 0x50, 0xbb, 0x3f, 0x21, 0x95, 0xd3,
+	0xf2, 0xea, 0xa4, 0x32, 0x0e, 0x62, 0x7f, 0x25, 0x60, 0x4d, 0x73, 0x06, 0xd2, 0x4b, 0xa3, 0x96,
+	0x8e, 0xf9, 0xf0, 0x95, 0x0c, 0x24, 0xa7, 0x79, 0x1d, 0xf3, 0x2a, 0x37, 0x48, 0x6d, 0x4d, 0x8c,
+	0x61, 0xf5, 0xc5, 0x69, 0x61, 0xf8, 0x40, 0xfe, 0x33, 0x0c, 0x2f, 0xff, 0xd0, 0xb0, 0x76, 0xb8,
+	0x95, 0xb9, 0x3e, 0xa9, 0x4f, 0x5b, 0x20, 0x5f, 0x95, 0xcc, 0x79, 0x3d, 0xfe, 0x04, 0x00, 0x00,
+	0xff, 0xff, 0x09, 0x79, 0xb2, 0xe6, 0x88, 0x02, 0x00, 0x00,
 }
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"strings"
+	"time"
+
+	"v2ray.com/core/common/log/internal"
+)
+
+// DNSQuery is a log of a domain resolved by DNS. Empty fields are written as "-".
+type DNSQuery struct {
+	Source   string
+	Inbound  string
+	User     string
+	Domain   string
+	Answer   string
+	Upstream string
+	Latency  time.Duration
+}
+
+func orDash(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
+
+func (q *DNSQuery) String() string {
+	return strings.Join([]string{
+		orDash(q.Source),
+		"[" + orDash(q.Inbound) + "]",
+		orDash(q.User),
+		orDash(q.Domain),
+		orDash(q.Answer),
+		orDash(q.Upstream),
+		q.Latency.String(),
+	}, " ")
+}
+
+var (
+	dnsLoggerInstance internal.LogWriter = new(internal.NoOpLogWriter)
+)
+
+// InitDNSLogger initializes the DNS query logger to write into the given file.
+func InitDNSLogger(file string) error {
+	logger, err := internal.NewFileLogWriter(file)
+	if err != nil {
+		Error("Failed to create DNS logger on file (", file, "): ", err)
+		return err
+	}
+	dnsLoggerInstance = logger
+	return nil
+}
+
+// DNS writes a DNS query log.
+func DNS(query *DNSQuery) {
+	dnsLoggerInstance.Log(query)
+}
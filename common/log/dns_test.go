@@ -0,0 +1,31 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	. "v2ray.com/core/common/log"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestDNSQueryString(t *testing.T) {
+	assert := assert.On(t)
+
+	query := &DNSQuery{
+		Source:   "tcp:127.0.0.1:1080",
+		Inbound:  "socks",
+		User:     "love@v2ray.com",
+		Domain:   "v2ray.com",
+		Answer:   "10.0.0.1,10.0.0.2",
+		Upstream: "udp:8.8.8.8:53",
+		Latency:  time.Millisecond * 12,
+	}
+	assert.String(query.String()).Equals("tcp:127.0.0.1:1080 [socks] love@v2ray.com v2ray.com 10.0.0.1,10.0.0.2 udp:8.8.8.8:53 12ms")
+
+	query = &DNSQuery{
+		Domain:   "ads.example.com",
+		Answer:   "NXDOMAIN",
+		Upstream: "block",
+	}
+	assert.String(query.String()).Equals("- [-] - ads.example.com NXDOMAIN block 0s")
+}
@@ -1,8 +1,10 @@
 package internal
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"v2ray.com/core/common/platform"
@@ -43,10 +45,12 @@ func (v *StdOutLogWriter) Close() {
 }
 
 type FileLogWriter struct {
-	queue  chan string
-	logger *log.Logger
-	file   *os.File
-	cancel *signal.CancelSignal
+	// dropped is the number of entries dropped since the last entry written, as the queue was full.
+	dropped uint32
+	queue   chan string
+	logger  *log.Logger
+	file    *os.File
+	cancel  *signal.CancelSignal
 }
 
 func (v *FileLogWriter) Log(log LogEntry) {
@@ -54,6 +58,7 @@ func (v *FileLogWriter) Log(log LogEntry) {
 	case v.queue <- log.String():
 	default:
 		// We don't expect this to happen, but don't want to block main thread as well.
+		atomic.AddUint32(&v.dropped, 1)
 	}
 }
 
@@ -66,6 +71,9 @@ func (v *FileLogWriter) run() {
 		if !open {
 			break
 		}
+		if dropped := atomic.SwapUint32(&v.dropped, 0); dropped > 0 {
+			v.logger.Print(fmt.Sprint("Log: ", dropped, " entries are dropped as the log writer is busy.") + platform.LineSeparator())
+		}
 		v.logger.Print(entry + platform.LineSeparator())
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"syscall"
 
 	"v2ray.com/core"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 
 	_ "v2ray.com/core/main/distro/all"
@@ -43,10 +44,9 @@ func GetConfigFormat() core.ConfigFormat {
 	}
 }
 
-func startV2Ray() *core.Point {
+func loadConfig() (*core.Config, error) {
 	if len(configFile) == 0 {
-		log.Error("Config file is not set.")
-		return nil
+		return nil, errors.New("Config file is not set.")
 	}
 	var configInput io.Reader
 	if configFile == "stdin:" {
@@ -55,15 +55,22 @@ func startV2Ray() *core.Point {
 		fixedFile := os.ExpandEnv(configFile)
 		file, err := os.Open(fixedFile)
 		if err != nil {
-			log.Error("Config file not readable: ", err)
-			return nil
+			return nil, errors.Base(err).Message("Config file not readable.")
 		}
 		defer file.Close()
 		configInput = file
 	}
 	config, err := core.LoadConfig(GetConfigFormat(), configInput)
 	if err != nil {
-		log.Error("Failed to read config file (", configFile, "): ", configFile, err)
+		return nil, errors.Base(err).Message("Failed to read config file (", configFile, ").")
+	}
+	return config, nil
+}
+
+func startV2Ray() *core.Point {
+	config, err := loadConfig()
+	if err != nil {
+		log.Error(err)
 		return nil
 	}
 
@@ -78,6 +85,13 @@ func startV2Ray() *core.Point {
 		return nil
 	}
 
+	if len(*testRoute) > 0 {
+		if err := printRoute(vPoint); err != nil {
+			log.Error("Failed to test route: ", err)
+		}
+		return nil
+	}
+
 	err = vPoint.Start()
 	if err != nil {
 		log.Error("Error starting Point server: ", err)
@@ -98,9 +112,26 @@ func main() {
 
 	if point := startV2Ray(); point != nil {
 		osSignals := make(chan os.Signal, 1)
-		signal.Notify(osSignals, os.Interrupt, os.Kill, syscall.SIGTERM)
-
-		<-osSignals
+		signal.Notify(osSignals, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGHUP)
+
+		for sig := range osSignals {
+			if sig != syscall.SIGHUP {
+				break
+			}
+			if configFile == "stdin:" {
+				log.Warning("Unable to reload config from stdin.")
+				continue
+			}
+			log.Warning("Reloading config file: ", configFile)
+			config, err := loadConfig()
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			if err := point.Reload(config); err != nil {
+				log.Error(err)
+			}
+		}
 		point.Close()
 	}
 	log.Close()
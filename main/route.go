@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/errors"
+	v2net "v2ray.com/core/common/net"
+)
+
+var (
+	testRoute       = flag.String("test-route", "", "Show how the routing rules handle a connection to the given destination, such as tcp:v2ray.com:443, without launching V2Ray server.")
+	routeSource     = flag.String("route-source", "", "Source of the connection for -test-route, such as 192.168.1.2:50000.")
+	routeInboundTag = flag.String("route-inbound-tag", "", "Inbound tag of the connection for -test-route.")
+	routeUserEmail  = flag.String("route-user", "", "User email of the connection for -test-route.")
+	routeUserLevel  = flag.Uint("route-user-level", 0, "User level of the connection for -test-route.")
+	routeAttributes = flag.String("route-attrs", "", "Attributes of the connection for -test-route, such as http.method=GET,http.path=/.")
+)
+
+// parseEndpoint parses an endpoint in the form of [network:]address:port. Network defaults to TCP.
+func parseEndpoint(s string) (*v2net.Endpoint, error) {
+	network := v2net.Network_TCP
+	if idx := strings.Index(s, ":"); idx > 0 {
+		switch strings.ToLower(s[:idx]) {
+		case "tcp":
+			s = s[idx+1:]
+		case "udp":
+			network = v2net.Network_UDP
+			s = s[idx+1:]
+		}
+	}
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return nil, errors.Base(err).Message("Invalid endpoint: ", s)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Base(err).Message("Invalid port: ", portStr)
+	}
+	return &v2net.Endpoint{
+		Network: network,
+		Address: v2net.NewIPOrDomain(v2net.ParseAddress(host)),
+		Port:    uint32(port),
+	}, nil
+}
+
+func buildRouteRequest() (*router.RouteRequest, error) {
+	dest, err := parseEndpoint(*testRoute)
+	if err != nil {
+		return nil, err
+	}
+	request := &router.RouteRequest{
+		Destination: dest,
+		InboundTag:  *routeInboundTag,
+		UserEmail:   *routeUserEmail,
+		UserLevel:   uint32(*routeUserLevel),
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "route-user" || f.Name == "route-user-level" {
+			request.HasUser = true
+		}
+	})
+	if len(*routeAttributes) > 0 {
+		request.Attribute = make(map[string]string)
+		for _, attribute := range strings.Split(*routeAttributes, ",") {
+			parts := strings.SplitN(attribute, "=", 2)
+			if len(parts) != 2 {
+				return nil, errors.New("Invalid attribute: ", attribute)
+			}
+			request.Attribute[parts[0]] = parts[1]
+		}
+	}
+	if len(*routeSource) > 0 {
+		source, err := parseEndpoint(*routeSource)
+		if err != nil {
+			return nil, err
+		}
+		request.Source = source
+	}
+	return request, nil
+}
+
+func printRoute(point *core.Point) error {
+	request, err := buildRouteRequest()
+	if err != nil {
+		return err
+	}
+	explanation, err := point.TestRoute(request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Destination:", request.Destination.AsDestination())
+	for _, idx := range explanation.SkippedRule {
+		fmt.Println("Skipped rule:", idx, "(outbound is down)")
+	}
+	if explanation.Resolved {
+		ips := make([]string, len(explanation.ResolvedIp))
+		for idx, ip := range explanation.ResolvedIp {
+			ips[idx] = ip.AsAddress().String()
+		}
+		fmt.Println("Resolved IPs:", strings.Join(ips, ", "), "(no rule matched the domain)")
+	}
+	if explanation.RuleIndex < 0 {
+		fmt.Println("Matched rule: none, using the default outbound")
+		return nil
+	}
+	fmt.Println("Matched rule:", explanation.RuleIndex, "("+strings.Join(explanation.Condition, ", ")+")")
+	if len(explanation.BalancingTag) > 0 {
+		fmt.Println("Balancer:", explanation.BalancingTag)
+	}
+	fmt.Println("Outbound:", explanation.OutboundTag)
+	return nil
+}
@@ -18,6 +18,7 @@ const (
 	outboundTagKey
 	resolvedIPsKey
 	allowPassiveConnKey
+	attributesKey
 )
 
 func ContextWithDialer(ctx context.Context, dialer Dialer) context.Context {
@@ -121,3 +122,24 @@ func AllowPassiveConnectionFromContext(ctx context.Context) (bool, bool) {
 	allow, ok := ctx.Value(allowPassiveConnKey).(bool)
 	return allow, ok
 }
+
+// ContextWithAttributes returns a context with the given attributes of the connection, in addition to the attributes
+// already in ctx. Attributes are what inbound handlers know about the connection, such as "http.method" or
+// "socks.username".
+func ContextWithAttributes(ctx context.Context, attributes map[string]string) context.Context {
+	existing := AttributesFromContext(ctx)
+	merged := make(map[string]string, len(existing)+len(attributes))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range attributes {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, attributesKey, merged)
+}
+
+// AttributesFromContext returns the attributes of the connection. The returned map must not be modified.
+func AttributesFromContext(ctx context.Context) map[string]string {
+	attributes, _ := ctx.Value(attributesKey).(map[string]string)
+	return attributes
+}
@@ -0,0 +1,20 @@
+package dns
+
+import (
+	"v2ray.com/core/common/net"
+)
+
+var defaultServer = net.UDPDestination(net.IPAddress([]byte{8, 8, 8, 8}), net.Port(53))
+
+// GetServerDestination returns the upstream server for queries other than A and AAAA.
+func (v *Config) GetServerDestination() net.Destination {
+	if v.Server == nil {
+		return defaultServer
+	}
+	dest := v.Server.AsDestination()
+	dest.Network = net.Network_UDP
+	if dest.Port == 0 {
+		dest.Port = net.Port(53)
+	}
+	return dest
+}
@@ -0,0 +1,58 @@
+package dns
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_common_net2 "v2ray.com/core/common/net"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Config for the DNS server inbound.
+type Config struct {
+	// Upstream server for queries other than A and AAAA, over UDP. Defaults to 8.8.8.8:53.
+	Server *v2ray_core_common_net2.Endpoint `protobuf:"bytes,1,opt,name=server" json:"server,omitempty"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Config) GetServer() *v2ray_core_common_net2.Endpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.dns.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/proxy/dns/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 193 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0xce, 0x31, 0xcb, 0xc2, 0x30,
+	0x10, 0xc6, 0x71, 0xfa, 0xbe, 0xd0, 0x21, 0xdd, 0x4a, 0x87, 0xe2, 0xa2, 0x08, 0x82, 0x20, 0x5c,
+	0xa0, 0x0e, 0xce, 0xb6, 0xba, 0x97, 0x0e, 0x0e, 0x6e, 0x35, 0x89, 0x92, 0x21, 0x77, 0x25, 0x09,
+	0xc5, 0x7e, 0x25, 0x3f, 0xa5, 0x34, 0x55, 0x10, 0x71, 0xff, 0xdf, 0xef, 0x1e, 0xb6, 0xea, 0x0b,
+	0xdb, 0x0e, 0x20, 0xc8, 0x70, 0x41, 0x56, 0xf1, 0xce, 0xd2, 0x7d, 0xe0, 0x12, 0x1d, 0x17, 0x84,
+	0x57, 0x7d, 0x83, 0xce, 0x92, 0xa7, 0x34, 0x7b, 0x67, 0x56, 0x41, 0x48, 0x40, 0xa2, 0x9b, 0x6d,
+	0xbe, 0x8e, 0x05, 0x19, 0x43, 0xc8, 0x51, 0x79, 0x2e, 0x95, 0xf3, 0x1a, 0x5b, 0xaf, 0x09, 0x27,
+	0x62, 0xb9, 0x67, 0x71, 0x15, 0xc8, 0x74, 0xc7, 0x62, 0xa7, 0x6c, 0xaf, 0x6c, 0x1e, 0x2d, 0xa2,
+	0x75, 0x52, 0xcc, 0xe1, 0x43, 0x9f, 0x0c, 0x40, 0xe5, 0xe1, 0x88, 0xb2, 0x23, 0x8d, 0xbe, 0x79,
+	0xe5, 0x65, 0xc5, 0x72, 0x41, 0x06, 0x7e, 0x6d, 0x29, 0x93, 0x09, 0xaf, 0xc7, 0x5f, 0xe7, 0x7f,
+	0x89, 0xee, 0xf1, 0x97, 0x9d, 0x8a, 0xa6, 0x1d, 0xa0, 0x1a, 0xc3, 0x3a, 0x84, 0x07, 0x74, 0x97,
+	0x38, 0xcc, 0xd9, 0x3e, 0x03, 0x00, 0x00, 0xff, 0xff, 0x4c, 0xa7, 0x4a, 0xbb, 0xfa, 0x00, 0x00,
+	0x00,
+}
@@ -0,0 +1,317 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	v2dns "v2ray.com/core/app/dns"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+)
+
+const (
+	// TTL of fake IPs, and of records answered from DNS apps that don't tell the TTL.
+	answerTTL = 60
+	// Idle timeout of TCP connections in seconds.
+	tcpIdleTimeout = 16
+	forwardTimeout = time.Second * 8
+	// Maximum number of queries answered at the same time. Connections stop reading queries when it is reached.
+	maxConcurrentQueries = 256
+	// Maximum size of responses over TCP.
+	maxTCPMsgSize = 65535
+)
+
+// Server is an inbound handler that serves DNS queries over UDP and TCP. A and AAAA queries are answered from
+// the DNS app, and other queries are forwarded to an upstream server through the dispatcher.
+type Server struct {
+	upstream   net.Destination
+	dns        v2dns.Server
+	dispatcher dispatcher.Interface
+	// queries limits the number of queries being answered.
+	queries chan struct{}
+}
+
+func New(ctx context.Context, config *Config) (*Server, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, errors.New("DNS|Server: No space in context.")
+	}
+	s := &Server{
+		upstream: config.GetServerDestination(),
+		queries:  make(chan struct{}, maxConcurrentQueries),
+	}
+	space.OnInitialize(func() error {
+		s.dns = v2dns.FromSpace(space)
+		if s.dns == nil {
+			return errors.New("DNS|Server: DNS is not found in the space.")
+		}
+		s.dispatcher = dispatcher.FromSpace(space)
+		if s.dispatcher == nil {
+			return errors.New("DNS|Server: Dispatcher is not found in the space.")
+		}
+		return nil
+	})
+	return s, nil
+}
+
+func (*Server) Network() net.NetworkList {
+	return net.NetworkList{
+		Network: []net.Network{net.Network_TCP, net.Network_UDP},
+	}
+}
+
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection) error {
+	conn.SetReusable(false)
+	if network == net.Network_TCP {
+		return s.processTCP(ctx, conn)
+	}
+	return s.processUDP(ctx, conn)
+}
+
+// serve answers the given query in background, once the number of queries being answered is under the limit. The
+// given wait group is done when the answer is replied, so that the connection is kept until then.
+func (s *Server) serve(ctx context.Context, wg *sync.WaitGroup, query []byte, maxSize int, reply func(response []byte)) {
+	select {
+	case s.queries <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() { <-s.queries }()
+
+		if response := s.handle(ctx, query, maxSize); response != nil {
+			reply(response)
+		}
+	}()
+}
+
+func (s *Server) processUDP(ctx context.Context, conn internet.Connection) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		payload := make([]byte, buf.Size)
+		nBytes, err := conn.Read(payload)
+		if err != nil {
+			return nil
+		}
+		s.serve(ctx, &wg, payload[:nBytes], dnsmsg.MinMsgSize, func(response []byte) {
+			conn.Write(response)
+		})
+	}
+}
+
+// processTCP serves length prefixed queries on the given connection, as in RFC 1035 4.2.2. Queries are answered
+// concurrently, so responses may be out of order.
+func (s *Server) processTCP(ctx context.Context, conn internet.Connection) error {
+	reader := bufio.NewReader(net.NewTimeOutReader(tcpIdleTimeout, conn))
+	var writeLock sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		var length uint16
+		if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+			return nil
+		}
+		query := make([]byte, length)
+		if _, err := io.ReadFull(reader, query); err != nil {
+			return errors.Base(err).Message("DNS|Server: Failed to read query.")
+		}
+		s.serve(ctx, &wg, query, maxTCPMsgSize, func(response []byte) {
+			payload := make([]byte, 2+len(response))
+			binary.BigEndian.PutUint16(payload, uint16(len(response)))
+			copy(payload[2:], response)
+
+			writeLock.Lock()
+			defer writeLock.Unlock()
+			conn.Write(payload)
+		})
+	}
+}
+
+// handle returns the response to the given query, or nil if the query is invalid. Answers from the DNS app larger
+// than the given size, or the UDP payload size the query advertises, are truncated.
+func (s *Server) handle(ctx context.Context, query []byte, maxSize int) []byte {
+	msg := new(dnsmsg.Msg)
+	if err := msg.Unpack(query); err != nil {
+		log.Info("DNS|Server: Invalid query: ", err)
+		return nil
+	}
+
+	reply := s.answer(ctx, msg)
+	if reply == nil {
+		response, err := s.forward(ctx, query)
+		if err == nil {
+			return response
+		}
+		log.Info("DNS|Server: Failed to forward query: ", err)
+		reply = new(dnsmsg.Msg)
+		reply.SetRcode(msg, dnsmsg.RcodeServerFailure)
+	}
+
+	if opt := msg.IsEdns0(); opt != nil && int(opt.UDPSize()) > maxSize && maxSize < maxTCPMsgSize {
+		maxSize = int(opt.UDPSize())
+	}
+	return pack(reply, maxSize)
+}
+
+// pack returns the given message in wire format. If it is larger than the given size, answers are dropped from the
+// end until it fits, and the message is marked as truncated.
+func pack(msg *dnsmsg.Msg, maxSize int) []byte {
+	for {
+		response, err := msg.Pack()
+		if err != nil {
+			log.Warning("DNS|Server: Failed to pack response: ", err)
+			return nil
+		}
+		if len(response) <= maxSize || len(msg.Answer) == 0 {
+			return response
+		}
+		msg.Truncated = true
+		msg.Answer = msg.Answer[:len(msg.Answer)-1]
+	}
+}
+
+// answer returns the response to A and AAAA queries from the DNS app, or nil for other queries. Domains blocked by
+// the DNS app are answered as nonexistent.
+func (s *Server) answer(ctx context.Context, msg *dnsmsg.Msg) *dnsmsg.Msg {
+	if msg.Opcode != dnsmsg.OpcodeQuery || len(msg.Question) != 1 {
+		return nil
+	}
+	question := msg.Question[0]
+	if question.Qclass != dnsmsg.ClassINET {
+		return nil
+	}
+	var strategy v2dns.Config_QueryStrategy
+	switch question.Qtype {
+	case dnsmsg.TypeA:
+		strategy = v2dns.Config_USE_IP4
+	case dnsmsg.TypeAAAA:
+		strategy = v2dns.Config_USE_IP6
+	default:
+		return nil
+	}
+
+	domain := strings.ToLower(strings.TrimSuffix(question.Name, "."))
+	var addresses []net.Address
+	ttl := uint32(answerTTL)
+	if fakeIP, isFake := s.getFakeIP(ctx, domain, question.Qtype == dnsmsg.TypeAAAA); isFake {
+		// Fake IPs of the other family are not handed out, and the answer is empty then.
+		if fakeIP != nil {
+			addresses = []net.Address{fakeIP}
+		}
+	} else {
+		var err error
+		addresses, ttl, err = s.lookup(ctx, domain, strategy)
+		switch {
+		case err == v2dns.ErrBlocked, err == v2dns.ErrNotExist:
+			log.Debug("DNS|Server: Domain ", domain, " does not exist or is blocked.")
+			reply := new(dnsmsg.Msg)
+			reply.SetRcode(msg, dnsmsg.RcodeNameError)
+			reply.RecursionAvailable = true
+			return reply
+		case err != nil:
+			log.Info("DNS|Server: Failed to resolve domain ", domain, ": ", err)
+			reply := new(dnsmsg.Msg)
+			reply.SetRcode(msg, dnsmsg.RcodeServerFailure)
+			reply.RecursionAvailable = true
+			return reply
+		}
+	}
+	log.Debug("DNS|Server: Answering ", len(addresses), " IPs for domain ", domain)
+
+	reply := new(dnsmsg.Msg)
+	reply.SetReply(msg)
+	reply.RecursionAvailable = true
+	header := dnsmsg.RR_Header{
+		Name:   question.Name,
+		Rrtype: question.Qtype,
+		Class:  dnsmsg.ClassINET,
+		Ttl:    ttl,
+	}
+	for _, address := range addresses {
+		switch {
+		case question.Qtype == dnsmsg.TypeA && address.Family().IsIPv4():
+			reply.Answer = append(reply.Answer, &dnsmsg.A{Hdr: header, A: address.IP()})
+		case question.Qtype == dnsmsg.TypeAAAA && address.Family().IsIPv6():
+			reply.Answer = append(reply.Answer, &dnsmsg.AAAA{Hdr: header, AAAA: address.IP()})
+		}
+	}
+	return reply
+}
+
+// lookup returns the IPs of the given domain from the DNS app and their TTL in seconds, with the context of the query
+// if the DNS app takes it.
+func (s *Server) lookup(ctx context.Context, domain string, strategy v2dns.Config_QueryStrategy) ([]net.Address, uint32, error) {
+	var addresses []net.Address
+	if server, ok := s.dns.(v2dns.ContextServer); ok {
+		ips, ttl, err := server.GetWithStrategyContext(ctx, domain, strategy)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, ip := range ips {
+			addresses = append(addresses, net.IPAddress(ip))
+		}
+		return addresses, uint32(ttl / time.Second), nil
+	}
+	for _, ip := range s.dns.GetWithStrategy(domain, strategy) {
+		addresses = append(addresses, net.IPAddress(ip))
+	}
+	return addresses, answerTTL, nil
+}
+
+// getFakeIP returns the fake IP of the given family for the given domain, and whether the domain is answered with fake
+// IPs, if the DNS app hands out fake IPs.
+func (s *Server) getFakeIP(ctx context.Context, domain string, ipv6 bool) (net.Address, bool) {
+	server, ok := s.dns.(v2dns.FakeIPServer)
+	if !ok || len(domain) == 0 {
+		return nil, false
+	}
+	ip, isFake := server.GetFakeIP(ctx, domain, ipv6)
+	if ip == nil {
+		return nil, isFake
+	}
+	return net.IPAddress(ip), isFake
+}
+
+// forward sends the given query to the upstream server through the dispatcher, and returns its response.
+func (s *Server) forward(ctx context.Context, query []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, forwardTimeout)
+	defer cancel()
+	ctx = proxy.ContextWithDestination(ctx, s.upstream)
+	inboundRay := s.dispatcher.DispatchToOutbound(ctx)
+	defer inboundRay.InboundOutput().CloseError()
+	defer inboundRay.InboundInput().Close()
+
+	payload := buf.New()
+	payload.Append(query)
+	if err := inboundRay.InboundInput().Write(payload); err != nil {
+		return nil, err
+	}
+	response, err := inboundRay.InboundOutput().ReadTimeout(forwardTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Release()
+	return append([]byte(nil), response.Bytes()...), nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,374 @@
+package dns_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	v2dns "v2ray.com/core/app/dns"
+	_ "v2ray.com/core/app/dns/server"
+	v2net "v2ray.com/core/common/net"
+	. "v2ray.com/core/proxy/dns"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+// upstreamDispatcher answers all queries dispatched to it with an MX record.
+type upstreamDispatcher struct{}
+
+func (upstreamDispatcher) Interface() interface{} {
+	return (*dispatcher.Interface)(nil)
+}
+
+func (upstreamDispatcher) DispatchToOutbound(ctx context.Context) ray.InboundRay {
+	stream := ray.NewRay(ctx)
+	go func() {
+		defer stream.OutboundOutput().Close()
+		payload, err := stream.OutboundInput().Read()
+		if err != nil {
+			return
+		}
+		query := new(dnsmsg.Msg)
+		if err := query.Unpack(payload.Bytes()); err != nil {
+			return
+		}
+		reply := new(dnsmsg.Msg)
+		reply.SetReply(query)
+		reply.Answer = append(reply.Answer, &dnsmsg.MX{
+			Hdr:        dnsmsg.RR_Header{Name: query.Question[0].Name, Rrtype: dnsmsg.TypeMX, Class: dnsmsg.ClassINET, Ttl: 300},
+			Preference: 10,
+			Mx:         "mail.v2ray.com.",
+		})
+		response, err := reply.Pack()
+		if err != nil {
+			return
+		}
+		payload.Clear()
+		payload.Append(response)
+		stream.OutboundOutput().Write(payload)
+	}()
+	return stream
+}
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (pipeConn) Reusable() bool {
+	return false
+}
+
+func (pipeConn) SetReusable(bool) {}
+
+func exchange(t *testing.T, conn net.Conn, name string, qtype uint16) *dnsmsg.Msg {
+	query := new(dnsmsg.Msg)
+	query.SetQuestion(name, qtype)
+	payload, err := query.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := binary.Write(conn, binary.BigEndian, uint16(len(payload))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write(payload); err != nil {
+		t.Fatal(err)
+	}
+
+	var length uint16
+	if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+		t.Fatal(err)
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		t.Fatal(err)
+	}
+	reply := new(dnsmsg.Msg)
+	if err := reply.Unpack(payload); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Id != query.Id {
+		t.Error("unexpected id: ", reply.Id)
+	}
+	return reply
+}
+
+func TestDNSServer(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(space.AddApplication(upstreamDispatcher{})).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, &v2dns.Config{
+		Hosts: map[string]*v2net.IPOrDomain{
+			"v2ray.com": v2net.NewIPOrDomain(v2net.IPAddress([]byte{10, 0, 0, 1})),
+		},
+	})).IsNil()
+	server, err := New(ctx, &Config{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	client, serverConn := net.Pipe()
+	defer client.Close()
+	go server.Process(ctx, v2net.Network_TCP, pipeConn{serverConn})
+
+	reply := exchange(t, client, "V2Ray.com.", dnsmsg.TypeA)
+	assert.Int(len(reply.Answer)).Equals(1)
+	a, ok := reply.Answer[0].(*dnsmsg.A)
+	assert.Bool(ok).IsTrue()
+	assert.String(a.A.String()).Equals("10.0.0.1")
+	assert.String(a.Hdr.Name).Equals("V2Ray.com.")
+
+	reply = exchange(t, client, "v2ray.com.", dnsmsg.TypeAAAA)
+	assert.Bool(reply.Rcode == dnsmsg.RcodeSuccess).IsTrue()
+	assert.Int(len(reply.Answer)).Equals(0)
+
+	reply = exchange(t, client, "v2ray.com.", dnsmsg.TypeMX)
+	assert.Int(len(reply.Answer)).Equals(1)
+	mx, ok := reply.Answer[0].(*dnsmsg.MX)
+	assert.Bool(ok).IsTrue()
+	assert.String(mx.Mx).Equals("mail.v2ray.com.")
+}
+
+func TestDNSServerFakeIP(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(space.AddApplication(upstreamDispatcher{})).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, &v2dns.Config{
+		FakeIpPool: &v2dns.FakeIPPool{
+			Cidr: &v2dns.CIDR{Ip: []byte{198, 18, 0, 0}, Prefix: 15},
+		},
+	})).IsNil()
+	server, err := New(ctx, &Config{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	client, serverConn := net.Pipe()
+	defer client.Close()
+	go server.Process(ctx, v2net.Network_TCP, pipeConn{serverConn})
+
+	reply := exchange(t, client, "v2ray.com.", dnsmsg.TypeA)
+	assert.Int(len(reply.Answer)).Equals(1)
+	a, ok := reply.Answer[0].(*dnsmsg.A)
+	assert.Bool(ok).IsTrue()
+	assert.String(a.A.String()).Equals("198.18.0.1")
+
+	reply = exchange(t, client, "v2ray.com.", dnsmsg.TypeAAAA)
+	assert.Int(len(reply.Answer)).Equals(0)
+
+	// No fake IP is allocated for AAAA queries, as the fake IPs are IPv4 addresses.
+	reply = exchange(t, client, "v2fly.org.", dnsmsg.TypeAAAA)
+	assert.Int(reply.Rcode).Equals(dnsmsg.RcodeSuccess)
+	assert.Int(len(reply.Answer)).Equals(0)
+
+	fakeServer := v2dns.FromSpace(space).(v2dns.FakeIPServer)
+	assert.String(fakeServer.GetFakeDomain(a.A)).Equals("v2ray.com")
+	assert.String(fakeServer.GetFakeDomain(v2net.ParseAddress("198.18.0.2").IP())).Equals("")
+}
+
+func TestDNSServerBlock(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(space.AddApplication(upstreamDispatcher{})).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, &v2dns.Config{
+		BlockRule: []*v2dns.BlockRule{
+			{
+				Domain: []*v2dns.Domain{{Type: v2dns.Domain_Subdomain, Value: "malware.example.com"}},
+			},
+			{
+				Domain:     []*v2dns.Domain{{Type: v2dns.Domain_Full, Value: "ads.example.com"}},
+				SinkholeIp: [][]byte{{0, 0, 0, 0}},
+			},
+		},
+	})).IsNil()
+	server, err := New(ctx, &Config{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	client, serverConn := net.Pipe()
+	defer client.Close()
+	go server.Process(ctx, v2net.Network_TCP, pipeConn{serverConn})
+
+	reply := exchange(t, client, "www.malware.example.com.", dnsmsg.TypeA)
+	assert.Bool(reply.Rcode == dnsmsg.RcodeNameError).IsTrue()
+	assert.Int(len(reply.Answer)).Equals(0)
+
+	reply = exchange(t, client, "ads.example.com.", dnsmsg.TypeA)
+	assert.Bool(reply.Rcode == dnsmsg.RcodeSuccess).IsTrue()
+	assert.Int(len(reply.Answer)).Equals(1)
+	a, ok := reply.Answer[0].(*dnsmsg.A)
+	assert.Bool(ok).IsTrue()
+	assert.String(a.A.String()).Equals("0.0.0.0")
+}
+
+// stubDNS answers all domains with the given IPs and TTL, or the given error, after the given delay.
+type stubDNS struct {
+	ips   []net.IP
+	ttl   time.Duration
+	err   error
+	delay time.Duration
+}
+
+func (*stubDNS) Interface() interface{} {
+	return (*v2dns.Server)(nil)
+}
+
+func (d *stubDNS) Get(domain string) []net.IP {
+	return d.ips
+}
+
+func (d *stubDNS) GetWithStrategy(domain string, strategy v2dns.Config_QueryStrategy) []net.IP {
+	return d.ips
+}
+
+func (d *stubDNS) GetContext(ctx context.Context, domain string) ([]net.IP, error) {
+	return d.ips, d.err
+}
+
+func (d *stubDNS) GetWithStrategyContext(ctx context.Context, domain string, strategy v2dns.Config_QueryStrategy) ([]net.IP, time.Duration, error) {
+	time.Sleep(d.delay)
+	return d.ips, d.ttl, d.err
+}
+
+func newStubServer(t *testing.T, stub *stubDNS) *Server {
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	if err := space.AddApplication(upstreamDispatcher{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := space.AddApplication(stub); err != nil {
+		t.Fatal(err)
+	}
+	server, err := New(ctx, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := space.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+// tcpClient returns a connection to the given server over TCP.
+func tcpClient(server *Server) net.Conn {
+	client, serverConn := net.Pipe()
+	go server.Process(context.Background(), v2net.Network_TCP, pipeConn{serverConn})
+	return client
+}
+
+func TestDNSServerTTLAndFailure(t *testing.T) {
+	assert := assert.On(t)
+
+	stub := &stubDNS{
+		ips: []net.IP{{10, 0, 0, 1}},
+		ttl: time.Second * 42,
+	}
+	client := tcpClient(newStubServer(t, stub))
+	defer client.Close()
+
+	reply := exchange(t, client, "v2ray.com.", dnsmsg.TypeA)
+	assert.Int(len(reply.Answer)).Equals(1)
+	assert.Uint32(reply.Answer[0].Header().Ttl).Equals(42)
+
+	stub.ips = nil
+	stub.err = v2dns.ErrQueryFailed
+	reply = exchange(t, client, "v2ray.com.", dnsmsg.TypeA)
+	assert.Bool(reply.Rcode == dnsmsg.RcodeServerFailure).IsTrue()
+	assert.Int(len(reply.Answer)).Equals(0)
+
+	stub.err = v2dns.ErrNotExist
+	reply = exchange(t, client, "v2ray.com.", dnsmsg.TypeA)
+	assert.Bool(reply.Rcode == dnsmsg.RcodeNameError).IsTrue()
+	assert.Int(len(reply.Answer)).Equals(0)
+}
+
+func TestDNSServerTruncate(t *testing.T) {
+	assert := assert.On(t)
+
+	stub := &stubDNS{ttl: time.Minute}
+	for i := 0; i < 64; i++ {
+		stub.ips = append(stub.ips, net.IP{10, 0, 0, byte(i)})
+	}
+	server := newStubServer(t, stub)
+
+	client, serverConn := net.Pipe()
+	defer client.Close()
+	go server.Process(context.Background(), v2net.Network_UDP, pipeConn{serverConn})
+
+	query := new(dnsmsg.Msg)
+	query.SetQuestion("v2ray.com.", dnsmsg.TypeA)
+	payload, err := query.Pack()
+	assert.Error(err).IsNil()
+	_, err = client.Write(payload)
+	assert.Error(err).IsNil()
+
+	payload = make([]byte, 2048)
+	nBytes, err := client.Read(payload)
+	assert.Error(err).IsNil()
+	assert.Bool(nBytes <= dnsmsg.MinMsgSize).IsTrue()
+	reply := new(dnsmsg.Msg)
+	assert.Error(reply.Unpack(payload[:nBytes])).IsNil()
+	assert.Bool(reply.Truncated).IsTrue()
+	assert.Bool(len(reply.Answer) > 0 && len(reply.Answer) < 64).IsTrue()
+
+	tcpConn := tcpClient(server)
+	defer tcpConn.Close()
+	reply = exchange(t, tcpConn, "v2ray.com.", dnsmsg.TypeA)
+	assert.Bool(reply.Truncated).IsFalse()
+	assert.Int(len(reply.Answer)).Equals(64)
+}
+
+func TestDNSServerRepliesBeforeReturn(t *testing.T) {
+	assert := assert.On(t)
+
+	stub := &stubDNS{
+		ips:   []net.IP{{10, 0, 0, 1}},
+		ttl:   time.Minute,
+		delay: time.Millisecond * 100,
+	}
+	server := newStubServer(t, stub)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Error(err).IsNil()
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		// The connection is closed once Process returns, as inbound handlers do.
+		server.Process(context.Background(), v2net.Network_TCP, pipeConn{conn})
+		conn.Close()
+	}()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	assert.Error(err).IsNil()
+	defer client.Close()
+
+	query := new(dnsmsg.Msg)
+	query.SetQuestion("v2ray.com.", dnsmsg.TypeA)
+	payload, err := query.Pack()
+	assert.Error(err).IsNil()
+	assert.Error(binary.Write(client, binary.BigEndian, uint16(len(payload)))).IsNil()
+	_, err = client.Write(payload)
+	assert.Error(err).IsNil()
+	assert.Error(client.(*net.TCPConn).CloseWrite()).IsNil()
+
+	var length uint16
+	assert.Error(binary.Read(client, binary.BigEndian, &length)).IsNil()
+	payload = make([]byte, length)
+	_, err = io.ReadFull(client, payload)
+	assert.Error(err).IsNil()
+	reply := new(dnsmsg.Msg)
+	assert.Error(reply.Unpack(payload)).IsNil()
+	assert.Int(len(reply.Answer)).Equals(1)
+}
@@ -49,15 +49,18 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 }
 
 // Private: Visible for testing.
-func (v *Handler) ResolveIP(destination net.Destination) net.Destination {
+func (v *Handler) ResolveIP(ctx context.Context, destination net.Destination) (net.Destination, error) {
 	if !destination.Address.Family().IsDomain() {
-		return destination
+		return destination, nil
 	}
 
-	ips := v.dns.Get(destination.Address.Domain())
+	ips, err := dns.GetContext(ctx, v.dns, destination.Address.Domain())
+	if err == dns.ErrBlocked {
+		return destination, err
+	}
 	if len(ips) == 0 {
 		log.Info("Freedom: DNS returns nil answer. Keep domain as is.")
-		return destination
+		return destination, nil
 	}
 
 	ip := ips[dice.Roll(len(ips))]
@@ -68,7 +71,7 @@ func (v *Handler) ResolveIP(destination net.Destination) net.Destination {
 		newDest = net.UDPDestination(net.IPAddress(ip), destination.Port)
 	}
 	log.Info("Freedom: Changing destination from ", destination, " to ", newDest)
-	return newDest
+	return newDest, nil
 }
 
 func (v *Handler) Process(ctx context.Context, outboundRay ray.OutboundRay) error {
@@ -88,7 +91,14 @@ func (v *Handler) Process(ctx context.Context, outboundRay ray.OutboundRay) erro
 
 	var conn internet.Connection
 	if v.domainStrategy == Config_USE_IP && destination.Address.Family().IsDomain() {
-		destination = v.ResolveIP(destination)
+		resolved, err := v.ResolveIP(ctx, destination)
+		if err != nil {
+			log.Info("Freedom: Dropping connection to ", destination, ": ", err)
+			input.CloseError()
+			output.CloseError()
+			return err
+		}
+		destination = resolved
 	}
 
 	dialer := proxy.DialerFromContext(ctx)
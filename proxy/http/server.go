@@ -106,6 +106,10 @@ func (s *Server) Process(ctx context.Context, network v2net.Network, conn intern
 	}
 	log.Access(conn.RemoteAddr(), request.URL, log.AccessAccepted, "")
 	ctx = proxy.ContextWithDestination(ctx, dest)
+	ctx = proxy.ContextWithAttributes(ctx, map[string]string{
+		"http.method": request.Method,
+		"http.path":   request.URL.Path,
+	})
 	if strings.ToUpper(request.Method) == "CONNECT" {
 		return s.handleConnect(ctx, request, reader, conn)
 	} else {
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/ray"
 )
@@ -25,3 +26,13 @@ type Outbound interface {
 type Dialer interface {
 	Dial(ctx context.Context, destination net.Destination) (internet.Connection, error)
 }
+
+// UserManager is the interface for proxies that are able to add and remove users at runtime.
+type UserManager interface {
+	// AddUser adds a new user.
+	AddUser(ctx context.Context, user *protocol.User) error
+
+	// RemoveUser removes the user with the given email. Emails are case insensitive. New connections from the user
+	// are rejected immediately, and existing connections from the user are closed.
+	RemoveUser(ctx context.Context, email string) error
+}
@@ -37,6 +37,9 @@ const (
 type ServerSession struct {
 	config *ServerConfig
 	port   v2net.Port
+
+	// username is the name that the client authenticated with, if any.
+	username string
 }
 
 func (s *ServerSession) Handshake(reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
@@ -116,6 +119,7 @@ func (s *ServerSession) Handshake(reader io.Reader, writer io.Writer) (*protocol
 			if err := writeSocks5AuthenticationResponse(writer, 0x00); err != nil {
 				return nil, err
 			}
+			s.username = username
 		}
 		buffer.Clear()
 		if err := buffer.AppendSupplier(buf.ReadFullFrom(reader, 4)); err != nil {
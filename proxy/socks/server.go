@@ -3,6 +3,7 @@ package socks
 import (
 	"context"
 	"io"
+	"sync"
 	"time"
 
 	"v2ray.com/core/app"
@@ -20,11 +21,23 @@ import (
 	"v2ray.com/core/transport/internet/udp"
 )
 
+// udpAssociation is a UDP association in progress, with the client port it declares and its username.
+type udpAssociation struct {
+	// port is the port the client sends UDP packets from, or 0 if the client doesn't declare it.
+	port     net.Port
+	username string
+}
+
 // Server is a SOCKS 5 proxy server
 type Server struct {
 	packetDispatcher dispatcher.Interface
 	config           *ServerConfig
 	udpServer        *udp.Dispatcher
+
+	// UDP packets come without authentication, so their usernames are taken from the UDP associations of the same
+	// client, by client IP.
+	udpAccess       sync.Mutex
+	udpAssociations map[string][]*udpAssociation
 }
 
 // NewServer creates a new Server object.
@@ -34,7 +47,8 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		return nil, errors.New("Socks|Server: No space in context.")
 	}
 	s := &Server{
-		config: config,
+		config:          config,
+		udpAssociations: make(map[string][]*udpAssociation),
 	}
 	space.OnInitialize(func() error {
 		s.packetDispatcher = dispatcher.FromSpace(space)
@@ -95,17 +109,34 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection) error
 
 		timedReader.SetTimeOut(s.config.Timeout)
 		ctx = proxy.ContextWithDestination(ctx, dest)
+		ctx = contextWithUsername(ctx, session.username)
 		return s.transport(ctx, reader, conn)
 	}
 
 	if request.Command == protocol.RequestCommandUDP {
-		return s.handleUDP()
+		return s.handleUDP(source, request.Port, session.username)
 	}
 
 	return nil
 }
 
-func (*Server) handleUDP() error {
+// contextWithUsername returns a context with the given username as the "socks.username" attribute, if it is not
+// empty.
+func contextWithUsername(ctx context.Context, username string) context.Context {
+	if len(username) == 0 {
+		return ctx
+	}
+	return proxy.ContextWithAttributes(ctx, map[string]string{
+		"socks.username": username,
+	})
+}
+
+// handleUDP keeps the UDP association from the given source, with the client port it declares, until it finishes.
+func (s *Server) handleUDP(source net.Destination, port net.Port, username string) error {
+	if len(username) > 0 && source.IsValid() {
+		defer s.AddUDPAssociation(source, port, username)()
+	}
+
 	// The TCP connection closes after v method returns. We need to wait until
 	// the client closes it.
 	// TODO: get notified from UDP part
@@ -114,6 +145,67 @@ func (*Server) handleUDP() error {
 	return nil
 }
 
+// AddUDPAssociation adds a UDP association from the IP of the given source, with the client port it declares and its
+// username. It returns a function to remove the association.
+// Private: Visible for testing.
+func (s *Server) AddUDPAssociation(source net.Destination, port net.Port, username string) func() {
+	ip := source.Address.String()
+	association := &udpAssociation{
+		port:     port,
+		username: username,
+	}
+	s.udpAccess.Lock()
+	s.udpAssociations[ip] = append(s.udpAssociations[ip], association)
+	s.udpAccess.Unlock()
+
+	return func() {
+		s.udpAccess.Lock()
+		defer s.udpAccess.Unlock()
+
+		associations := s.udpAssociations[ip]
+		for idx, a := range associations {
+			if a == association {
+				associations = append(associations[:idx], associations[idx+1:]...)
+				break
+			}
+		}
+		if len(associations) == 0 {
+			delete(s.udpAssociations, ip)
+		} else {
+			s.udpAssociations[ip] = associations
+		}
+	}
+}
+
+// UDPUsername returns the username of the UDP association from the given source. Associations declaring the port of
+// the source take precedence. Otherwise the username is taken from the associations of the same IP, only if they all
+// have the same username, as it is unknown which one the source belongs to. It returns an empty string if there is
+// no such association.
+// Private: Visible for testing.
+func (s *Server) UDPUsername(source net.Destination) string {
+	if !source.IsValid() {
+		return ""
+	}
+	s.udpAccess.Lock()
+	defer s.udpAccess.Unlock()
+
+	associations := s.udpAssociations[source.Address.String()]
+	for _, a := range associations {
+		if a.port != 0 && a.port == source.Port {
+			return a.username
+		}
+	}
+	username := ""
+	for _, a := range associations {
+		if len(username) > 0 && a.username != username {
+			log.Info("Socks|Server: Multiple users have UDP associations from ", source.Address, ", leaving the user unknown.")
+			return ""
+		}
+		username = a.username
+	}
+	return username
+}
+
 func (v *Server) transport(ctx context.Context, reader io.Reader, writer io.Writer) error {
 	ray := v.packetDispatcher.DispatchToOutbound(ctx)
 	input := ray.InboundInput()
@@ -153,6 +245,7 @@ func (v *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 func (v *Server) handleUDPPayload(ctx context.Context, conn internet.Connection) error {
 	source := proxy.SourceFromContext(ctx)
 	log.Info("Socks|Server: Client UDP connection from ", source)
+	ctx = contextWithUsername(ctx, v.UDPUsername(source))
 
 	reader := buf.NewReader(conn)
 	for {
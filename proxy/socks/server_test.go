@@ -0,0 +1,34 @@
+package socks_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/proxy/socks"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestUDPUsername(t *testing.T) {
+	assert := assert.On(t)
+
+	server, err := NewServer(app.ContextWithSpace(context.Background(), app.NewSpace()), &ServerConfig{})
+	assert.Error(err).IsNil()
+
+	client := net.IPAddress([]byte{10, 0, 0, 1})
+	removeAlice := server.AddUDPAssociation(net.TCPDestination(client, 40000), 50000, "alice")
+	assert.String(server.UDPUsername(net.UDPDestination(client, 50000))).Equals("alice")
+	assert.String(server.UDPUsername(net.UDPDestination(client, 50001))).Equals("alice")
+	assert.String(server.UDPUsername(net.UDPDestination(net.IPAddress([]byte{10, 0, 0, 2}), 50000))).Equals("")
+
+	// Another user behind the same IP, without declaring its port.
+	removeBob := server.AddUDPAssociation(net.TCPDestination(client, 40001), 0, "bob")
+	assert.String(server.UDPUsername(net.UDPDestination(client, 50000))).Equals("alice")
+	assert.String(server.UDPUsername(net.UDPDestination(client, 50001))).Equals("")
+
+	removeAlice()
+	assert.String(server.UDPUsername(net.UDPDestination(client, 50000))).Equals("bob")
+	removeBob()
+	assert.String(server.UDPUsername(net.UDPDestination(client, 50000))).Equals("")
+}
@@ -3,6 +3,7 @@ package inbound
 import (
 	"context"
 	"io"
+	"strings"
 	"sync"
 
 	"v2ray.com/core/app"
@@ -25,9 +26,12 @@ import (
 	"v2ray.com/core/transport/ray"
 )
 
+// userByEmail keeps the users by their emails. Emails are case insensitive.
 type userByEmail struct {
 	sync.RWMutex
-	cache           map[string]*protocol.User
+	cache map[string]*protocol.User
+	// detours are the users created by Get for detours, which are replaced by the users added with the same emails.
+	detours         map[string]*protocol.User
 	defaultLevel    uint32
 	defaultAlterIDs uint16
 }
@@ -35,24 +39,26 @@ type userByEmail struct {
 func NewUserByEmail(users []*protocol.User, config *DefaultConfig) *userByEmail {
 	cache := make(map[string]*protocol.User)
 	for _, user := range users {
-		cache[user.Email] = user
+		cache[strings.ToLower(user.Email)] = user
 	}
 	return &userByEmail{
 		cache:           cache,
+		detours:         make(map[string]*protocol.User),
 		defaultLevel:    config.Level,
 		defaultAlterIDs: uint16(config.AlterId),
 	}
 }
 
+// Get returns the user of the given email, and whether the user exists. If not, a user with a random ID is created for
+// detours.
 func (v *userByEmail) Get(email string) (*protocol.User, bool) {
-	var user *protocol.User
-	var found bool
+	key := strings.ToLower(email)
 	v.RLock()
-	user, found = v.cache[email]
+	user, found := v.get(key)
 	v.RUnlock()
 	if !found {
 		v.Lock()
-		user, found = v.cache[email]
+		user, found = v.get(key)
 		if !found {
 			account := &vmess.Account{
 				Id:      uuid.New().String(),
@@ -63,13 +69,100 @@ func (v *userByEmail) Get(email string) (*protocol.User, bool) {
 				Email:   email,
 				Account: serial.ToTypedMessage(account),
 			}
-			v.cache[email] = user
+			v.detours[key] = user
 		}
 		v.Unlock()
 	}
 	return user, found
 }
 
+func (v *userByEmail) get(key string) (*protocol.User, bool) {
+	if user, found := v.cache[key]; found {
+		return user, true
+	}
+	user, found := v.detours[key]
+	return user, found
+}
+
+// Add adds the given user, and returns false if a user with the same email exists. A user created by Get for detours
+// is replaced, and returned.
+func (v *userByEmail) Add(user *protocol.User) (*protocol.User, bool) {
+	key := strings.ToLower(user.Email)
+	v.Lock()
+	defer v.Unlock()
+
+	if _, found := v.cache[key]; found {
+		return nil, false
+	}
+	replaced := v.detours[key]
+	delete(v.detours, key)
+	v.cache[key] = user
+	return replaced, true
+}
+
+func (v *userByEmail) Remove(email string) {
+	key := strings.ToLower(email)
+	v.Lock()
+	delete(v.cache, key)
+	delete(v.detours, key)
+	v.Unlock()
+}
+
+type userSession struct {
+	cancel     context.CancelFunc
+	connection internet.Connection
+}
+
+// sessionsByEmail keeps track of active connections of each user. Emails are case insensitive.
+type sessionsByEmail struct {
+	sync.Mutex
+	sessions map[string]map[*userSession]bool
+}
+
+func (v *sessionsByEmail) add(email string, session *userSession) {
+	email = strings.ToLower(email)
+	v.Lock()
+	defer v.Unlock()
+
+	if v.sessions == nil {
+		v.sessions = make(map[string]map[*userSession]bool)
+	}
+	userSessions, found := v.sessions[email]
+	if !found {
+		userSessions = make(map[*userSession]bool)
+		v.sessions[email] = userSessions
+	}
+	userSessions[session] = true
+}
+
+func (v *sessionsByEmail) remove(email string, session *userSession) {
+	email = strings.ToLower(email)
+	v.Lock()
+	defer v.Unlock()
+
+	if userSessions, found := v.sessions[email]; found {
+		delete(userSessions, session)
+		if len(userSessions) == 0 {
+			delete(v.sessions, email)
+		}
+	}
+}
+
+// closeAll closes all sessions of the given user, and returns the number of closed sessions.
+func (v *sessionsByEmail) closeAll(email string) int {
+	email = strings.ToLower(email)
+	v.Lock()
+	userSessions := v.sessions[email]
+	delete(v.sessions, email)
+	v.Unlock()
+
+	for session := range userSessions {
+		session.cancel()
+		session.connection.Close()
+	}
+	return len(userSessions)
+}
+
 // Inbound connection handler that handles messages in VMess format.
 type VMessInboundHandler struct {
 	sync.RWMutex
@@ -77,6 +170,7 @@ type VMessInboundHandler struct {
 	inboundHandlerManager proxyman.InboundHandlerManager
 	clients               protocol.UserValidator
 	usersByEmail          *userByEmail
+	sessions              sessionsByEmail
 	detours               *DetourConfig
 }
 
@@ -129,6 +223,47 @@ func (v *VMessInboundHandler) GetUser(email string) *protocol.User {
 	return user
 }
 
+// AddUser implements proxy.UserManager.
+func (v *VMessInboundHandler) AddUser(ctx context.Context, user *protocol.User) error {
+	if len(user.Email) == 0 {
+		return errors.New("VMess|Inbound: User must have an email.")
+	}
+	if _, err := user.GetTypedAccount(); err != nil {
+		return errors.Base(err).Message("VMess|Inbound: Invalid account of user ", user.Email)
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	replaced, ok := v.usersByEmail.Add(user)
+	if !ok {
+		return errors.New("VMess|Inbound: User already exists: ", user.Email)
+	}
+	if replaced != nil {
+		v.clients.Remove(replaced.Email)
+	}
+	if err := v.clients.Add(user); err != nil {
+		v.usersByEmail.Remove(user.Email)
+		return err
+	}
+	return nil
+}
+
+// RemoveUser implements proxy.UserManager.
+func (v *VMessInboundHandler) RemoveUser(ctx context.Context, email string) error {
+	v.Lock()
+	defer v.Unlock()
+
+	if !v.clients.Remove(email) {
+		return errors.New("VMess|Inbound: User not found: ", email)
+	}
+	v.usersByEmail.Remove(email)
+
+	n := v.sessions.closeAll(email)
+	log.Info("VMess|Inbound: Closed ", n, " connections of user ", email)
+	return nil
+}
+
 func transferRequest(session *encoding.ServerSession, request *protocol.RequestHeader, input io.Reader, output ray.OutputStream) error {
 	defer output.Close()
 
@@ -194,6 +329,17 @@ func (v *VMessInboundHandler) Process(ctx context.Context, network net.Network,
 
 	connection.SetReusable(request.Option.Has(protocol.RequestOptionConnectionReuse))
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if email := request.User.Email; len(email) > 0 {
+		session := &userSession{
+			cancel:     cancel,
+			connection: connection,
+		}
+		v.sessions.add(email, session)
+		defer v.sessions.remove(email, session)
+	}
+
 	ctx = proxy.ContextWithDestination(ctx, request.Destination())
 	ctx = protocol.ContextWithUser(ctx, request.User)
 	ray := v.packetDispatcher.DispatchToOutbound(ctx)
@@ -0,0 +1,82 @@
+package inbound
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/uuid"
+	"v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/testing/assert"
+)
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (pipeConn) Reusable() bool {
+	return false
+}
+
+func (pipeConn) SetReusable(bool) {}
+
+func newVMessUser(email string) *protocol.User {
+	return &protocol.User{
+		Email: email,
+		Account: serial.ToTypedMessage(&vmess.Account{
+			Id:      uuid.New().String(),
+			AlterId: 2,
+		}),
+	}
+}
+
+func TestAddAndRemoveUser(t *testing.T) {
+	assert := assert.On(t)
+
+	ctx := app.ContextWithSpace(context.Background(), app.NewSpace())
+	handler, err := New(ctx, &Config{})
+	assert.Error(err).IsNil()
+
+	assert.Error(handler.AddUser(ctx, newVMessUser("Love@V2Ray.com"))).IsNil()
+	assert.Error(handler.AddUser(ctx, newVMessUser("love@v2ray.com"))).IsNotNil()
+	assert.Error(handler.AddUser(ctx, newVMessUser(""))).IsNotNil()
+	assert.String(handler.GetUser("LOVE@v2ray.com").Email).Equals("Love@V2Ray.com")
+
+	sessionCtx, cancel := context.WithCancel(context.Background())
+	client, server := net.Pipe()
+	defer client.Close()
+	handler.sessions.add("Love@V2Ray.com", &userSession{
+		cancel:     cancel,
+		connection: pipeConn{server},
+	})
+
+	assert.Error(handler.RemoveUser(ctx, "love@v2ray.com")).IsNil()
+	assert.Error(sessionCtx.Err()).IsNotNil()
+	_, err = client.Read(make([]byte, 1))
+	assert.Error(err).IsNotNil()
+
+	assert.Error(handler.RemoveUser(ctx, "love@v2ray.com")).IsNotNil()
+	assert.Error(handler.AddUser(ctx, newVMessUser("love@v2ray.com"))).IsNil()
+}
+
+func TestAddUserAfterDetour(t *testing.T) {
+	assert := assert.On(t)
+
+	ctx := app.ContextWithSpace(context.Background(), app.NewSpace())
+	handler, err := New(ctx, &Config{})
+	assert.Error(err).IsNil()
+
+	detour := handler.GetUser("love@v2ray.com")
+	assert.Pointer(handler.GetUser("love@v2ray.com")).Equals(detour)
+
+	user := newVMessUser("love@v2ray.com")
+	assert.Error(handler.AddUser(ctx, user)).IsNil()
+	assert.Pointer(handler.GetUser("love@v2ray.com")).Equals(user)
+	assert.Error(handler.AddUser(ctx, newVMessUser("love@v2ray.com"))).IsNotNil()
+
+	assert.Error(handler.RemoveUser(ctx, "love@v2ray.com")).IsNil()
+	assert.Error(handler.RemoveUser(ctx, "love@v2ray.com")).IsNotNil()
+}
@@ -6,6 +6,7 @@
 package vmess
 
 import (
+	"strings"
 	"sync"
 	"time"
 
@@ -162,3 +163,58 @@ func (v *TimedUserValidator) Get(userHash []byte) (*protocol.User, protocol.Time
 	}
 	return nil, 0, false
 }
+
+func (v *TimedUserValidator) Remove(email string) bool {
+	if len(email) == 0 {
+		return false
+	}
+	email = strings.ToLower(email)
+
+	v.Lock()
+	defer v.Unlock()
+
+	idx := -1
+	for i, user := range v.validUsers {
+		if strings.ToLower(user.Email) == email {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return false
+	}
+
+	for hash, pair := range v.userHash {
+		if pair.index == idx {
+			delete(v.userHash, hash)
+		}
+	}
+
+	// Moves the last user into the removed slot, so that only indices of one user need to be updated.
+	lastIdx := len(v.validUsers) - 1
+	v.validUsers[idx] = v.validUsers[lastIdx]
+	v.validUsers[lastIdx] = nil
+	v.validUsers = v.validUsers[:lastIdx]
+	for _, pair := range v.userHash {
+		if pair.index == lastIdx {
+			pair.index = idx
+		}
+	}
+
+	ids := v.ids[:0]
+	for _, entry := range v.ids {
+		if entry.userIdx == idx {
+			continue
+		}
+		if entry.userIdx == lastIdx {
+			entry.userIdx = idx
+		}
+		ids = append(ids, entry)
+	}
+	for i := len(ids); i < len(v.ids); i++ {
+		v.ids[i] = nil
+	}
+	v.ids = ids
+
+	return true
+}
@@ -0,0 +1,71 @@
+package vmess_test
+
+import (
+	"testing"
+	"time"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/uuid"
+	. "v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/testing/assert"
+)
+
+func newUser(email string) (*protocol.User, *InternalAccount) {
+	user := &protocol.User{
+		Email: email,
+		Account: serial.ToTypedMessage(&Account{
+			Id:      uuid.New().String(),
+			AlterId: 2,
+		}),
+	}
+	account, err := user.GetTypedAccount()
+	if err != nil {
+		panic(err)
+	}
+	return user, account.(*InternalAccount)
+}
+
+func userHash(id *protocol.ID) []byte {
+	hash := protocol.DefaultIDHash(id.Bytes())
+	hash.Write(protocol.Timestamp(time.Now().Unix()).Bytes(nil))
+	return hash.Sum(nil)
+}
+
+func TestUserValidatorRemove(t *testing.T) {
+	assert := assert.On(t)
+
+	validator := NewTimedUserValidator(protocol.DefaultIDHash)
+	defer validator.Release()
+
+	user1, account1 := newUser("a@v2ray.com")
+	user2, account2 := newUser("b@v2ray.com")
+	user3, account3 := newUser("c@v2ray.com")
+	assert.Error(validator.Add(user1)).IsNil()
+	assert.Error(validator.Add(user2)).IsNil()
+	assert.Error(validator.Add(user3)).IsNil()
+
+	assert.Bool(validator.Remove("A@v2ray.com")).IsTrue()
+	assert.Bool(validator.Remove("a@v2ray.com")).IsFalse()
+	assert.Bool(validator.Remove("")).IsFalse()
+
+	_, _, found := validator.Get(userHash(account1.ID))
+	assert.Bool(found).IsFalse()
+	_, _, found = validator.Get(userHash(account1.AlterIDs[0]))
+	assert.Bool(found).IsFalse()
+
+	user, _, found := validator.Get(userHash(account2.AlterIDs[1]))
+	assert.Bool(found).IsTrue()
+	assert.String(user.Email).Equals("b@v2ray.com")
+
+	user, _, found = validator.Get(userHash(account3.ID))
+	assert.Bool(found).IsTrue()
+	assert.String(user.Email).Equals("c@v2ray.com")
+
+	assert.Bool(validator.Remove("c@v2ray.com")).IsTrue()
+	_, _, found = validator.Get(userHash(account3.AlterIDs[0]))
+	assert.Bool(found).IsFalse()
+	user, _, found = validator.Get(userHash(account2.ID))
+	assert.Bool(found).IsTrue()
+	assert.String(user.Email).Equals("b@v2ray.com")
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/serial"
+)
+
+// Reload applies the given config to this running Point. Only the inbounds, outbounds, routing rules and DNS
+// settings that differ from the current config are re-created. GeoIP and GeoSite files that changed on disk are
+// reloaded as well. Handlers are matched by their tags, and connections on
+// unchanged handlers are not affected. Changes that can't be applied at runtime, such as transport settings or
+// untagged handlers, are logged and require a restart.
+//
+// All changes are attempted even if some of them fail. The first error is returned, and the current config is kept,
+// so that the failed changes are attempted again on the next reload.
+func (v *Point) Reload(config *Config) error {
+	r := &reloader{
+		ctx: v.ctx,
+	}
+
+	if !proto.Equal(v.config.Log, config.Log) {
+		r.handle(config.Log.Apply())
+	}
+	if !proto.Equal(v.config.Transport, config.Transport) {
+		log.Warning("Core: Transport settings changed. Restart is required to apply the change.")
+	}
+
+	ihm := proxyman.InboundHandlerManagerFromSpace(v.space)
+	r.reloadInbounds(ihm, v.config.Inbound, config.Inbound)
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(v.space)
+	r.reloadOutbounds(ohm, v.config.Outbound, config.Outbound)
+
+	r.reloadApps(v, v.config.App, config.App)
+	if rt := router.FromSpace(v.space); rt != nil {
+		reloaded, err := rt.ReloadGeoData()
+		r.handle(err)
+		if reloaded {
+			log.Info("Core: GeoIP and GeoSite files reloaded.")
+		}
+	}
+
+	if r.err != nil {
+		return errors.Base(r.err).Message("Core: Failed to reload config.")
+	}
+	v.config = config
+	log.Warning("Core: Config reloaded.")
+	return nil
+}
+
+type reloader struct {
+	ctx context.Context
+	err error
+}
+
+func (r *reloader) handle(err error) {
+	if err == nil {
+		return
+	}
+	log.Error("Core: ", err)
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// untaggedChanged returns true if untagged handlers in two lists differ.
+func untaggedChanged(current []proto.Message, next []proto.Message) bool {
+	if len(current) != len(next) {
+		return true
+	}
+	for idx := range current {
+		if !proto.Equal(current[idx], next[idx]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reloader) reloadInbounds(ihm proxyman.InboundHandlerManager, current []*proxyman.InboundHandlerConfig, next []*proxyman.InboundHandlerConfig) {
+	currentTagged := make(map[string]*proxyman.InboundHandlerConfig)
+	var currentUntagged, nextUntagged []proto.Message
+	for _, config := range current {
+		if len(config.Tag) > 0 {
+			currentTagged[config.Tag] = config
+		} else {
+			currentUntagged = append(currentUntagged, config)
+		}
+	}
+
+	nextTags := make(map[string]bool)
+	for _, config := range next {
+		if len(config.Tag) > 0 {
+			nextTags[config.Tag] = true
+		} else {
+			nextUntagged = append(nextUntagged, config)
+		}
+	}
+
+	// Removes handlers first, so that their ports can be reused by new ones. Handlers are looked up in the manager as
+	// well, as a previous reload may have failed after applying some of the changes.
+	for tag := range currentTagged {
+		if nextTags[tag] {
+			continue
+		}
+		if _, err := ihm.GetHandler(r.ctx, tag); err == nil {
+			log.Info("Core: Removing inbound ", tag)
+			r.handle(ihm.RemoveHandler(r.ctx, tag))
+		}
+	}
+
+	for _, config := range next {
+		if len(config.Tag) == 0 {
+			continue
+		}
+		currentConfig, found := currentTagged[config.Tag]
+		_, err := ihm.GetHandler(r.ctx, config.Tag)
+		switch {
+		case err != nil:
+			log.Info("Core: Adding inbound ", config.Tag)
+			r.handle(ihm.AddHandler(r.ctx, config))
+		case !found || !proto.Equal(currentConfig, config):
+			log.Info("Core: Replacing inbound ", config.Tag)
+			r.handle(ihm.ReplaceHandler(r.ctx, config))
+		}
+	}
+
+	if untaggedChanged(currentUntagged, nextUntagged) {
+		log.Warning("Core: Untagged inbounds changed. Restart is required to apply the change.")
+	}
+}
+
+// unexpiredOutbounds returns the configs in the given list that have not expired. Expired handlers are retired by
+// the outbound handler manager, or skipped when they are added.
+func unexpiredOutbounds(configs []*proxyman.OutboundHandlerConfig, now time.Time) []*proxyman.OutboundHandlerConfig {
+	unexpired := make([]*proxyman.OutboundHandlerConfig, 0, len(configs))
+	for _, config := range configs {
+		if !config.HasExpired(now) {
+			unexpired = append(unexpired, config)
+		}
+	}
+	return unexpired
+}
+
+func (r *reloader) reloadOutbounds(ohm proxyman.OutboundHandlerManager, current []*proxyman.OutboundHandlerConfig, next []*proxyman.OutboundHandlerConfig) {
+	now := time.Now()
+	for _, config := range next {
+		if config.HasExpired(now) {
+			log.Warning("Core: Skipping expired outbound [", config.Tag, "]")
+		}
+	}
+	current = unexpiredOutbounds(current, now)
+	next = unexpiredOutbounds(next, now)
+
+	currentTagged := make(map[string]*proxyman.OutboundHandlerConfig)
+	var currentUntagged, nextUntagged []proto.Message
+	for _, config := range current {
+		if len(config.Tag) > 0 {
+			currentTagged[config.Tag] = config
+		} else {
+			currentUntagged = append(currentUntagged, config)
+		}
+	}
+
+	nextTags := make(map[string]bool)
+	for _, config := range next {
+		if len(config.Tag) > 0 {
+			nextTags[config.Tag] = true
+		} else {
+			nextUntagged = append(nextUntagged, config)
+		}
+	}
+
+	// Removes handlers first, so that their ports can be reused by new ones. Handlers are looked up in the manager as
+	// well, as a previous reload may have failed after applying some of the changes.
+	for tag := range currentTagged {
+		if !nextTags[tag] && ohm.GetHandler(tag) != nil {
+			log.Info("Core: Removing outbound ", tag)
+			r.handle(ohm.RemoveHandler(r.ctx, tag))
+		}
+	}
+
+	for _, config := range next {
+		if len(config.Tag) == 0 {
+			continue
+		}
+		currentConfig, found := currentTagged[config.Tag]
+		switch {
+		case ohm.GetHandler(config.Tag) == nil:
+			log.Info("Core: Adding outbound ", config.Tag)
+			r.handle(ohm.AddHandler(r.ctx, config))
+		case !found || !proto.Equal(currentConfig, config):
+			log.Info("Core: Replacing outbound ", config.Tag)
+			r.handle(ohm.ReplaceHandler(r.ctx, config))
+		}
+	}
+
+	if untaggedChanged(currentUntagged, nextUntagged) {
+		log.Warning("Core: Untagged outbounds changed. Restart is required to apply the change.")
+	}
+}
+
+func appsByType(apps []*serial.TypedMessage) map[string]*serial.TypedMessage {
+	m := make(map[string]*serial.TypedMessage)
+	for _, app := range apps {
+		m[app.Type] = app
+	}
+	return m
+}
+
+func (r *reloader) reloadApps(point *Point, current []*serial.TypedMessage, next []*serial.TypedMessage) {
+	currentApps := appsByType(current)
+	nextApps := appsByType(next)
+
+	for appType, nextApp := range nextApps {
+		currentApp, found := currentApps[appType]
+		if found && proto.Equal(currentApp, nextApp) {
+			continue
+		}
+		settings, err := nextApp.GetInstance()
+		if err != nil {
+			r.handle(err)
+			continue
+		}
+		switch s := settings.(type) {
+		case *router.Config:
+			if rt := router.FromSpace(point.space); rt != nil {
+				log.Info("Core: Reloading routing rules.")
+				r.handle(rt.Reload(s))
+				continue
+			}
+		case *dns.Config:
+			if server, ok := dns.FromSpace(point.space).(dns.ReloadableServer); ok {
+				log.Info("Core: Reloading DNS.")
+				r.handle(server.Reload(s))
+				continue
+			}
+		}
+		log.Warning("Core: App settings changed: ", appType, ". Restart is required to apply the change.")
+	}
+
+	for appType := range currentApps {
+		if _, found := nextApps[appType]; !found {
+			log.Warning("Core: App removed: ", appType, ". Restart is required to apply the change.")
+		}
+	}
+}
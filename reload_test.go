@@ -0,0 +1,112 @@
+package core
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/proxyman"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/proxy/socks"
+	"v2ray.com/core/testing/assert"
+)
+
+func pickPort() v2net.Port {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	defer listener.Close()
+	return v2net.Port(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func socksInbound(port v2net.Port, timeout uint32) *proxyman.InboundHandlerConfig {
+	return &proxyman.InboundHandlerConfig{
+		Tag: "socks",
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortRange: v2net.SinglePortRange(port),
+			Listen:    v2net.NewIPOrDomain(v2net.LocalHostIP),
+		}),
+		ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+			AuthType: socks.AuthType_NO_AUTH,
+			Timeout:  timeout,
+		}),
+	}
+}
+
+func freedomOutbound(tag string) *proxyman.OutboundHandlerConfig {
+	return &proxyman.OutboundHandlerConfig{
+		Tag:           tag,
+		ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+	}
+}
+
+func TestReloadReplaceInboundOnSamePort(t *testing.T) {
+	assert := assert.On(t)
+
+	port := pickPort()
+	point, err := NewPoint(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{socksInbound(port, 0)},
+		Outbound: []*proxyman.OutboundHandlerConfig{freedomOutbound("direct")},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(point.Start()).IsNil()
+	defer point.Close()
+
+	ihm := proxyman.InboundHandlerManagerFromSpace(point.space)
+	oldHandler, err := ihm.GetHandler(point.ctx, "socks")
+	assert.Error(err).IsNil()
+
+	config := &Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{socksInbound(port, 30)},
+		Outbound: []*proxyman.OutboundHandlerConfig{freedomOutbound("direct")},
+	}
+	assert.Error(point.Reload(config)).IsNil()
+	assert.Pointer(point.config).Equals(config)
+
+	handler, err := ihm.GetHandler(point.ctx, "socks")
+	assert.Error(err).IsNil()
+	assert.Bool(handler != oldHandler).IsTrue()
+
+	conn, err := net.Dial("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	assert.Error(err).IsNil()
+	conn.Close()
+}
+
+func TestReloadFailureKeepsConfig(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Outbound: []*proxyman.OutboundHandlerConfig{freedomOutbound("direct")},
+	}
+	point, err := NewPoint(config)
+	assert.Error(err).IsNil()
+	assert.Error(point.Start()).IsNil()
+	defer point.Close()
+
+	next := &Config{
+		Outbound: []*proxyman.OutboundHandlerConfig{
+			freedomOutbound("direct"),
+			freedomOutbound("extra"),
+			{
+				Tag:           "broken",
+				ProxySettings: &serial.TypedMessage{Type: "v2ray.core.proxy.unknown.Config"},
+			},
+		},
+	}
+	assert.Error(point.Reload(next)).IsNotNil()
+	assert.Pointer(point.config).Equals(config)
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(point.space)
+	assert.Bool(ohm.GetHandler("extra") != nil).IsTrue()
+	assert.Bool(ohm.GetHandler("broken") == nil).IsTrue()
+
+	// The changes applied by the failed reload are applied again without errors.
+	next = proto.Clone(next).(*Config)
+	next.Outbound = next.Outbound[:2]
+	assert.Error(point.Reload(next)).IsNil()
+	assert.Pointer(point.config).Equals(next)
+	assert.Bool(ohm.GetHandler("extra") != nil).IsTrue()
+}
@@ -3,6 +3,7 @@ package tcp
 import (
 	"fmt"
 	"net"
+	"sync"
 
 	v2net "v2ray.com/core/common/net"
 )
@@ -11,6 +12,7 @@ type Server struct {
 	Port         v2net.Port
 	MsgProcessor func(msg []byte) []byte
 	SendFirst    []byte
+	access       sync.Mutex
 	accepting    bool
 	listener     *net.TCPListener
 }
@@ -26,17 +28,25 @@ func (server *Server) Start() (v2net.Destination, error) {
 	}
 	server.Port = v2net.Port(listener.Addr().(*net.TCPAddr).Port)
 	server.listener = listener
+	server.accepting = true
 	go server.acceptConnections(listener)
 	localAddr := listener.Addr().(*net.TCPAddr)
 	return v2net.TCPDestination(v2net.IPAddress(localAddr.IP), v2net.Port(localAddr.Port)), nil
 }
 
+func (server *Server) isAccepting() bool {
+	server.access.Lock()
+	defer server.access.Unlock()
+	return server.accepting
+}
+
 func (server *Server) acceptConnections(listener *net.TCPListener) {
-	server.accepting = true
-	for server.accepting {
+	for server.isAccepting() {
 		conn, err := listener.Accept()
 		if err != nil {
-			fmt.Printf("Failed accept TCP connection: %v\n", err)
+			if server.isAccepting() {
+				fmt.Printf("Failed accept TCP connection: %v\n", err)
+			}
 			continue
 		}
 
@@ -61,6 +71,8 @@ func (server *Server) handleConnection(conn net.Conn) {
 }
 
 func (v *Server) Close() {
+	v.access.Lock()
 	v.accepting = false
+	v.access.Unlock()
 	v.listener.Close()
 }
@@ -0,0 +1,27 @@
+package conf
+
+import (
+	"v2ray.com/core/app/api"
+	"v2ray.com/core/common/errors"
+)
+
+type ApiConfig struct {
+	Port   uint16   `json:"port"`
+	Listen *Address `json:"listen"`
+}
+
+func (v *ApiConfig) Build() (*api.Config, error) {
+	if v.Port == 0 {
+		return nil, errors.New("API port is not specified.")
+	}
+	config := &api.Config{
+		DirectPort: uint32(v.Port),
+	}
+	if v.Listen != nil {
+		if v.Listen.Family().IsDomain() {
+			return nil, errors.New("Unable to listen on domain address: ", v.Listen.Domain())
+		}
+		config.Listen = v.Listen.Build()
+	}
+	return config, nil
+}
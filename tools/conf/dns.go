@@ -1,32 +1,415 @@
 package conf
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
 	"v2ray.com/core/app/dns"
+	"v2ray.com/core/common/errors"
 	v2net "v2ray.com/core/common/net"
 )
 
+// NameServerConfig is a name server given as an address, such as 8.8.8.8, or as a URL, such as tcp://8.8.8.8,
+// tls://1.1.1.1:853 or https://1.1.1.1/dns-query. It can also be an object with the address, and the domains it
+// serves and the IPs it is expected to answer.
+type NameServerConfig struct {
+	Address     *Address
+	Type        dns.NameServer_Type
+	Port        uint32
+	Path        string
+	Domains     []*dns.Domain
+	ExpectedIPs []*dns.CIDR
+	extended    bool
+}
+
+func (v *NameServerConfig) UnmarshalJSON(data []byte) error {
+	var rawStr string
+	if err := json.Unmarshal(data, &rawStr); err == nil {
+		return v.parseAddress(rawStr)
+	}
+
+	var rawConfig struct {
+		Address     string   `json:"address"`
+		Port        uint16   `json:"port"`
+		Domains     []string `json:"domains"`
+		ExpectedIPs []string `json:"expectedIPs"`
+	}
+	if err := json.Unmarshal(data, &rawConfig); err != nil {
+		return err
+	}
+	if err := v.parseAddress(rawConfig.Address); err != nil {
+		return err
+	}
+	if rawConfig.Port > 0 {
+		v.Port = uint32(rawConfig.Port)
+	}
+	domains, err := parseDNSDomains(rawConfig.Domains)
+	if err != nil {
+		return err
+	}
+	v.Domains = domains
+	for _, ip := range rawConfig.ExpectedIPs {
+		cidr := parseIP(ip)
+		if cidr == nil {
+			return errors.New("Config: Invalid expected IP: ", ip)
+		}
+		v.ExpectedIPs = append(v.ExpectedIPs, &dns.CIDR{
+			Ip:     cidr.Ip,
+			Prefix: cidr.Prefix,
+		})
+	}
+	v.extended = true
+	return nil
+}
+
+// parseDNSDomains parses the given domains in the same way as routing rules, except that GeoSite is not supported.
+func parseDNSDomains(rawDomains []string) ([]*dns.Domain, error) {
+	domains := make([]*dns.Domain, 0, len(rawDomains))
+	for _, domain := range rawDomains {
+		if strings.HasPrefix(domain, "geosite:") {
+			return nil, errors.New("Config: GeoSite is not supported in DNS: ", domain)
+		}
+		rule := ParseDomainRule(domain)
+		domains = append(domains, &dns.Domain{
+			Type:  dns.Domain_Type(rule.Type),
+			Value: rule.Value,
+		})
+	}
+	return domains, nil
+}
+
+func (v *NameServerConfig) parseAddress(rawStr string) error {
+	if len(rawStr) == 0 {
+		return errors.New("Config: Empty name server address.")
+	}
+	if !strings.Contains(rawStr, "://") {
+		v.Address = &Address{v2net.ParseAddress(rawStr)}
+		return nil
+	}
+
+	u, err := url.Parse(rawStr)
+	if err != nil {
+		return errors.Base(err).Message("Config: Invalid name server: ", rawStr)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "udp":
+		v.Type = dns.NameServer_UDP
+	case "tcp":
+		v.Type = dns.NameServer_TCP
+	case "tls":
+		v.Type = dns.NameServer_TLS
+	case "https":
+		v.Type = dns.NameServer_HTTPS
+		v.Path = u.Path
+	default:
+		return errors.New("Config: Unknown name server scheme: ", u.Scheme)
+	}
+	if len(u.Hostname()) == 0 {
+		return errors.New("Config: Empty name server address: ", rawStr)
+	}
+	v.Address = &Address{v2net.ParseAddress(u.Hostname())}
+	if len(u.Port()) > 0 {
+		port, err := v2net.PortFromString(u.Port())
+		if err != nil {
+			return errors.Base(err).Message("Config: Invalid name server port: ", rawStr)
+		}
+		v.Port = uint32(port)
+	}
+	v.extended = true
+	return nil
+}
+
+func (v *NameServerConfig) Build() *dns.NameServer {
+	return &dns.NameServer{
+		Type: v.Type,
+		Address: &v2net.Endpoint{
+			Address: v.Address.Build(),
+			Port:    v.Port,
+		},
+		Path:       v.Path,
+		Domain:     v.Domains,
+		ExpectedIp: v.ExpectedIPs,
+	}
+}
+
+// FakeIPConfig is the fake IP pool of DNS, such as {"ipPool": "198.18.0.0/15", "poolSize": 65535}.
+type FakeIPConfig struct {
+	CIDR     *dns.CIDR
+	PoolSize uint32
+}
+
+func (v *FakeIPConfig) UnmarshalJSON(data []byte) error {
+	var rawConfig struct {
+		IPPool   string `json:"ipPool"`
+		PoolSize uint32 `json:"poolSize"`
+	}
+	if err := json.Unmarshal(data, &rawConfig); err != nil {
+		return err
+	}
+	cidr := parseIP(rawConfig.IPPool)
+	if cidr == nil {
+		return errors.New("Config: Invalid fake IP pool: ", rawConfig.IPPool)
+	}
+	v.CIDR = &dns.CIDR{
+		Ip:     cidr.Ip,
+		Prefix: cidr.Prefix,
+	}
+	v.PoolSize = rawConfig.PoolSize
+	return nil
+}
+
+func (v *FakeIPConfig) Build() *dns.FakeIPPool {
+	return &dns.FakeIPPool{
+		Cidr: v.CIDR,
+		Size: v.PoolSize,
+	}
+}
+
+// HostsConfig is the static hosts of DNS. Each domain maps to an IP, a list of IPs, or another domain as an
+// alias. Domains are matched exactly by default, or by prefixes in the same way as routing rules, such as
+// "domain:example.com" and "regexp:^ads?\\.". A wildcard such as "*.example.com" matches subdomains only. Keyword,
+// regex and wildcard patterns are tried in the order they appear in the config.
+type HostsConfig struct {
+	Hosts    map[string]*v2net.IPOrDomain
+	Mappings []*dns.HostMapping
+}
+
+// rawHost is a domain in static hosts with its raw addresses.
+type rawHost struct {
+	domain  string
+	address json.RawMessage
+}
+
+// decodeRawHosts returns the entries of the given JSON object in the order they appear.
+func decodeRawHosts(data []byte) ([]rawHost, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, errors.New("Config: DNS hosts must be an object.")
+	}
+	var hosts []rawHost
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		host := rawHost{domain: token.(string)}
+		if err := decoder.Decode(&host.address); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+func (v *HostsConfig) UnmarshalJSON(data []byte) error {
+	rawHosts, err := decodeRawHosts(data)
+	if err != nil {
+		return err
+	}
+
+	v.Hosts = make(map[string]*v2net.IPOrDomain)
+	for _, host := range rawHosts {
+		mapping, err := parseHostMapping(host.domain, host.address)
+		if err != nil {
+			return err
+		}
+		if mapping.Type == dns.Domain_Full && len(mapping.Ip) <= 1 {
+			if len(mapping.Ip) == 1 {
+				v.Hosts[mapping.Domain] = v2net.NewIPOrDomain(v2net.IPAddress(mapping.Ip[0]))
+			} else {
+				v.Hosts[mapping.Domain] = v2net.NewIPOrDomain(v2net.DomainAddress(mapping.ProxiedDomain))
+			}
+			continue
+		}
+		v.Mappings = append(v.Mappings, mapping)
+	}
+	return nil
+}
+
+func parseHostMapping(domain string, data []byte) (*dns.HostMapping, error) {
+	mapping := new(dns.HostMapping)
+	switch {
+	case strings.HasPrefix(domain, "geosite:"):
+		return nil, errors.New("Config: GeoSite is not supported in DNS hosts: ", domain)
+	case strings.HasPrefix(domain, "*."):
+		mapping.Type = dns.Domain_Regex
+		mapping.Domain = "^.+\\." + regexp.QuoteMeta(domain[2:]) + "$"
+	case strings.HasPrefix(domain, "full:"):
+		mapping.Type = dns.Domain_Full
+		mapping.Domain = domain[5:]
+	case strings.HasPrefix(domain, "domain:"), strings.HasPrefix(domain, "regexp:"), strings.HasPrefix(domain, "keyword:"):
+		rule := ParseDomainRule(domain)
+		mapping.Type = dns.Domain_Type(rule.Type)
+		mapping.Domain = rule.Value
+	default:
+		mapping.Type = dns.Domain_Full
+		mapping.Domain = domain
+	}
+
+	var addresses StringList
+	if err := json.Unmarshal(data, &addresses); err != nil {
+		return nil, errors.Base(err).Message("Config: Invalid address of DNS host: ", domain)
+	}
+	for _, rawAddress := range addresses {
+		address := v2net.ParseAddress(strings.TrimSpace(rawAddress))
+		if address.Family().IsDomain() {
+			if len(addresses) > 1 {
+				return nil, errors.New("Config: Alias of DNS host must be a single domain: ", domain)
+			}
+			mapping.ProxiedDomain = address.Domain()
+		} else {
+			mapping.Ip = append(mapping.Ip, address.IP())
+		}
+	}
+	if len(mapping.Ip) == 0 && len(mapping.ProxiedDomain) == 0 {
+		return nil, errors.New("Config: Empty address of DNS host: ", domain)
+	}
+	return mapping, nil
+}
+
+// DnsBlockRuleConfig blocks domains in DNS, such as {"domains": ["domain:ads.example.com"], "sinkhole": ["0.0.0.0"]}.
+// Without sinkhole IPs, the domains are answered as nonexistent.
+type DnsBlockRuleConfig struct {
+	Domains  []*dns.Domain
+	Sinkhole [][]byte
+}
+
+func (v *DnsBlockRuleConfig) UnmarshalJSON(data []byte) error {
+	var rawConfig struct {
+		Domains  []string `json:"domains"`
+		Sinkhole []string `json:"sinkhole"`
+	}
+	if err := json.Unmarshal(data, &rawConfig); err != nil {
+		return err
+	}
+	if len(rawConfig.Domains) == 0 {
+		return errors.New("Config: No domain in DNS block rule.")
+	}
+	domains, err := parseDNSDomains(rawConfig.Domains)
+	if err != nil {
+		return err
+	}
+	v.Domains = domains
+	for _, rawIP := range rawConfig.Sinkhole {
+		address := v2net.ParseAddress(rawIP)
+		if address.Family().IsDomain() {
+			return errors.New("Config: Invalid sinkhole IP: ", rawIP)
+		}
+		v.Sinkhole = append(v.Sinkhole, address.IP())
+	}
+	return nil
+}
+
+func (v *DnsBlockRuleConfig) Build() *dns.BlockRule {
+	return &dns.BlockRule{
+		Domain:     v.Domains,
+		SinkholeIp: v.Sinkhole,
+	}
+}
+
+// DnsCacheConfig is the cache settings of DNS. ServeStale, MinTTL and MaxTTL are in seconds.
+type DnsCacheConfig struct {
+	File       string `json:"file"`
+	Prefetch   bool   `json:"prefetch"`
+	ServeStale uint32 `json:"serveStale"`
+	MinTTL     uint32 `json:"minTTL"`
+	MaxTTL     uint32 `json:"maxTTL"`
+}
+
+func (v *DnsCacheConfig) Build() *dns.CacheConfig {
+	return &dns.CacheConfig{
+		File:       v.File,
+		Prefetch:   v.Prefetch,
+		ServeStale: v.ServeStale,
+		MinTtl:     v.MinTTL,
+		MaxTtl:     v.MaxTTL,
+	}
+}
+
+// DnsQueryStrategy is the query strategy of DNS: "UseIPv4", "UseIPv6", "PreferIPv4" or "PreferIPv6", case
+// insensitively. It is UseIPv4 by default.
+type DnsQueryStrategy dns.Config_QueryStrategy
+
+func (v *DnsQueryStrategy) UnmarshalJSON(data []byte) error {
+	var strategy string
+	if err := json.Unmarshal(data, &strategy); err != nil {
+		return errors.Base(err).Message("Config: Invalid DNS query strategy: ", string(data))
+	}
+	switch strings.ToLower(strategy) {
+	case "", "useipv4", "use_ip4":
+		*v = DnsQueryStrategy(dns.Config_USE_IP4)
+	case "useipv6", "use_ip6":
+		*v = DnsQueryStrategy(dns.Config_USE_IP6)
+	case "preferipv4", "prefer_ip4":
+		*v = DnsQueryStrategy(dns.Config_PREFER_IP4)
+	case "preferipv6", "prefer_ip6":
+		*v = DnsQueryStrategy(dns.Config_PREFER_IP6)
+	default:
+		return errors.New("Config: Unknown DNS query strategy: ", strategy)
+	}
+	return nil
+}
+
 type DnsConfig struct {
-	Servers []*Address          `json:"servers"`
-	Hosts   map[string]*Address `json:"hosts"`
+	Servers       []*NameServerConfig   `json:"servers"`
+	Hosts         *HostsConfig          `json:"hosts"`
+	HostsFiles    *StringList           `json:"hostsFiles"`
+	QueryStrategy DnsQueryStrategy      `json:"queryStrategy"`
+	FakeIP        *FakeIPConfig         `json:"fakeIP"`
+	Cache         *DnsCacheConfig       `json:"cache"`
+	Block         []*DnsBlockRuleConfig `json:"block"`
 }
 
 func (v *DnsConfig) Build() *dns.Config {
 	config := new(dns.Config)
-	config.NameServers = make([]*v2net.Endpoint, len(v.Servers))
-	for idx, server := range v.Servers {
-		config.NameServers[idx] = &v2net.Endpoint{
-			Network: v2net.Network_UDP,
-			Address: server.Build(),
-			Port:    53,
+
+	// Servers go to NameServer if any of them needs it, so that they are kept in the given order.
+	extended := false
+	for _, server := range v.Servers {
+		if server.extended {
+			extended = true
+		}
+	}
+	if extended {
+		config.NameServer = make([]*dns.NameServer, len(v.Servers))
+		for idx, server := range v.Servers {
+			config.NameServer[idx] = server.Build()
+		}
+	} else {
+		config.NameServers = make([]*v2net.Endpoint, len(v.Servers))
+		for idx, server := range v.Servers {
+			config.NameServers[idx] = &v2net.Endpoint{
+				Network: v2net.Network_UDP,
+				Address: server.Address.Build(),
+				Port:    53,
+			}
 		}
 	}
 
 	if v.Hosts != nil {
-		config.Hosts = make(map[string]*v2net.IPOrDomain)
-		for domain, ip := range v.Hosts {
-			config.Hosts[domain] = ip.Build()
-		}
+		config.Hosts = v.Hosts.Hosts
+		config.StaticHosts = v.Hosts.Mappings
 	}
+	if v.HostsFiles != nil {
+		config.HostsFile = []string(*v.HostsFiles)
+	}
+
+	if v.FakeIP != nil {
+		config.FakeIpPool = v.FakeIP.Build()
+	}
+	if v.Cache != nil {
+		config.Cache = v.Cache.Build()
+	}
+	for _, rule := range v.Block {
+		config.BlockRule = append(config.BlockRule, rule.Build())
+	}
+
+	config.QueryStrategy = dns.Config_QueryStrategy(v.QueryStrategy)
 
 	return config
 }
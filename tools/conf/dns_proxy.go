@@ -0,0 +1,24 @@
+package conf
+
+import (
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/dns"
+)
+
+type DnsServerConfig struct {
+	Address *Address `json:"address"`
+	Port    uint16   `json:"port"`
+}
+
+func (v *DnsServerConfig) Build() (*serial.TypedMessage, error) {
+	config := new(dns.Config)
+	if v.Address != nil {
+		config.Server = &v2net.Endpoint{
+			Network: v2net.Network_UDP,
+			Address: v.Address.Build(),
+			Port:    uint32(v.Port),
+		}
+	}
+	return serial.ToTypedMessage(config), nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"v2ray.com/core/app/dns"
 	v2net "v2ray.com/core/common/net"
 	"v2ray.com/core/testing/assert"
 	. "v2ray.com/core/tools/conf"
@@ -26,4 +27,208 @@ func TestDnsConfigParsing(t *testing.T) {
 	assert.Destination(dest).IsUDP()
 	assert.Address(dest.Address).Equals(v2net.IPAddress([]byte{8, 8, 8, 8}))
 	assert.Port(dest.Port).Equals(v2net.Port(53))
+	assert.Bool(config.QueryStrategy == dns.Config_USE_IP4).IsTrue()
+}
+
+func TestDnsQueryStrategy(t *testing.T) {
+	assert := assert.On(t)
+
+	for strategy, expected := range map[string]dns.Config_QueryStrategy{
+		"UseIPv4":    dns.Config_USE_IP4,
+		"UseIPv6":    dns.Config_USE_IP6,
+		"PreferIPv4": dns.Config_PREFER_IP4,
+		"preferIPv6": dns.Config_PREFER_IP6,
+	} {
+		jsonConfig := new(DnsConfig)
+		err := json.Unmarshal([]byte(`{"queryStrategy": "`+strategy+`"}`), jsonConfig)
+		assert.Error(err).IsNil()
+		if jsonConfig.Build().QueryStrategy != expected {
+			t.Error("unexpected query strategy for ", strategy)
+		}
+	}
+
+	jsonConfig := new(DnsConfig)
+	assert.Error(json.Unmarshal([]byte(`{"queryStrategy": "UseIPv5"}`), jsonConfig)).IsNotNil()
+}
+
+func TestDnsNameServerURLs(t *testing.T) {
+	assert := assert.On(t)
+
+	rawJson := `{
+    "servers": ["8.8.8.8", "tcp://8.8.4.4", "tls://1.1.1.1:853", "https://dns.google/dns-query", "localhost"]
+  }`
+
+	jsonConfig := new(DnsConfig)
+	err := json.Unmarshal([]byte(rawJson), jsonConfig)
+	assert.Error(err).IsNil()
+
+	config := jsonConfig.Build()
+	assert.Int(len(config.NameServers)).Equals(0)
+	assert.Int(len(config.NameServer)).Equals(5)
+
+	ns := config.NameServer[0]
+	assert.Bool(ns.Type == dns.NameServer_UDP).IsTrue()
+	assert.Address(ns.Address.Address.AsAddress()).Equals(v2net.IPAddress([]byte{8, 8, 8, 8}))
+	assert.Uint32(ns.Address.Port).Equals(0)
+
+	assert.Bool(config.NameServer[1].Type == dns.NameServer_TCP).IsTrue()
+
+	ns = config.NameServer[2]
+	assert.Bool(ns.Type == dns.NameServer_TLS).IsTrue()
+	assert.Uint32(ns.Address.Port).Equals(853)
+
+	ns = config.NameServer[3]
+	assert.Bool(ns.Type == dns.NameServer_HTTPS).IsTrue()
+	assert.Address(ns.Address.Address.AsAddress()).Equals(v2net.DomainAddress("dns.google"))
+	assert.String(ns.Path).Equals("/dns-query")
+
+	assert.String(config.NameServer[4].Address.Address.AsAddress().Domain()).Equals("localhost")
+
+	for _, server := range []string{`"ftp://8.8.8.8"`, `"tls://1.1.1.1:99999"`, `"https:///dns-query"`} {
+		assert.Error(json.Unmarshal([]byte(server), new(NameServerConfig))).IsNotNil()
+	}
+}
+
+func TestDnsNameServerDomains(t *testing.T) {
+	assert := assert.On(t)
+
+	rawJson := `{
+    "servers": [
+      {
+        "address": "10.0.0.53",
+        "port": 5353,
+        "domains": ["domain:corp.example.com", "full:intranet"],
+        "expectedIPs": ["10.0.0.0/8"]
+      },
+      "8.8.8.8"
+    ]
+  }`
+
+	jsonConfig := new(DnsConfig)
+	err := json.Unmarshal([]byte(rawJson), jsonConfig)
+	assert.Error(err).IsNil()
+
+	config := jsonConfig.Build()
+	assert.Int(len(config.NameServer)).Equals(2)
+
+	ns := config.NameServer[0]
+	assert.Bool(ns.Type == dns.NameServer_UDP).IsTrue()
+	assert.Uint32(ns.Address.Port).Equals(5353)
+	assert.Int(len(ns.Domain)).Equals(2)
+	assert.Bool(ns.Domain[0].Type == dns.Domain_Subdomain).IsTrue()
+	assert.String(ns.Domain[0].Value).Equals("corp.example.com")
+	assert.Bool(ns.Domain[1].Type == dns.Domain_Full).IsTrue()
+	assert.Int(len(ns.ExpectedIp)).Equals(1)
+	assert.Uint32(ns.ExpectedIp[0].Prefix).Equals(8)
+
+	assert.Int(len(config.NameServer[1].Domain)).Equals(0)
+
+	for _, server := range []string{`{"address": "10.0.0.53", "domains": ["geosite:cn"]}`, `{"address": "10.0.0.53", "expectedIPs": ["10.0.0.0/33"]}`, `{"port": 53}`} {
+		assert.Error(json.Unmarshal([]byte(server), new(NameServerConfig))).IsNotNil()
+	}
+}
+
+func TestDnsFakeIP(t *testing.T) {
+	assert := assert.On(t)
+
+	jsonConfig := new(DnsConfig)
+	err := json.Unmarshal([]byte(`{"fakeIP": {"ipPool": "198.18.0.0/15", "poolSize": 1024}}`), jsonConfig)
+	assert.Error(err).IsNil()
+
+	pool := jsonConfig.Build().FakeIpPool
+	assert.Bytes(pool.Cidr.Ip).Equals([]byte{198, 18, 0, 0})
+	assert.Uint32(pool.Cidr.Prefix).Equals(15)
+	assert.Uint32(pool.Size).Equals(1024)
+
+	assert.Error(json.Unmarshal([]byte(`{"fakeIP": {"ipPool": "198.18.0.0/33"}}`), new(DnsConfig))).IsNotNil()
+}
+
+func TestDnsCache(t *testing.T) {
+	assert := assert.On(t)
+
+	jsonConfig := new(DnsConfig)
+	err := json.Unmarshal([]byte(`{"cache": {"file": "dns.cache", "prefetch": true, "serveStale": 3600, "minTTL": 60, "maxTTL": 86400}}`), jsonConfig)
+	assert.Error(err).IsNil()
+
+	cache := jsonConfig.Build().Cache
+	assert.String(cache.File).Equals("dns.cache")
+	assert.Bool(cache.Prefetch).IsTrue()
+	assert.Uint32(cache.ServeStale).Equals(3600)
+	assert.Uint32(cache.MinTtl).Equals(60)
+	assert.Uint32(cache.MaxTtl).Equals(86400)
+}
+
+func TestDnsHosts(t *testing.T) {
+	assert := assert.On(t)
+
+	jsonConfig := new(DnsConfig)
+	err := json.Unmarshal([]byte(`{
+		"hosts": {
+			"v2ray.com": "10.0.0.1",
+			"www.v2ray.com": "v2ray.com",
+			"full:dual.v2ray.com": ["10.0.0.2", "2001:db8::2"],
+			"domain:corp.example.com": "10.0.1.1",
+			"*.cdn.example.com": "origin.example.net",
+			"keyword:ads": "0.0.0.0"
+		},
+		"hostsFiles": ["/etc/hosts"]
+	}`), jsonConfig)
+	assert.Error(err).IsNil()
+
+	config := jsonConfig.Build()
+	assert.Int(len(config.Hosts)).Equals(2)
+	assert.Address(config.Hosts["v2ray.com"].AsAddress()).Equals(v2net.ParseAddress("10.0.0.1"))
+	assert.Address(config.Hosts["www.v2ray.com"].AsAddress()).Equals(v2net.ParseAddress("v2ray.com"))
+	assert.Int(len(config.HostsFile)).Equals(1)
+	assert.String(config.HostsFile[0]).Equals("/etc/hosts")
+
+	// Mappings are kept in the order of the config.
+	assert.Int(len(config.StaticHosts)).Equals(4)
+	dual := config.StaticHosts[0]
+	assert.Bool(dual.Type == dns.Domain_Full).IsTrue()
+	assert.Int(len(dual.Ip)).Equals(2)
+	subdomain := config.StaticHosts[1]
+	assert.Bool(subdomain.Type == dns.Domain_Subdomain).IsTrue()
+	assert.String(subdomain.Domain).Equals("corp.example.com")
+	assert.Bytes(subdomain.Ip[0]).Equals([]byte{10, 0, 1, 1})
+	wildcard := config.StaticHosts[2]
+	assert.Bool(wildcard.Type == dns.Domain_Regex).IsTrue()
+	assert.String(wildcard.Domain).Equals(`^.+\.cdn\.example\.com$`)
+	assert.String(wildcard.ProxiedDomain).Equals("origin.example.net")
+	keyword := config.StaticHosts[3]
+	assert.Bool(keyword.Type == dns.Domain_Plain).IsTrue()
+	assert.String(keyword.Domain).Equals("ads")
+
+	assert.Error(json.Unmarshal([]byte(`{"hosts": {"geosite:cn": "10.0.0.1"}}`), new(DnsConfig))).IsNotNil()
+	assert.Error(json.Unmarshal([]byte(`{"hosts": {"v2ray.com": ["v2ray.com", "10.0.0.1"]}}`), new(DnsConfig))).IsNotNil()
+	assert.Error(json.Unmarshal([]byte(`{"hosts": {"v2ray.com": []}}`), new(DnsConfig))).IsNotNil()
+	assert.Error(json.Unmarshal([]byte(`{"hosts": ["v2ray.com"]}`), new(DnsConfig))).IsNotNil()
+}
+
+func TestDnsBlockRules(t *testing.T) {
+	assert := assert.On(t)
+
+	jsonConfig := new(DnsConfig)
+	err := json.Unmarshal([]byte(`{
+		"block": [
+			{"domains": ["domain:malware.example.com", "regexp:^c2\\d+\\."]},
+			{"domains": ["keyword:ads"], "sinkhole": ["0.0.0.0", "::"]}
+		]
+	}`), jsonConfig)
+	assert.Error(err).IsNil()
+
+	rules := jsonConfig.Build().BlockRule
+	assert.Int(len(rules)).Equals(2)
+	assert.Int(len(rules[0].Domain)).Equals(2)
+	assert.Bool(rules[0].Domain[0].Type == dns.Domain_Subdomain).IsTrue()
+	assert.String(rules[0].Domain[0].Value).Equals("malware.example.com")
+	assert.Bool(rules[0].Domain[1].Type == dns.Domain_Regex).IsTrue()
+	assert.Int(len(rules[0].SinkholeIp)).Equals(0)
+	assert.Bool(rules[1].Domain[0].Type == dns.Domain_Plain).IsTrue()
+	assert.Int(len(rules[1].SinkholeIp)).Equals(2)
+	assert.Bytes(rules[1].SinkholeIp[0]).Equals([]byte{0, 0, 0, 0})
+
+	assert.Error(json.Unmarshal([]byte(`{"block": [{"domains": []}]}`), new(DnsConfig))).IsNotNil()
+	assert.Error(json.Unmarshal([]byte(`{"block": [{"domains": ["geosite:cn"]}]}`), new(DnsConfig))).IsNotNil()
+	assert.Error(json.Unmarshal([]byte(`{"block": [{"domains": ["v2ray.com"], "sinkhole": ["v2ray.com"]}]}`), new(DnsConfig))).IsNotNil()
 }
@@ -10,6 +10,7 @@ type LogConfig struct {
 	AccessLog string `json:"access"`
 	ErrorLog  string `json:"error"`
 	LogLevel  string `json:"loglevel"`
+	DNSLog    string `json:"dns"`
 }
 
 func (v *LogConfig) Build() *log.Config {
@@ -25,6 +26,10 @@ func (v *LogConfig) Build() *log.Config {
 		config.AccessLogPath = v.AccessLog
 		config.AccessLogType = log.LogType_File
 	}
+	if len(v.DNSLog) > 0 {
+		config.DnsLogPath = v.DNSLog
+		config.DnsLogType = log.LogType_File
+	}
 	if len(v.ErrorLog) > 0 {
 		config.ErrorLogPath = v.ErrorLog
 		config.ErrorLogType = log.LogType_File
@@ -41,6 +46,7 @@ func (v *LogConfig) Build() *log.Config {
 	case "none":
 		config.ErrorLogType = log.LogType_None
 		config.AccessLogType = log.LogType_None
+		config.DnsLogType = log.LogType_None
 	default:
 		config.ErrorLogLevel = log.LogLevel_Warning
 	}
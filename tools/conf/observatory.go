@@ -0,0 +1,30 @@
+package conf
+
+import (
+	"v2ray.com/core/app/observatory"
+	"v2ray.com/core/common/errors"
+)
+
+type ObservatoryConfig struct {
+	SubjectSelector StringList `json:"subjectSelector"`
+	ProbeAddress    *Address   `json:"probeAddress"`
+	ProbePort       uint16     `json:"probePort"`
+	ProbeInterval   uint32     `json:"probeInterval"`
+	ProbeTimeout    uint32     `json:"probeTimeout"`
+}
+
+func (v *ObservatoryConfig) Build() (*observatory.Config, error) {
+	if len(v.SubjectSelector) == 0 {
+		return nil, errors.New("Observatory: Empty subject selector.")
+	}
+	if v.ProbeAddress == nil {
+		return nil, errors.New("Observatory: Probe address is not specified.")
+	}
+	return &observatory.Config{
+		SubjectSelector: []string(v.SubjectSelector),
+		ProbeAddress:    v.ProbeAddress.Build(),
+		ProbePort:       uint32(v.ProbePort),
+		ProbeInterval:   v.ProbeInterval,
+		ProbeTimeout:    v.ProbeTimeout,
+	}, nil
+}
@@ -2,6 +2,7 @@ package conf
 
 import (
 	"encoding/json"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,6 +18,42 @@ import (
 type RouterRulesConfig struct {
 	RuleList       []json.RawMessage `json:"rules"`
 	DomainStrategy string            `json:"domainStrategy"`
+	Balancers      []*BalancerConfig `json:"balancers"`
+	GeoIPFile      string            `json:"geoipFile"`
+	GeoSiteFile    string            `json:"geositeFile"`
+}
+
+type BalancerConfig struct {
+	Tag      string     `json:"tag"`
+	Selector StringList `json:"selector"`
+	Strategy string     `json:"strategy"`
+}
+
+func (v *BalancerConfig) Build() (*router.BalancingRule, error) {
+	if len(v.Tag) == 0 {
+		return nil, errors.New("Router: Balancer tag is not specified.")
+	}
+	if len(v.Selector) == 0 {
+		return nil, errors.New("Router: Empty selector list in balancer: ", v.Tag)
+	}
+
+	rule := &router.BalancingRule{
+		Tag:              v.Tag,
+		OutboundSelector: []string(v.Selector),
+	}
+	switch strings.ToLower(v.Strategy) {
+	case "", "random":
+		rule.Strategy = router.BalancingRule_Random
+	case "roundrobin":
+		rule.Strategy = router.BalancingRule_RoundRobin
+	case "leastconn":
+		rule.Strategy = router.BalancingRule_LeastConn
+	case "leastlatency":
+		rule.Strategy = router.BalancingRule_LeastLatency
+	default:
+		return nil, errors.New("Router: Unknown balancing strategy: ", v.Strategy)
+	}
+	return rule, nil
 }
 
 type RouterConfig struct {
@@ -31,6 +68,8 @@ func (v *RouterConfig) Build() (*router.Config, error) {
 
 	settings := v.Settings
 	config.DomainStrategy = router.Config_AsIs
+	config.GeoipFile = settings.GeoIPFile
+	config.GeositeFile = settings.GeoSiteFile
 	config.Rule = make([]*router.RoutingRule, len(settings.RuleList))
 	domainStrategy := strings.ToLower(settings.DomainStrategy)
 	if domainStrategy == "alwaysip" {
@@ -42,12 +81,21 @@ func (v *RouterConfig) Build() (*router.Config, error) {
 		rule := ParseRule(rawRule)
 		config.Rule[idx] = rule
 	}
+	for _, balancer := range settings.Balancers {
+		rule, err := balancer.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.BalancingRule = append(config.BalancingRule, rule)
+	}
 	return config, nil
 }
 
 type RouterRule struct {
 	Type        string `json:"type"`
 	OutboundTag string `json:"outboundTag"`
+	BalancerTag string `json:"balancerTag"`
+	RuleTag     string `json:"ruleTag"`
 }
 
 func parseIP(s string) *router.CIDR {
@@ -101,16 +149,65 @@ func parseIP(s string) *router.CIDR {
 	}
 }
 
+// ParseDomainRule parses a domain in routing rules or GeoSite lists. The type of the domain is given by its prefix, and
+// domains without a known prefix are keywords.
+func ParseDomainRule(domain string) *router.Domain {
+	domainRule := new(router.Domain)
+	switch {
+	case strings.HasPrefix(domain, "regexp:"):
+		domainRule.Type = router.Domain_Regex
+		domainRule.Value = domain[7:]
+	case strings.HasPrefix(domain, "domain:"):
+		domainRule.Type = router.Domain_Subdomain
+		domainRule.Value = domain[7:]
+	case strings.HasPrefix(domain, "full:"):
+		domainRule.Type = router.Domain_Full
+		domainRule.Value = domain[5:]
+	case strings.HasPrefix(domain, "keyword:"):
+		domainRule.Type = router.Domain_Plain
+		domainRule.Value = domain[8:]
+	default:
+		domainRule.Type = router.Domain_Plain
+		domainRule.Value = domain
+	}
+	return domainRule
+}
+
+// parseUserLevelRange parses a user level, such as "1", or an inclusive range of user levels, such as "1-3".
+func parseUserLevelRange(s string) (*router.UserLevelRange, error) {
+	parts := strings.SplitN(s, "-", 2)
+	from, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 32)
+	if err != nil {
+		return nil, errors.Base(err).Message("Router: Invalid user level: ", s)
+	}
+	to := from
+	if len(parts) == 2 {
+		to, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 32)
+		if err != nil {
+			return nil, errors.Base(err).Message("Router: Invalid user level: ", s)
+		}
+	}
+	if from > to {
+		return nil, errors.New("Router: Invalid user level range: ", s)
+	}
+	return &router.UserLevelRange{
+		From: uint32(from),
+		To:   uint32(to),
+	}, nil
+}
+
 func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	type RawFieldRule struct {
 		RouterRule
-		Domain     *StringList  `json:"domain"`
-		IP         *StringList  `json:"ip"`
-		Port       *PortRange   `json:"port"`
-		Network    *NetworkList `json:"network"`
-		SourceIP   *StringList  `json:"source"`
-		User       *StringList  `json:"user"`
-		InboundTag *StringList  `json:"inboundTag"`
+		Domain     *StringList       `json:"domain"`
+		IP         *StringList       `json:"ip"`
+		Port       *PortRange        `json:"port"`
+		Network    *NetworkList      `json:"network"`
+		SourceIP   *StringList       `json:"source"`
+		User       *StringList       `json:"user"`
+		InboundTag *StringList       `json:"inboundTag"`
+		UserLevel  *StringList       `json:"userLevel"`
+		Attributes map[string]string `json:"attrs"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -120,23 +217,25 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 
 	rule := new(router.RoutingRule)
 	rule.Tag = rawFieldRule.OutboundTag
+	rule.BalancingTag = rawFieldRule.BalancerTag
+	rule.RuleTag = rawFieldRule.RuleTag
 
 	if rawFieldRule.Domain != nil {
 		for _, domain := range *rawFieldRule.Domain {
-			domainRule := new(router.Domain)
-			if strings.HasPrefix(domain, "regexp:") {
-				domainRule.Type = router.Domain_Regex
-				domainRule.Value = domain[7:]
-			} else {
-				domainRule.Type = router.Domain_Plain
-				domainRule.Value = domain
+			if strings.HasPrefix(domain, "geosite:") {
+				rule.Geosite = append(rule.Geosite, domain[8:])
+				continue
 			}
-			rule.Domain = append(rule.Domain, domainRule)
+			rule.Domain = append(rule.Domain, ParseDomainRule(domain))
 		}
 	}
 
 	if rawFieldRule.IP != nil {
 		for _, ip := range *rawFieldRule.IP {
+			if strings.HasPrefix(ip, "geoip:") {
+				rule.Geoip = append(rule.Geoip, ip[6:])
+				continue
+			}
 			ipRule := parseIP(ip)
 			if ipRule != nil {
 				rule.Cidr = append(rule.Cidr, ipRule)
@@ -173,6 +272,36 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		}
 	}
 
+	if rawFieldRule.UserLevel != nil {
+		for _, s := range *rawFieldRule.UserLevel {
+			levelRange, err := parseUserLevelRange(s)
+			if err != nil {
+				return nil, err
+			}
+			rule.UserLevel = append(rule.UserLevel, levelRange)
+		}
+	}
+
+	// Attributes are sorted by key, so that the same config always builds the same rule.
+	keys := make([]string, 0, len(rawFieldRule.Attributes))
+	for key := range rawFieldRule.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := rawFieldRule.Attributes[key]
+		attribute := &router.Attribute{
+			Key:   key,
+			Type:  router.Attribute_Equal,
+			Value: value,
+		}
+		if strings.HasPrefix(value, "regexp:") {
+			attribute.Type = router.Attribute_Regex
+			attribute.Value = value[7:]
+		}
+		rule.Attribute = append(rule.Attribute, attribute)
+	}
+
 	return rule, nil
 }
 
@@ -224,8 +353,10 @@ func parseChinaIPRule(data []byte) (*router.RoutingRule, error) {
 		return nil, err
 	}
 	return &router.RoutingRule{
-		Tag:  rawRule.OutboundTag,
-		Cidr: chinaIPs.Ips,
+		Tag:          rawRule.OutboundTag,
+		BalancingTag: rawRule.BalancerTag,
+		RuleTag:      rawRule.RuleTag,
+		Cidr:         chinaIPs.Ips,
 	}, nil
 }
 
@@ -237,7 +368,9 @@ func parseChinaSitesRule(data []byte) (*router.RoutingRule, error) {
 		return nil, err
 	}
 	return &router.RoutingRule{
-		Tag:    rawRule.OutboundTag,
-		Domain: chinaSitesDomains,
+		Tag:          rawRule.OutboundTag,
+		BalancingTag: rawRule.BalancerTag,
+		RuleTag:      rawRule.RuleTag,
+		Domain:       chinaSitesDomains,
 	}, nil
 }
@@ -79,6 +79,88 @@ func TestDomainRule(t *testing.T) {
 	assert.Bool(cond.Apply(proxy.ContextWithDestination(context.Background(), v2net.TCPDestination(v2net.ParseAddress("www.acn.com"), 80)))).IsFalse()
 }
 
+func TestDomainRuleTypes(t *testing.T) {
+	assert := assert.On(t)
+
+	rule := ParseRule([]byte(`{
+    "type": "field",
+    "domain": [
+      "domain:v2ray.com",
+      "full:www.google.com",
+      "keyword:facebook"
+    ],
+    "outboundTag": "direct"
+  }`))
+	assert.Pointer(rule).IsNotNil()
+	cond, err := rule.BuildCondition()
+	assert.Error(err).IsNil()
+
+	match := func(domain string) bool {
+		return cond.Apply(proxy.ContextWithDestination(context.Background(), v2net.TCPDestination(v2net.DomainAddress(domain), 80)))
+	}
+	assert.Bool(match("v2ray.com")).IsTrue()
+	assert.Bool(match("www.v2ray.com")).IsTrue()
+	assert.Bool(match("notv2ray.com")).IsFalse()
+	assert.Bool(match("v2ray.com.evil")).IsFalse()
+	assert.Bool(match("www.google.com")).IsTrue()
+	assert.Bool(match("mail.google.com")).IsFalse()
+	assert.Bool(match("www.facebook.net")).IsTrue()
+}
+
+func TestGeoDataRule(t *testing.T) {
+	assert := assert.On(t)
+
+	rule := ParseRule([]byte(`{
+    "type": "field",
+    "domain": [
+      "geosite:google",
+      "v2ray.com"
+    ],
+    "ip": [
+      "geoip:cn",
+      "10.0.0.0/8"
+    ],
+    "outboundTag": "direct"
+  }`))
+	assert.Pointer(rule).IsNotNil()
+	assert.Int(len(rule.Geosite)).Equals(1)
+	assert.String(rule.Geosite[0]).Equals("google")
+	assert.Int(len(rule.Domain)).Equals(1)
+	assert.Int(len(rule.Geoip)).Equals(1)
+	assert.String(rule.Geoip[0]).Equals("cn")
+	assert.Int(len(rule.Cidr)).Equals(1)
+}
+
+func TestUserLevelAndAttributeRule(t *testing.T) {
+	assert := assert.On(t)
+
+	rule := ParseRule([]byte(`{
+    "type": "field",
+    "userLevel": ["1-3", "10"],
+    "attrs": {
+      "http.path": "regexp:^/api/",
+      "http.method": "GET"
+    },
+    "outboundTag": "tier1"
+  }`))
+	assert.Pointer(rule).IsNotNil()
+	assert.Int(len(rule.UserLevel)).Equals(2)
+	assert.Uint32(rule.UserLevel[0].From).Equals(1)
+	assert.Uint32(rule.UserLevel[0].To).Equals(3)
+	assert.Uint32(rule.UserLevel[1].From).Equals(10)
+	assert.Uint32(rule.UserLevel[1].To).Equals(10)
+	assert.Int(len(rule.Attribute)).Equals(2)
+	assert.String(rule.Attribute[0].Key).Equals("http.method")
+	assert.String(rule.Attribute[1].Key).Equals("http.path")
+	assert.String(rule.Attribute[1].Value).Equals("^/api/")
+
+	assert.Pointer(ParseRule([]byte(`{
+    "type": "field",
+    "userLevel": "3-1",
+    "outboundTag": "tier1"
+  }`))).IsNil()
+}
+
 func TestIPRule(t *testing.T) {
 	assert := assert.On(t)
 
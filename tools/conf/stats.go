@@ -0,0 +1,11 @@
+package conf
+
+import (
+	"v2ray.com/core/app/stats"
+)
+
+type StatsConfig struct{}
+
+func (v *StatsConfig) Build() *stats.Config {
+	return new(stats.Config)
+}
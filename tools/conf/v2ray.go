@@ -15,6 +15,7 @@ import (
 
 var (
 	inboundConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
+		"dns":           func() interface{} { return new(DnsServerConfig) },
 		"dokodemo-door": func() interface{} { return new(DokodemoConfig) },
 		"http":          func() interface{} { return new(HttpServerConfig) },
 		"shadowsocks":   func() interface{} { return new(ShadowsocksServerConfig) },
@@ -38,9 +39,36 @@ type InboundConnectionConfig struct {
 	StreamSetting *StreamConfig   `json:"streamSettings"`
 	Settings      json.RawMessage `json:"settings"`
 	AllowPassive  bool            `json:"allowPassive"`
+	Sniffing      *SniffingConfig `json:"sniffing"`
 	Tag           string          `json:"tag"`
 }
 
+type SniffingConfig struct {
+	Enabled      bool        `json:"enabled"`
+	DestOverride *StringList `json:"destOverride"`
+	RouteOnly    bool        `json:"routeOnly"`
+}
+
+func (v *SniffingConfig) Build() (*proxyman.SniffingConfig, error) {
+	config := &proxyman.SniffingConfig{
+		Enabled:   v.Enabled,
+		RouteOnly: v.RouteOnly,
+	}
+	if v.DestOverride == nil {
+		config.DestinationOverride = []string{"http", "tls"}
+		return config, nil
+	}
+	for _, protocol := range *v.DestOverride {
+		switch strings.ToLower(protocol) {
+		case "http", "tls":
+			config.DestinationOverride = append(config.DestinationOverride, strings.ToLower(protocol))
+		default:
+			return nil, errors.New("Unknown sniffing protocol: ", protocol)
+		}
+	}
+	return config, nil
+}
+
 func (v *InboundConnectionConfig) Build() (*proxyman.InboundHandlerConfig, error) {
 	receiverConfig := &proxyman.ReceiverConfig{
 		PortRange: &v2net.PortRange{
@@ -62,6 +90,13 @@ func (v *InboundConnectionConfig) Build() (*proxyman.InboundHandlerConfig, error
 		}
 		receiverConfig.StreamSettings = ts
 	}
+	if v.Sniffing != nil {
+		sc, err := v.Sniffing.Build()
+		if err != nil {
+			return nil, err
+		}
+		receiverConfig.SniffingSettings = sc
+	}
 
 	jsonConfig, err := inboundConfigLoader.LoadWithID(v.Settings, v.Protocol)
 	if err != nil {
@@ -89,6 +124,19 @@ type OutboundConnectionConfig struct {
 	ProxySettings *ProxyConfig    `json:"proxySettings"`
 	Settings      json.RawMessage `json:"settings"`
 	Tag           string          `json:"tag"`
+	MuxSettings   *MuxConfig      `json:"mux"`
+}
+
+type MuxConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Concurrency uint16 `json:"concurrency"`
+}
+
+func (v *MuxConfig) Build() *proxyman.MultiplexingConfig {
+	return &proxyman.MultiplexingConfig{
+		Enabled:     v.Enabled,
+		Concurrency: uint32(v.Concurrency),
+	}
 }
 
 func (v *OutboundConnectionConfig) Build() (*proxyman.OutboundHandlerConfig, error) {
@@ -116,6 +164,10 @@ func (v *OutboundConnectionConfig) Build() (*proxyman.OutboundHandlerConfig, err
 		senderSettings.ProxySettings = ps
 	}
 
+	if v.MuxSettings != nil {
+		senderSettings.MultiplexSettings = v.MuxSettings.Build()
+	}
+
 	rawConfig, err := outboundConfigLoader.LoadWithID(v.Settings, v.Protocol)
 	if err != nil {
 		return nil, errors.Base(err).Message("Failed to parse outbound config.")
@@ -174,6 +226,7 @@ type InboundDetourConfig struct {
 	Allocation    *InboundDetourAllocationConfig `json:"allocate"`
 	StreamSetting *StreamConfig                  `json:"streamSettings"`
 	AllowPassive  bool                           `json:"allowPassive"`
+	Sniffing      *SniffingConfig                `json:"sniffing"`
 }
 
 func (v *InboundDetourConfig) Build() (*proxyman.InboundHandlerConfig, error) {
@@ -206,6 +259,13 @@ func (v *InboundDetourConfig) Build() (*proxyman.InboundHandlerConfig, error) {
 		}
 		receiverSettings.StreamSettings = ss
 	}
+	if v.Sniffing != nil {
+		sc, err := v.Sniffing.Build()
+		if err != nil {
+			return nil, err
+		}
+		receiverSettings.SniffingSettings = sc
+	}
 
 	rawConfig, err := inboundConfigLoader.LoadWithID(v.Settings, v.Protocol)
 	if err != nil {
@@ -233,6 +293,9 @@ type OutboundDetourConfig struct {
 	Settings      json.RawMessage `json:"settings"`
 	StreamSetting *StreamConfig   `json:"streamSettings"`
 	ProxySettings *ProxyConfig    `json:"proxySettings"`
+	MuxSettings   *MuxConfig      `json:"mux"`
+	Expire        int64           `json:"expire"`
+	Comment       string          `json:"comment"`
 }
 
 func (v *OutboundDetourConfig) Build() (*proxyman.OutboundHandlerConfig, error) {
@@ -262,6 +325,10 @@ func (v *OutboundDetourConfig) Build() (*proxyman.OutboundHandlerConfig, error)
 		senderSettings.ProxySettings = ps
 	}
 
+	if v.MuxSettings != nil {
+		senderSettings.MultiplexSettings = v.MuxSettings.Build()
+	}
+
 	rawConfig, err := outboundConfigLoader.LoadWithID(v.Settings, v.Protocol)
 	if err != nil {
 		return nil, errors.Base(err).Message("Failed to parse to outbound detour config.")
@@ -275,6 +342,8 @@ func (v *OutboundDetourConfig) Build() (*proxyman.OutboundHandlerConfig, error)
 		SenderSettings: serial.ToTypedMessage(senderSettings),
 		Tag:            v.Tag,
 		ProxySettings:  ts,
+		Expire:         v.Expire,
+		Comment:        v.Comment,
 	}, nil
 }
 
@@ -283,6 +352,10 @@ type Config struct {
 	LogConfig       *LogConfig                `json:"log"`
 	RouterConfig    *RouterConfig             `json:"routing"`
 	DNSConfig       *DnsConfig                `json:"dns"`
+	ApiConfig       *ApiConfig                `json:"api"`
+	StatsConfig     *StatsConfig              `json:"stats"`
+	WebConfig       *WebConfig                `json:"web"`
+	Observatory     *ObservatoryConfig        `json:"observatory"`
 	InboundConfig   *InboundConnectionConfig  `json:"inbound"`
 	OutboundConfig  *OutboundConnectionConfig `json:"outbound"`
 	InboundDetours  []InboundDetourConfig     `json:"inboundDetour"`
@@ -317,6 +390,34 @@ func (v *Config) Build() (*core.Config, error) {
 		config.App = append(config.App, serial.ToTypedMessage(v.DNSConfig.Build()))
 	}
 
+	if v.ApiConfig != nil {
+		apiConfig, err := v.ApiConfig.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(apiConfig))
+	}
+
+	if v.StatsConfig != nil {
+		config.App = append(config.App, serial.ToTypedMessage(v.StatsConfig.Build()))
+	}
+
+	if v.WebConfig != nil {
+		webConfig, err := v.WebConfig.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(webConfig))
+	}
+
+	if v.Observatory != nil {
+		observatoryConfig, err := v.Observatory.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(observatoryConfig))
+	}
+
 	if v.InboundConfig == nil {
 		return nil, errors.New("No inbound config specified.")
 	}
@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"v2ray.com/core/app/web"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/serial"
+)
+
+type WebFileEntry struct {
+	Path      string `json:"path"`
+	File      string `json:"file"`
+	Directory string `json:"dir"`
+}
+
+func (v *WebFileEntry) Build() (*web.FileServer_Entry, error) {
+	entry := &web.FileServer_Entry{
+		Path: v.Path,
+	}
+	switch {
+	case len(v.File) > 0 && len(v.Directory) > 0:
+		return nil, errors.New("Both file and dir are specified for web path: ", v.Path)
+	case len(v.File) > 0:
+		entry.FileOrDir = &web.FileServer_Entry_File{File: v.File}
+	case len(v.Directory) > 0:
+		entry.FileOrDir = &web.FileServer_Entry_Directory{Directory: v.Directory}
+	default:
+		return nil, errors.New("Neither file nor dir is specified for web path: ", v.Path)
+	}
+	return entry, nil
+}
+
+type WebServerConfig struct {
+	Domain []string        `json:"domain"`
+	Files  []*WebFileEntry `json:"files"`
+}
+
+func (v *WebServerConfig) Build() (*web.Server, error) {
+	fileServer := new(web.FileServer)
+	for _, file := range v.Files {
+		entry, err := file.Build()
+		if err != nil {
+			return nil, err
+		}
+		fileServer.Entry = append(fileServer.Entry, entry)
+	}
+	return &web.Server{
+		Domain:   v.Domain,
+		Settings: serial.ToTypedMessage(fileServer),
+	}, nil
+}
+
+type WebConfig struct {
+	Port    uint16             `json:"port"`
+	Listen  *Address           `json:"listen"`
+	Servers []*WebServerConfig `json:"servers"`
+}
+
+func (v *WebConfig) Build() (*web.Config, error) {
+	config := &web.Config{
+		Port: uint32(v.Port),
+	}
+	if v.Listen != nil {
+		if v.Listen.Family().IsDomain() {
+			return nil, errors.New("Unable to listen on domain address: ", v.Listen.Domain())
+		}
+		config.Listen = v.Listen.Build()
+	}
+	for _, server := range v.Servers {
+		s, err := server.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Server = append(config.Server, s)
+	}
+	return config, nil
+}
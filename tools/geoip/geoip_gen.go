@@ -5,6 +5,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"v2ray.com/core/app/router"
 	"v2ray.com/core/common/errors"
@@ -38,30 +40,58 @@ func main() {
 	ips := &geoip.CountryIPRange{
 		Ips: make([]*router.CIDR, 0, 8192),
 	}
+	countries := make(map[string]*router.GeoIP)
+	geoIPList := &router.GeoIPList{
+		Version: time.Now().UTC().Format("20060102"),
+	}
 	for scanner.Scan() {
 		line := scanner.Text()
 		line = strings.TrimSpace(line)
 		parts := strings.Split(line, "|")
-		if len(parts) < 5 {
+		if len(parts) < 5 || len(parts[1]) != 2 {
 			continue
 		}
-		if strings.ToLower(parts[1]) != "cn" || strings.ToLower(parts[2]) != "ipv4" {
+		code := strings.ToLower(parts[1])
+		ip := parts[3]
+		ipBytes := net.ParseIP(ip)
+		if len(ipBytes) == 0 {
 			continue
 		}
-		ip := parts[3]
 		count, err := strconv.Atoi(parts[4])
 		if err != nil {
 			continue
 		}
-		mask := uint32(math.Floor(math.Log2(float64(count)) + 0.5))
-		ipBytes := net.ParseIP(ip)
-		if len(ipBytes) == 0 {
-			panic("Invalid IP " + ip)
+
+		var cidr *router.CIDR
+		switch strings.ToLower(parts[2]) {
+		case "ipv4":
+			mask := uint32(math.Floor(math.Log2(float64(count)) + 0.5))
+			cidr = &router.CIDR{
+				Ip:     []byte(ipBytes)[12:16],
+				Prefix: 32 - mask,
+			}
+			if code == "cn" {
+				ips.Ips = append(ips.Ips, cidr)
+			}
+		case "ipv6":
+			// The count of an IPv6 record is its prefix length.
+			cidr = &router.CIDR{
+				Ip:     []byte(ipBytes),
+				Prefix: uint32(count),
+			}
+		default:
+			continue
+		}
+
+		country, found := countries[code]
+		if !found {
+			country = &router.GeoIP{
+				CountryCode: code,
+			}
+			countries[code] = country
+			geoIPList.Entry = append(geoIPList.Entry, country)
 		}
-		ips.Ips = append(ips.Ips, &router.CIDR{
-			Ip:     []byte(ipBytes)[12:16],
-			Prefix: 32 - mask,
-		})
+		country.Cidr = append(country.Cidr, cidr)
 	}
 
 	ipbytes, err := proto.Marshal(ips)
@@ -78,6 +108,15 @@ func main() {
 	fmt.Fprintln(file, "package geoip")
 
 	fmt.Fprintln(file, "var ChinaIPs = "+formatArray(ipbytes))
+
+	// geoip.dat is loaded by the router at runtime. See router.GeoIPList for its format.
+	listBytes, err := proto.Marshal(geoIPList)
+	if err != nil {
+		log.Fatalf("Failed to marshal GeoIP list: %v", err)
+	}
+	if err := ioutil.WriteFile("geoip.dat", listBytes, 0644); err != nil {
+		log.Fatalf("Failed to write geoip.dat: %v", err)
+	}
 }
 
 func formatArray(a []byte) string {
@@ -0,0 +1,9 @@
+// Package geosite contains the generator of GeoSite files. Run "go generate" with a directory of domain lists to
+// build geosite.dat, which is loaded by the router at runtime.
+//
+// Each file in the directory is a list named after the file. Each line in a list is a domain in the same form as
+// domains in routing rules: "domain:", "full:", "keyword:" or "regexp:" followed by the value, or a plain value which
+// is a keyword. Empty lines and lines starting with "#" are ignored.
+package geosite
+
+//go:generate go run geosite_gen.go -data data -out geosite.dat
@@ -0,0 +1,81 @@
+// +build generate
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/tools/conf"
+
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	dataDir = flag.String("data", "data", "Directory of domain lists.")
+	outFile = flag.String("out", "geosite.dat", "Path of the output file.")
+)
+
+func loadList(path string) ([]*router.Domain, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var domains []*router.Domain
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, conf.ParseDomainRule(line))
+	}
+	return domains, scanner.Err()
+}
+
+func main() {
+	flag.Parse()
+
+	files, err := ioutil.ReadDir(*dataDir)
+	if err != nil {
+		log.Fatalf("Failed to read data directory: %v", err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	list := &router.GeoSiteList{
+		Version: time.Now().UTC().Format("20060102"),
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		domains, err := loadList(filepath.Join(*dataDir, file.Name()))
+		if err != nil {
+			log.Fatalf("Failed to load list %s: %v", file.Name(), err)
+		}
+		list.Entry = append(list.Entry, &router.GeoSite{
+			Name:   strings.ToLower(file.Name()),
+			Domain: domains,
+		})
+	}
+
+	data, err := proto.Marshal(list)
+	if err != nil {
+		log.Fatalf("Failed to marshal GeoSite list: %v", err)
+	}
+	if err := ioutil.WriteFile(*outFile, data, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *outFile, err)
+	}
+}
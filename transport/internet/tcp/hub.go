@@ -81,8 +81,15 @@ func ListenTCP(address v2net.Address, port v2net.Port, options internet.ListenOp
 	return l, nil
 }
 
+// isAccepting returns true if the listener is not closed.
+func (v *TCPListener) isAccepting() bool {
+	v.Lock()
+	defer v.Unlock()
+	return v.acccepting
+}
+
 func (v *TCPListener) Accept() (internet.Connection, error) {
-	for v.acccepting {
+	for v.isAccepting() {
 		select {
 		case connErr, open := <-v.awaitingConns:
 			if !open {
@@ -100,7 +107,7 @@ func (v *TCPListener) Accept() (internet.Connection, error) {
 }
 
 func (v *TCPListener) KeepAccepting() {
-	for v.acccepting {
+	for v.isAccepting() {
 		conn, err := v.listener.Accept()
 		v.Lock()
 		if !v.acccepting {
@@ -58,6 +58,7 @@ func ListenTCP(address v2net.Address, port v2net.Port, callback ConnectionHandle
 	hub := &TCPHub{
 		listener:     listener,
 		connCallback: callback,
+		accepting:    true,
 	}
 
 	go hub.start()
@@ -65,21 +66,29 @@ func ListenTCP(address v2net.Address, port v2net.Port, callback ConnectionHandle
 }
 
 func (v *TCPHub) Close() {
+	v.Lock()
 	v.accepting = false
+	v.Unlock()
 	v.listener.Close()
 }
 
+// isAccepting returns true if the hub is not closed.
+func (v *TCPHub) isAccepting() bool {
+	v.Lock()
+	defer v.Unlock()
+	return v.accepting
+}
+
 func (v *TCPHub) start() {
-	v.accepting = true
-	for v.accepting {
+	for v.isAccepting() {
 		var newConn Connection
 		err := retry.ExponentialBackoff(10, 200).On(func() error {
-			if !v.accepting {
+			if !v.isAccepting() {
 				return nil
 			}
 			conn, err := v.listener.Accept()
 			if err != nil {
-				if v.accepting {
+				if v.isAccepting() {
 					log.Warning("Internet|Listener: Failed to accept new TCP connection: ", err)
 				}
 				return err
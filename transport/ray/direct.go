@@ -54,6 +54,20 @@ func (v *directRay) AddInspector(inspector Inspector) {
 	v.Output.inspector.AddInspector(inspector)
 }
 
+func (v *directRay) AddInputInspector(inspector Inspector) {
+	if inspector == nil {
+		return
+	}
+	v.Input.inspector.AddInspector(inspector)
+}
+
+func (v *directRay) AddOutputInspector(inspector Inspector) {
+	if inspector == nil {
+		return
+	}
+	v.Output.inspector.AddInspector(inspector)
+}
+
 type Stream struct {
 	buffer    chan *buf.Buffer
 	ctx       context.Context
@@ -131,6 +145,10 @@ func (v *Stream) Write(data *buf.Buffer) (err error) {
 	case <-v.close:
 		return io.ErrClosedPipe
 	default:
+		// Inspectors must see the buffer before it is published, as the reader may release it right after. Lengths are
+		// counted only once the buffer is published.
+		length := data.Len()
+		v.inspector.Input(data)
 		select {
 		case <-v.ctx.Done():
 			return io.ErrClosedPipe
@@ -139,7 +157,7 @@ func (v *Stream) Write(data *buf.Buffer) (err error) {
 		case <-v.close:
 			return io.ErrClosedPipe
 		case v.buffer <- data:
-			v.inspector.Input(data)
+			v.inspector.InputLength(length)
 			return nil
 		}
 	}
@@ -2,7 +2,9 @@ package ray_test
 
 import (
 	"io"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"context"
 
@@ -48,3 +50,80 @@ func TestStreamClose(t *testing.T) {
 	_, err = stream.Read()
 	assert.Error(err).Equals(io.EOF)
 }
+
+type lengthInspector struct {
+	length int
+}
+
+func (i *lengthInspector) Input(b *buf.Buffer) {
+	i.length += b.Len()
+}
+
+func TestStreamInspectBeforeRead(t *testing.T) {
+	assert := assert.On(t)
+
+	ray := NewRay(context.Background())
+	inspector := new(lengthInspector)
+	ray.AddInputInspector(inspector)
+	stream := ray.InboundInput()
+
+	done := make(chan bool)
+	go func() {
+		for {
+			b, err := ray.OutboundInput().Read()
+			if err != nil {
+				close(done)
+				return
+			}
+			b.Clear()
+			b.Release()
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		b := buf.New()
+		b.AppendBytes('a', 'b')
+		assert.Error(stream.Write(b)).IsNil()
+	}
+	stream.Close()
+	<-done
+
+	assert.Int(inspector.length).Equals(2000)
+}
+
+type writtenLengthInspector struct {
+	lengthInspector
+	written int64
+}
+
+func (i *writtenLengthInspector) InputLength(length int) {
+	atomic.AddInt64(&i.written, int64(length))
+}
+
+func TestStreamCountWrittenOnly(t *testing.T) {
+	assert := assert.On(t)
+
+	ray := NewRay(context.Background())
+	inspector := new(writtenLengthInspector)
+	ray.AddInputInspector(inspector)
+	stream := ray.InboundInput()
+
+	// Nothing reads the stream, so the writes block once its buffer is full, until the stream is closed.
+	written := make(chan int64)
+	go func() {
+		var n int64
+		for {
+			b := buf.New()
+			b.AppendBytes('a', 'b')
+			if err := stream.Write(b); err != nil {
+				written <- n
+				return
+			}
+			n += 2
+		}
+	}()
+	time.Sleep(time.Millisecond * 100)
+	stream.Close()
+
+	assert.Int64(<-written).Equals(atomic.LoadInt64(&inspector.written))
+}
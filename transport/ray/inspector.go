@@ -10,6 +10,13 @@ type Inspector interface {
 	Input(*buf.Buffer)
 }
 
+// A LengthInspector is an Inspector that only needs the length of buffers, such as a traffic counter. Streams give it
+// the length of each buffer after the buffer is written successfully, instead of the buffer before it is written.
+type LengthInspector interface {
+	Inspector
+	InputLength(int)
+}
+
 type NoOpInspector struct{}
 
 func (NoOpInspector) Input(*buf.Buffer) {}
@@ -26,11 +33,26 @@ func (ic *InspectorChain) AddInspector(inspector Inspector) {
 	ic.chain = append(ic.chain, inspector)
 }
 
+// Input passes the given buffer to the inspectors in the chain, except LengthInspectors.
 func (ic *InspectorChain) Input(b *buf.Buffer) {
 	ic.RLock()
 	defer ic.RUnlock()
 
 	for _, inspector := range ic.chain {
-		inspector.Input(b)
+		if _, ok := inspector.(LengthInspector); !ok {
+			inspector.Input(b)
+		}
+	}
+}
+
+// InputLength passes the given length to the LengthInspectors in the chain.
+func (ic *InspectorChain) InputLength(length int) {
+	ic.RLock()
+	defer ic.RUnlock()
+
+	for _, inspector := range ic.chain {
+		if li, ok := inspector.(LengthInspector); ok {
+			li.InputLength(length)
+		}
 	}
 }
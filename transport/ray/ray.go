@@ -32,7 +32,12 @@ type InboundRay interface {
 type Ray interface {
 	InboundRay
 	OutboundRay
+	// AddInspector adds an Inspector to both directions of the Ray.
 	AddInspector(Inspector)
+	// AddInputInspector adds an Inspector to the stream from inbound to outbound.
+	AddInputInspector(Inspector)
+	// AddOutputInspector adds an Inspector to the stream from outbound to inbound.
+	AddOutputInspector(Inspector)
 }
 
 type RayStream interface {
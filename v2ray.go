@@ -2,18 +2,23 @@ package core
 
 import (
 	"context"
+	"time"
 
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/dns"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	v2net "v2ray.com/core/common/net"
 )
 
 // Point shell of V2Ray.
 type Point struct {
-	space app.Space
+	ctx    context.Context
+	space  app.Space
+	config *Config
 }
 
 // NewPoint returns a new Point server based on given configuration.
@@ -32,7 +37,9 @@ func NewPoint(pConfig *Config) (*Point, error) {
 	space := app.NewSpace()
 	ctx := app.ContextWithSpace(context.Background(), space)
 
+	vpoint.ctx = ctx
 	vpoint.space = space
+	vpoint.config = pConfig
 
 	outboundHandlerManager := proxyman.OutboundHandlerManagerFromSpace(space)
 	if outboundHandlerManager == nil {
@@ -100,6 +107,10 @@ func NewPoint(pConfig *Config) (*Point, error) {
 	}
 
 	for _, outbound := range pConfig.Outbound {
+		if outbound.HasExpired(time.Now()) {
+			log.Warning("Core: Skipping expired outbound [", outbound.Tag, "]")
+			continue
+		}
 		if err := outboundHandlerManager.AddHandler(ctx, outbound); err != nil {
 			return nil, err
 		}
@@ -113,18 +124,30 @@ func NewPoint(pConfig *Config) (*Point, error) {
 }
 
 func (v *Point) Close() {
-	ihm := proxyman.InboundHandlerManagerFromSpace(v.space)
-	ihm.Close()
+	v.space.Close()
 }
 
 // Start starts the Point server, and return any error during the process.
 // In the case of any errors, the state of the server is unpredicatable.
 func (v *Point) Start() error {
-	ihm := proxyman.InboundHandlerManagerFromSpace(v.space)
-	if err := ihm.Start(); err != nil {
+	if err := v.space.Start(); err != nil {
 		return err
 	}
 	log.Warning("V2Ray started.")
 
 	return nil
 }
+
+// TestRoute returns how the routing rules of this Point decide the outbound handler for the connection described in
+// the given request. The Point doesn't have to be started. If no rule matches, RuleIndex of the result is -1.
+func (v *Point) TestRoute(request *router.RouteRequest) (*router.RouteExplanation, error) {
+	r := router.FromSpace(v.space)
+	if r == nil {
+		return nil, errors.New("Core: Router is not configured.")
+	}
+	explanation, err := r.Explain(router.ContextWithRouteRequest(v.ctx, request))
+	if err != nil && err != router.ErrNoRuleApplicable {
+		return nil, err
+	}
+	return explanation, nil
+}
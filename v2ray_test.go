@@ -0,0 +1,35 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/serial"
+	_ "v2ray.com/core/main/distro/all"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestNewPointWithExpiredOutbound(t *testing.T) {
+	assert := assert.On(t)
+
+	point, err := NewPoint(&Config{
+		Outbound: []*proxyman.OutboundHandlerConfig{
+			{
+				Tag:           "expired",
+				ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+				Expire:        time.Now().Unix() - 1,
+			},
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(new(freedom.Config)),
+			},
+		},
+	})
+	assert.Error(err).IsNil()
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(point.space)
+	assert.Bool(ohm.GetHandler("expired") == nil).IsTrue()
+	assert.Bool(ohm.GetDefaultHandler() == ohm.GetHandler("direct")).IsTrue()
+}